@@ -1,7 +1,26 @@
 DROP TABLE IF EXISTS qa_history;
 DROP TABLE IF EXISTS ref_proponents;
+DROP TABLE IF EXISTS ref_message_versions;
 DROP TABLE IF EXISTS ref_messages;
 DROP TABLE IF EXISTS ref_threads;
+DROP TABLE IF EXISTS conviction_votes;
+DROP TABLE IF EXISTS conviction_delegations;
+DROP TABLE IF EXISTS indexer_checkpoints;
+DROP TABLE IF EXISTS ref_events;
+DROP TABLE IF EXISTS ref_spends;
+DROP TABLE IF EXISTS account_identities;
+DROP TABLE IF EXISTS dao_votes;
+DROP TABLE IF EXISTS dao_decisions;
+DROP TABLE IF EXISTS dao_members;
+DROP TABLE IF EXISTS wallet_challenges;
+DROP TABLE IF EXISTS ref_reminders;
+DROP TABLE IF EXISTS command_permissions;
+DROP TABLE IF EXISTS command_quotas;
+DROP TABLE IF EXISTS ai_usages;
+DROP TABLE IF EXISTS guild_networks;
+DROP TABLE IF EXISTS guilds;
+DROP TABLE IF EXISTS ai_token_usages;
+DROP TABLE IF EXISTS ai_model_prices;
 DROP TABLE IF EXISTS refs;
 DROP TABLE IF EXISTS network_rpcs;
 DROP TABLE IF EXISTS networks;
@@ -17,6 +36,102 @@ CREATE TABLE IF NOT EXISTS `settings` (
   UNIQUE KEY `name` (`name`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
+-- Per-command permission rules, reloaded by the bots every minute. Deny rules
+-- win; channel allow rules limit where a command works; role, user,
+-- dao_member and everyone allow rules decide who may use it, in place of the
+-- module's default role, which is required only when a command has none.
+CREATE TABLE IF NOT EXISTS `command_permissions` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `command` varchar(32) NOT NULL COMMENT 'Slash command name, feedback-review for the review buttons, feedback-reply for answering proponents, or * for all commands',
+  `effect` varchar(8) NOT NULL COMMENT 'allow or deny',
+  `subject` varchar(16) NOT NULL COMMENT 'role, user, channel, dao_member or everyone',
+  `subject_id` varchar(64) NOT NULL DEFAULT '' COMMENT 'Discord ID; empty for dao_member and everyone',
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  `note` varchar(255) DEFAULT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_command_permission_command` (`command`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Usage limits and cooldowns for AI-backed slash commands
+CREATE TABLE IF NOT EXISTS `command_quotas` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `command` varchar(32) NOT NULL COMMENT 'Slash command name, or * for all AI-backed commands',
+  `scope` varchar(16) NOT NULL COMMENT 'user, role, guild or global',
+  `subject_id` varchar(64) NOT NULL DEFAULT '' COMMENT 'Discord user or role ID; empty for every user',
+  `window_minutes` int unsigned NOT NULL DEFAULT '0' COMMENT 'Sliding window for max_requests and max_tokens',
+  `max_requests` int unsigned NOT NULL DEFAULT '0' COMMENT '0 for no limit',
+  `max_tokens` bigint unsigned NOT NULL DEFAULT '0' COMMENT 'AI tokens used, from ai_token_usages; 0 for no limit',
+  `cooldown_seconds` int unsigned NOT NULL DEFAULT '0',
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  `note` varchar(255) DEFAULT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_command_quota_command` (`command`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Ledger of accepted AI-backed command invocations
+CREATE TABLE IF NOT EXISTS `ai_usages` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `command` varchar(32) NOT NULL,
+  `user_id` varchar(64) NOT NULL DEFAULT '',
+  `guild_id` varchar(64) NOT NULL DEFAULT '',
+  `channel_id` varchar(64) NOT NULL DEFAULT '',
+  `estimated_tokens` int unsigned NOT NULL DEFAULT '0',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_ai_usage_command` (`command`),
+  KEY `idx_ai_usage_user` (`user_id`),
+  KEY `idx_ai_usage_guild` (`guild_id`),
+  KEY `idx_ai_usages_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Tokens used by each AI model request, priced from ai_model_prices when the
+-- request was made. Sum cost_usd by network_id and ref_id for what a
+-- referendum cost, or by feature to see where the money goes.
+CREATE TABLE IF NOT EXISTS `ai_token_usages` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `provider` varchar(32) NOT NULL,
+  `model` varchar(128) NOT NULL,
+  `feature` varchar(32) NOT NULL DEFAULT '' COMMENT 'question, summary, research, report, consensus or agents',
+  `network_id` tinyint unsigned NOT NULL DEFAULT '0' COMMENT '0 when not about a referendum',
+  `ref_id` int unsigned NOT NULL DEFAULT '0',
+  `command` varchar(32) NOT NULL DEFAULT '' COMMENT 'Slash command the request answered, if any',
+  `user_id` varchar(64) NOT NULL DEFAULT '' COMMENT 'Discord user who asked, if any',
+  `guild_id` varchar(64) NOT NULL DEFAULT '' COMMENT 'Discord guild asked in, if any',
+  `input_tokens` int unsigned NOT NULL DEFAULT '0' COMMENT 'Including cached tokens',
+  `output_tokens` int unsigned NOT NULL DEFAULT '0' COMMENT 'Including reasoning tokens',
+  `cached_tokens` int unsigned NOT NULL DEFAULT '0',
+  `cost_usd` decimal(12,6) DEFAULT NULL COMMENT 'NULL when no price matched the model',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_ai_token_usage_model` (`provider`, `model`),
+  KEY `idx_ai_token_usage_feature` (`feature`),
+  KEY `idx_ai_token_usage_ref` (`network_id`, `ref_id`),
+  KEY `idx_ai_token_usage_user` (`user_id`),
+  KEY `idx_ai_token_usage_guild` (`guild_id`),
+  KEY `idx_ai_token_usages_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Model list prices in US dollars per million tokens, reloaded every minute.
+-- model is a prefix of the model ID the API reports; the longest match wins
+-- and an empty provider applies to every provider.
+CREATE TABLE IF NOT EXISTS `ai_model_prices` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `provider` varchar(32) NOT NULL DEFAULT '',
+  `model` varchar(128) NOT NULL,
+  `input_usd_per_mtok` decimal(10,4) NOT NULL DEFAULT '0.0000',
+  `cached_input_usd_per_mtok` decimal(10,4) DEFAULT NULL COMMENT 'NULL bills cached tokens at the input price',
+  `output_usd_per_mtok` decimal(10,4) NOT NULL DEFAULT '0.0000',
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_ai_model_price` (`provider`, `model`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
 -- Networks
 CREATE TABLE IF NOT EXISTS `networks` (
   `id` tinyint unsigned NOT NULL,
@@ -26,16 +141,56 @@ CREATE TABLE IF NOT EXISTS `networks` (
   `discord_channel_id` varchar(64) DEFAULT NULL,
   `polkassembly_seed` varchar(512) DEFAULT NULL,
   `ss58_prefix` smallint unsigned DEFAULT NULL,
+  `referenda_pallet` varchar(64) NOT NULL DEFAULT 'Referenda',
   PRIMARY KEY (`id`),
   UNIQUE KEY `idx_network_name` (`name`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
+-- Discord guilds served by this deployment. While no guild is active the
+-- guild_id and role settings and the networks' channels and seeds are used.
+CREATE TABLE IF NOT EXISTS `guilds` (
+  `guild_id` varchar(64) NOT NULL,
+  `name` varchar(128) NOT NULL DEFAULT '',
+  `qa_role_id` varchar(64) NOT NULL DEFAULT '',
+  `feedback_role_id` varchar(64) NOT NULL DEFAULT '',
+  `feedback_review_channel_id` varchar(64) NOT NULL DEFAULT '' COMMENT 'Holds feedback for approval when set',
+  `polkassembly_intro` text DEFAULT NULL COMMENT 'Overrides the polkassembly_intro setting',
+  `polkassembly_outro` text DEFAULT NULL COMMENT 'Overrides the polkassembly_outro setting',
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`guild_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Each guild's referendum channel and Polkassembly account per network
+CREATE TABLE IF NOT EXISTS `guild_networks` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `guild_id` varchar(64) NOT NULL,
+  `network_id` tinyint unsigned NOT NULL,
+  `discord_channel_id` varchar(64) NOT NULL DEFAULT '',
+  `polkassembly_seed` varchar(512) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_guild_network` (`guild_id`, `network_id`),
+  KEY `idx_guild_networks_discord_channel_id` (`discord_channel_id`),
+  CONSTRAINT `fk_guild_network_guild` FOREIGN KEY (`guild_id`) REFERENCES `guilds` (`guild_id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_guild_network_network` FOREIGN KEY (`network_id`) REFERENCES `networks` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
 -- RPC endpoints
 CREATE TABLE IF NOT EXISTS `network_rpcs` (
   `id` int unsigned NOT NULL AUTO_INCREMENT,
   `network_id` tinyint unsigned NOT NULL,
   `url` varchar(256) NOT NULL,
+  `role` varchar(16) NOT NULL DEFAULT 'governance' COMMENT 'governance (referenda chain) or identity (People chain)',
   `active` tinyint(1) DEFAULT '1',
+  `in_use` tinyint(1) NOT NULL DEFAULT '0',
+  `healthy` tinyint(1) DEFAULT NULL,
+  `latency_ms` int unsigned DEFAULT NULL,
+  `best_block` bigint unsigned DEFAULT NULL,
+  `block_lag` bigint unsigned DEFAULT NULL,
+  `failures` int unsigned NOT NULL DEFAULT '0',
+  `last_error` varchar(512) DEFAULT NULL,
+  `last_checked_at` datetime DEFAULT NULL,
   PRIMARY KEY (`id`),
   KEY `idx_rpc_network` (`network_id`),
   CONSTRAINT `fk_rpc_network` FOREIGN KEY (`network_id`) REFERENCES `networks` (`id`)
@@ -56,6 +211,7 @@ CREATE TABLE IF NOT EXISTS `refs` (
   `submitted_at` timestamp NULL DEFAULT NULL,
   `decision_start` bigint unsigned DEFAULT '0',
   `decision_end` bigint unsigned DEFAULT '0',
+  `decision_end_at` datetime DEFAULT NULL COMMENT 'estimated time of decision_end',
   `confirm_start` bigint unsigned DEFAULT '0',
   `confirm_end` bigint unsigned DEFAULT '0',
   `approved` tinyint(1) DEFAULT '0',
@@ -67,6 +223,8 @@ CREATE TABLE IF NOT EXISTS `refs` (
   `electorate` varchar(64) DEFAULT NULL,
   `preimage_hash` varchar(128) DEFAULT NULL,
   `preimage_len` int unsigned DEFAULT NULL,
+  `decoded_call` longtext DEFAULT NULL COMMENT 'JSON decoded proposal call tree',
+  `pass_projection` text DEFAULT NULL COMMENT 'JSON track curve evaluation for ongoing referenda',
   `decision_deposit_who` varchar(128) DEFAULT NULL,
   `decision_deposit_amount` varchar(64) DEFAULT NULL,
   `submission_deposit_who` varchar(128) DEFAULT NULL,
@@ -89,6 +247,7 @@ CREATE TABLE IF NOT EXISTS `refs` (
 CREATE TABLE IF NOT EXISTS `ref_threads` (
   `id` bigint unsigned NOT NULL AUTO_INCREMENT,
   `thread_id` varchar(64) NOT NULL,
+  `guild_id` varchar(64) NOT NULL DEFAULT '',
   `ref_db_id` bigint unsigned NOT NULL,
   `network_id` tinyint unsigned NOT NULL,
   `ref_id` bigint unsigned NOT NULL,
@@ -97,6 +256,7 @@ CREATE TABLE IF NOT EXISTS `ref_threads` (
   PRIMARY KEY (`id`),
   UNIQUE KEY `idx_thread_id` (`thread_id`),
   KEY `idx_ref_db_id` (`ref_db_id`),
+  KEY `idx_ref_threads_guild_id` (`guild_id`),
   KEY `idx_network_ref` (`network_id`, `ref_id`),
   CONSTRAINT `fk_thread_ref` FOREIGN KEY (`ref_db_id`) REFERENCES `refs` (`id`) ON DELETE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -105,19 +265,47 @@ CREATE TABLE IF NOT EXISTS `ref_threads` (
 CREATE TABLE IF NOT EXISTS `ref_messages` (
   `id` bigint unsigned NOT NULL AUTO_INCREMENT,
   `ref_id` bigint unsigned NOT NULL,
+  `guild_id` varchar(64) NOT NULL DEFAULT '',
   `author` varchar(128) NOT NULL,
-  `body` text NOT NULL,
+  `author_id` varchar(64) DEFAULT NULL COMMENT 'Discord ID of the feedback author',
+  `body` text NOT NULL COMMENT 'Final text, as posted',
+  `draft` text DEFAULT NULL COMMENT 'Text as submitted for review',
+  `status` varchar(16) NOT NULL DEFAULT '' COMMENT 'pending, approving, approved or rejected when reviewed',
+  `reviewed_by` varchar(128) DEFAULT NULL,
+  `reviewed_at` timestamp NULL DEFAULT NULL,
+  `review_message_id` varchar(64) DEFAULT NULL,
   `internal` tinyint(1) DEFAULT '0',
   `polkassembly_user_id` int unsigned DEFAULT NULL,
   `polkassembly_username` varchar(128) DEFAULT NULL,
   `polkassembly_comment_id` varchar(64) DEFAULT NULL,
+  `polkassembly_parent_id` varchar(64) DEFAULT NULL COMMENT 'Comment replied to; NULL for top-level feedback',
+  `revises_id` bigint unsigned DEFAULT NULL COMMENT 'Posted feedback an edit under review replaces',
+  `discord_message_id` varchar(64) DEFAULT NULL COMMENT 'Thread message showing the feedback or reply',
+  `edited_at` timestamp NULL DEFAULT NULL,
+  `removed_at` timestamp NULL DEFAULT NULL COMMENT 'Withdrawn with /feedback-withdraw or deleted on Polkassembly',
   `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
   PRIMARY KEY (`id`),
   KEY `idx_message_proposal` (`ref_id`),
   KEY `idx_message_author` (`author`),
+  KEY `idx_message_discord` (`discord_message_id`),
+  KEY `idx_message_revises` (`revises_id`),
   CONSTRAINT `fk_message_proposal` FOREIGN KEY (`ref_id`) REFERENCES `refs` (`id`) ON DELETE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
+-- Edit history of ref_messages; version 1 is the text as first posted
+CREATE TABLE IF NOT EXISTS `ref_message_versions` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `message_id` bigint unsigned NOT NULL,
+  `version` int unsigned NOT NULL,
+  `body` text NOT NULL,
+  `edited_by` varchar(128) NOT NULL DEFAULT '',
+  `source` varchar(16) NOT NULL COMMENT 'discord or polkassembly',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_message_version` (`message_id`, `version`),
+  CONSTRAINT `fk_version_message` FOREIGN KEY (`message_id`) REFERENCES `ref_messages` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
 -- Q&A history for the AI module
 CREATE TABLE IF NOT EXISTS `qa_history` (
   `id` bigint unsigned NOT NULL AUTO_INCREMENT,
@@ -145,6 +333,222 @@ CREATE TABLE IF NOT EXISTS `ref_proponents` (
   CONSTRAINT `fk_participant_proposal` FOREIGN KEY (`ref_id`) REFERENCES `refs` (`id`) ON DELETE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
+-- On-chain conviction votes for ongoing referenda (snapshot per index run)
+CREATE TABLE IF NOT EXISTS `conviction_votes` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `ref_db_id` bigint unsigned NOT NULL,
+  `network_id` tinyint unsigned NOT NULL,
+  `ref_id` bigint unsigned NOT NULL,
+  `voter` varchar(128) NOT NULL,
+  `track_id` smallint unsigned NOT NULL,
+  `vote_type` varchar(16) NOT NULL COMMENT 'Standard, Split, SplitAbstain',
+  `aye` tinyint(1) DEFAULT NULL,
+  `conviction` tinyint unsigned NOT NULL DEFAULT '0',
+  `balance` varchar(64) DEFAULT NULL,
+  `aye_balance` varchar(64) DEFAULT NULL,
+  `nay_balance` varchar(64) DEFAULT NULL,
+  `abstain_balance` varchar(64) DEFAULT NULL,
+  `delegated_votes` varchar(64) DEFAULT NULL,
+  `delegated_capital` varchar(64) DEFAULT NULL,
+  `class_lock` varchar(64) DEFAULT NULL,
+  `snapshot_block` varchar(80) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_cv_network_ref_voter` (`network_id`,`ref_id`,`voter`),
+  KEY `idx_cv_ref` (`ref_db_id`),
+  KEY `idx_cv_voter` (`voter`),
+  CONSTRAINT `fk_cv_ref` FOREIGN KEY (`ref_db_id`) REFERENCES `refs` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Conviction voting delegations per track; changed delegations close the old row
+CREATE TABLE IF NOT EXISTS `conviction_delegations` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `network_id` tinyint unsigned NOT NULL,
+  `delegator` varchar(128) NOT NULL,
+  `target` varchar(128) NOT NULL,
+  `track_id` smallint unsigned NOT NULL,
+  `conviction` tinyint unsigned NOT NULL DEFAULT '0',
+  `balance` varchar(64) NOT NULL,
+  `class_lock` varchar(64) DEFAULT NULL,
+  `started_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `ended_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_cd_network_delegator` (`network_id`,`delegator`),
+  KEY `idx_cd_target` (`target`),
+  KEY `idx_cd_active` (`network_id`,`ended_at`),
+  CONSTRAINT `fk_cd_network` FOREIGN KEY (`network_id`) REFERENCES `networks` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Append-only referendum lifecycle history written by the indexer
+CREATE TABLE IF NOT EXISTS `ref_events` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `ref_db_id` bigint unsigned NOT NULL,
+  `network_id` tinyint unsigned NOT NULL,
+  `ref_id` bigint unsigned NOT NULL,
+  `event` varchar(32) NOT NULL COMMENT 'Submitted, DecisionStarted, ConfirmStarted, ConfirmAborted, Approved, ...',
+  `old_status` varchar(32) NOT NULL DEFAULT '',
+  `new_status` varchar(32) NOT NULL,
+  `block_number` bigint unsigned NOT NULL,
+  `block_hash` varchar(80) DEFAULT NULL,
+  `ayes` varchar(64) DEFAULT NULL,
+  `nays` varchar(64) DEFAULT NULL,
+  `support` varchar(64) DEFAULT NULL,
+  `occurred_at` datetime DEFAULT NULL,
+  `notified_at` datetime DEFAULT NULL COMMENT 'When the referendum thread was notified, or the event skipped',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_ref_event_notified` (`notified_at`),
+  UNIQUE KEY `idx_ref_event_unique` (`ref_db_id`,`event`,`block_number`),
+  KEY `idx_ref_event_network_ref` (`network_id`,`ref_id`),
+  CONSTRAINT `fk_ref_event_ref` FOREIGN KEY (`ref_db_id`) REFERENCES `refs` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Last finalized block processed by the indexer for each network
+CREATE TABLE IF NOT EXISTS `indexer_checkpoints` (
+  `network_id` tinyint unsigned NOT NULL,
+  `block_number` bigint unsigned NOT NULL DEFAULT '0',
+  `block_hash` varchar(80) DEFAULT NULL,
+  `block_time_ms` int unsigned NOT NULL DEFAULT '6000',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`network_id`),
+  CONSTRAINT `fk_checkpoint_network` FOREIGN KEY (`network_id`) REFERENCES `networks` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Decision-deadline reminders posted in referendum threads
+CREATE TABLE IF NOT EXISTS `ref_reminders` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `ref_db_id` bigint unsigned NOT NULL,
+  `network_id` tinyint unsigned NOT NULL,
+  `ref_id` bigint unsigned NOT NULL,
+  `offset_hours` smallint unsigned NOT NULL,
+  `decision_end` bigint unsigned NOT NULL,
+  `thread_id` varchar(64) NOT NULL,
+  `sent_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_ref_reminder` (`ref_db_id`,`offset_hours`,`decision_end`),
+  CONSTRAINT `fk_ref_reminder_ref` FOREIGN KEY (`ref_db_id`) REFERENCES `refs` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Treasury spends created by approved referenda and their payout status
+CREATE TABLE IF NOT EXISTS `ref_spends` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `ref_db_id` bigint unsigned NOT NULL,
+  `network_id` tinyint unsigned NOT NULL,
+  `ref_id` bigint unsigned NOT NULL,
+  `kind` varchar(16) NOT NULL COMMENT 'spend (Treasury.Spends) or spend_local (Treasury.Proposals)',
+  `spend_index` int unsigned NOT NULL,
+  `asset` varchar(32) DEFAULT NULL,
+  `amount` varchar(64) NOT NULL,
+  `amount_display` varchar(64) DEFAULT NULL,
+  `beneficiary` varchar(128) DEFAULT NULL,
+  `valid_from` bigint unsigned NOT NULL DEFAULT '0',
+  `expire_at` bigint unsigned NOT NULL DEFAULT '0',
+  `chain_state` varchar(16) DEFAULT NULL COMMENT 'Pending, Attempted, Failed; NULL once removed from storage',
+  `status` varchar(16) NOT NULL COMMENT 'pending, paid, failed, expired, cleared',
+  `payment_id` bigint unsigned DEFAULT NULL,
+  `payout_block` bigint unsigned DEFAULT NULL,
+  `note` varchar(255) DEFAULT NULL,
+  `alerted_at` datetime DEFAULT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_ref_spend_unique` (`network_id`,`kind`,`spend_index`),
+  KEY `idx_ref_spend_ref` (`ref_db_id`),
+  KEY `idx_ref_spend_status` (`status`),
+  CONSTRAINT `fk_ref_spend_ref` FOREIGN KEY (`ref_db_id`) REFERENCES `refs` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- On-chain identities of submitters, depositors and beneficiaries, resolved
+-- from the Identity pallet of each network's People chain
+CREATE TABLE IF NOT EXISTS `account_identities` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `network_id` tinyint unsigned NOT NULL,
+  `address` varchar(128) NOT NULL,
+  `has_identity` tinyint(1) NOT NULL DEFAULT '0',
+  `display` varchar(255) DEFAULT NULL,
+  `fields` text DEFAULT NULL COMMENT 'JSON identity info fields other than display',
+  `judgements` text DEFAULT NULL COMMENT 'JSON array of registrar judgements',
+  `judgement` varchar(16) NOT NULL DEFAULT 'none',
+  `verified` tinyint(1) NOT NULL DEFAULT '0',
+  `parent_address` varchar(128) DEFAULT NULL,
+  `parent_display` varchar(255) DEFAULT NULL,
+  `sub_name` varchar(255) DEFAULT NULL,
+  `resolved_at` datetime NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_account_identity` (`network_id`,`address`),
+  KEY `idx_account_identity_resolved` (`resolved_at`),
+  CONSTRAINT `fk_account_identity_network` FOREIGN KEY (`network_id`) REFERENCES `networks` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- DAO members of each guild and the Discord accounts they vote from
+CREATE TABLE IF NOT EXISTS `dao_members` (
+  `guild_id` varchar(64) NOT NULL,
+  `address` varchar(128) NOT NULL,
+  `discord` varchar(64) DEFAULT NULL COMMENT 'Discord user ID',
+  `is_admin` tinyint(1) NOT NULL DEFAULT '0',
+  `scheme` varchar(16) DEFAULT NULL COMMENT 'sr25519, ed25519 or ecdsa signature that proved ownership',
+  `linked_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`guild_id`,`address`),
+  KEY `idx_dao_member_discord` (`discord`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Pending /link-wallet challenges: the message a Discord user must sign
+CREATE TABLE IF NOT EXISTS `wallet_challenges` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `guild_id` varchar(64) NOT NULL,
+  `discord_id` varchar(64) NOT NULL,
+  `address` varchar(128) NOT NULL,
+  `message` text NOT NULL,
+  `expires_at` datetime NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_wallet_challenge` (`guild_id`,`discord_id`,`address`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Internal DAO votes cast with /vote, one per guild and member Discord
+-- account; members may change them until the cutoff
+CREATE TABLE IF NOT EXISTS `dao_votes` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `ref_id` bigint unsigned NOT NULL,
+  `guild_id` varchar(64) NOT NULL,
+  `discord` varchar(64) NOT NULL COMMENT 'Discord user ID of the member',
+  `choice` smallint NOT NULL COMMENT '1 aye, -1 nay, 0 abstain',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_dao_vote_member` (`ref_id`,`guild_id`,`discord`),
+  CONSTRAINT `fk_dao_vote_ref` FOREIGN KEY (`ref_id`) REFERENCES `refs` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- DAO decision per referendum and guild: live tally while voting is open,
+-- outcome once closed
+CREATE TABLE IF NOT EXISTS `dao_decisions` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `ref_db_id` bigint unsigned NOT NULL,
+  `guild_id` varchar(64) NOT NULL,
+  `network_id` tinyint unsigned NOT NULL,
+  `ref_id` bigint unsigned NOT NULL,
+  `thread_id` varchar(64) DEFAULT NULL,
+  `tally_message_id` varchar(64) DEFAULT NULL,
+  `ayes` int unsigned NOT NULL DEFAULT '0',
+  `nays` int unsigned NOT NULL DEFAULT '0',
+  `abstains` int unsigned NOT NULL DEFAULT '0',
+  `members` int unsigned NOT NULL DEFAULT '0',
+  `quorum_percent` tinyint unsigned NOT NULL DEFAULT '0',
+  `quorum_met` tinyint(1) NOT NULL DEFAULT '0',
+  `outcome` varchar(16) DEFAULT NULL COMMENT 'aye, nay, abstain, no_quorum; NULL while open',
+  `decided_at` datetime DEFAULT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_dao_decision_ref` (`ref_db_id`,`guild_id`),
+  KEY `idx_dao_decision_open` (`outcome`),
+  CONSTRAINT `fk_dao_decision_ref` FOREIGN KEY (`ref_db_id`) REFERENCES `refs` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
 -- Insert initial settings with your actual values
 INSERT INTO settings (id, name, value, active) VALUES
     (1, 'site_name', 'Opengov Communications Platform', 1),
@@ -154,17 +558,68 @@ INSERT INTO settings (id, name, value, active) VALUES
     (5, 'indexer_workers', '10', 1),
     (6, 'indexer_interval_minutes', '60', 1),
     (7, 'polkassembly_intro', '', 1),
-    (8, 'polkassembly_outro', '', 1);
+    (8, 'polkassembly_outro', '', 1),
+    (9, 'dao_vote_cutoff_hours', '24', 1),
+    (10, 'dao_quorum_percent', '50', 1),
+    (11, 'auto_create_threads', '0', 1),
+    (12, 'auto_thread_max_age_hours', '48', 1),
+    (13, 'decision_reminder_hours', '72,24,6', 1),
+    (14, 'feedback_review_channel_id', '', 1);
 
 -- Insert network data with Discord channel IDs
-INSERT INTO networks (id, name, symbol, url, discord_channel_id) VALUES
-    (1, 'Polkadot', 'DOT', 'https://polkadot.network', ''),
-    (2, 'Kusama', 'KSM', 'https://kusama.network', '');
+INSERT INTO networks (id, name, symbol, url, discord_channel_id, referenda_pallet) VALUES
+    (1, 'Polkadot', 'DOT', 'https://polkadot.network', '', 'Referenda'),
+    (2, 'Kusama', 'KSM', 'https://kusama.network', '', 'Referenda'),
+    (3, 'Collectives', 'DOT', 'https://collectives.polkadot.io', '', 'FellowshipReferenda'),
+    (4, 'Ambassador', 'DOT', 'https://collectives.polkadot.io', '', 'AmbassadorReferenda');
+
+-- Open /question to everyone; keep /feedback and /report to DAO members
+INSERT INTO command_permissions (command, effect, subject, subject_id, note) VALUES
+    ('question', 'allow', 'everyone', '', 'Q&A for everyone, with or without the QA role'),
+    ('feedback', 'allow', 'dao_member', '', 'Linked DAO members, in place of the feedback role'),
+    ('report', 'allow', 'dao_member', '', 'DAO reports only');
+
+INSERT INTO command_quotas (command, scope, subject_id, window_minutes, max_requests, max_tokens, cooldown_seconds, note) VALUES
+    ('question', 'user', '', 60, 20, 0, 10, 'Q&A per user'),
+    ('summary', 'user', '', 1440, 20, 0, 30, 'Summaries per user'),
+    ('refresh', 'user', '', 1440, 10, 0, 300, 'Research refreshes per user'),
+    ('report', 'user', '', 1440, 5, 0, 600, 'Reports per user'),
+    ('*', 'guild', '', 43200, 0, 20000000, 0, 'Monthly AI budget per server');
+
+-- List prices at the time of writing; update them when providers change theirs
+INSERT INTO ai_model_prices (provider, model, input_usd_per_mtok, cached_input_usd_per_mtok, output_usd_per_mtok) VALUES
+    ('', 'gpt-5.1', 1.25, 0.125, 10.00),
+    ('', 'gpt-4o', 2.50, 1.25, 10.00),
+    ('', 'claude-sonnet-4-5', 3.00, 0.30, 15.00),
+    ('', 'claude-haiku-4-5', 1.00, 0.10, 5.00),
+    ('', 'claude-opus-4-1', 15.00, 1.50, 75.00),
+    ('', 'gemini-2.5-flash', 0.30, 0.03, 2.50),
+    ('', 'deepseek-chat', 0.28, 0.028, 0.42),
+    ('', 'grok-4-fast-reasoning', 0.20, 0.05, 0.50);
 
 -- Insert RPC endpoints
 INSERT INTO network_rpcs (network_id, url, active) VALUES
     (1, 'wss://polkadot.dotters.network/', 1),
-    (2, 'wss://kusama.dotters.network/', 1);
+    (1, 'wss://rpc.ibp.network/polkadot', 1),
+    (1, 'wss://polkadot-rpc.dwellir.com', 1),
+    (2, 'wss://kusama.dotters.network/', 1),
+    (2, 'wss://rpc.ibp.network/kusama', 1),
+    (2, 'wss://kusama-rpc.dwellir.com', 1),
+    (3, 'wss://collectives-polkadot.dotters.network/', 1),
+    (3, 'wss://sys.ibp.network/collectives-polkadot', 1),
+    (4, 'wss://collectives-polkadot.dotters.network/', 1),
+    (4, 'wss://sys.ibp.network/collectives-polkadot', 1);
+
+-- People chain endpoints used to resolve on-chain identities
+INSERT INTO network_rpcs (network_id, url, role, active) VALUES
+    (1, 'wss://people-polkadot.dotters.network/', 'identity', 1),
+    (1, 'wss://sys.ibp.network/people-polkadot', 'identity', 1),
+    (2, 'wss://people-kusama.dotters.network/', 'identity', 1),
+    (2, 'wss://sys.ibp.network/people-kusama', 'identity', 1),
+    (3, 'wss://people-polkadot.dotters.network/', 'identity', 1),
+    (3, 'wss://sys.ibp.network/people-polkadot', 'identity', 1),
+    (4, 'wss://people-polkadot.dotters.network/', 'identity', 1),
+    (4, 'wss://sys.ibp.network/people-polkadot', 'identity', 1);
 
     CREATE TABLE IF NOT EXISTS `ref_claims` (
   `id` bigint unsigned NOT NULL AUTO_INCREMENT,
@@ -0,0 +1,55 @@
+package data
+
+import (
+	"log"
+
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+)
+
+// checkEndpoints health-checks the network's RPC endpoints, failing over when
+// needed, and records each endpoint's status on network_rpcs. Returns true
+// when the client switched to another endpoint.
+func (ni *NetworkIndexer) checkEndpoints() bool {
+	previous := ni.client.ActiveEndpoint()
+	switched, err := ni.client.CheckHealth()
+	if err != nil {
+		log.Printf("%s indexer: %v", ni.networkName, err)
+	}
+	if switched {
+		ni.rpcURL = ni.client.ActiveEndpoint()
+		log.Printf("%s indexer: failed over from %s to %s", ni.networkName, previous, ni.rpcURL)
+	}
+
+	for _, status := range ni.client.Endpoints() {
+		if status.CheckedAt.IsZero() {
+			continue
+		}
+
+		updates := map[string]interface{}{
+			"in_use":          status.Active,
+			"healthy":         status.Healthy,
+			"failures":        status.Failures,
+			"last_checked_at": status.CheckedAt,
+			"last_error":      nil,
+		}
+		if status.LastError != "" {
+			lastError := status.LastError
+			if len(lastError) > 512 {
+				lastError = lastError[:512]
+			}
+			updates["last_error"] = lastError
+		} else {
+			updates["latency_ms"] = uint32(status.Latency.Milliseconds())
+			updates["best_block"] = status.BestBlock
+			updates["block_lag"] = status.Lag
+		}
+
+		if err := ni.db.Model(&sharedgov.NetworkRPC{}).
+			Where("network_id = ? AND role = ? AND url = ?", ni.networkID, sharedgov.RPCRoleGovernance, status.URL).
+			Updates(updates).Error; err != nil {
+			log.Printf("%s indexer: failed to record RPC status for %s: %v", ni.networkName, status.URL, err)
+		}
+	}
+
+	return switched
+}
@@ -0,0 +1,224 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	polkadot "github.com/stake-plus/govcomms/src/polkadot-go"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// identityRefreshAge is how long a resolved identity is trusted before
+	// it is read from the People chain again
+	identityRefreshAge = 24 * time.Hour
+	// identityBatchSize bounds the identities resolved per refresh
+	identityBatchSize = 200
+	// identityRetryDelay spaces reconnect attempts to the People chain
+	identityRetryDelay = 15 * time.Minute
+)
+
+// resolveIdentities refreshes the cached identities of every address shown
+// for this network's referenda: submitters, depositors and beneficiaries
+func (ni *NetworkIndexer) resolveIdentities(ctx context.Context) {
+	client := ni.identityChain()
+	if client == nil {
+		return
+	}
+
+	addresses := ni.displayedAddresses()
+	if len(addresses) == 0 {
+		return
+	}
+
+	var fresh []string
+	if err := ni.db.Model(&sharedgov.AccountIdentity{}).
+		Where("network_id = ? AND resolved_at >= ?", ni.networkID, time.Now().Add(-identityRefreshAge)).
+		Pluck("address", &fresh).Error; err != nil {
+		log.Printf("%s indexer: failed to load cached identities: %v", ni.networkName, err)
+		return
+	}
+	skip := make(map[string]bool, len(fresh))
+	for _, address := range fresh {
+		skip[address] = true
+	}
+
+	var resolved int
+	for _, address := range addresses {
+		if skip[address] {
+			continue
+		}
+		if ctx.Err() != nil || resolved == identityBatchSize {
+			break
+		}
+
+		identity, err := client.GetIdentity(address)
+		if err != nil {
+			// The connection may be gone; reconnect on the next refresh
+			log.Printf("%s indexer: failed to resolve identity of %s, dropping People chain connection: %v", ni.networkName, address, err)
+			ni.dropIdentityChain()
+			break
+		}
+		if err := ni.storeIdentity(address, identity); err != nil {
+			log.Printf("%s indexer: failed to store identity of %s: %v", ni.networkName, address, err)
+			continue
+		}
+		resolved++
+	}
+
+	if resolved > 0 {
+		log.Printf("%s indexer: resolved %d on-chain identities", ni.networkName, resolved)
+	}
+}
+
+// identityChain returns a client for the network's People chain, connecting
+// on first use and after a failed query. Returns nil when no identity RPC is configured or none is
+// reachable.
+func (ni *NetworkIndexer) identityChain() *polkadot.Client {
+	if ni.identityClient != nil {
+		return ni.identityClient
+	}
+	if time.Now().Before(ni.identityRetryAt) {
+		return nil
+	}
+	ni.identityRetryAt = time.Now().Add(identityRetryDelay)
+
+	var rpcs []sharedgov.NetworkRPC
+	if err := ni.db.
+		Where("network_id = ? AND role = ? AND active = ?", ni.networkID, sharedgov.RPCRoleIdentity, true).
+		Order("id").
+		Find(&rpcs).Error; err != nil {
+		log.Printf("%s indexer: failed to load identity RPCs: %v", ni.networkName, err)
+		return nil
+	}
+	if len(rpcs) == 0 {
+		return nil
+	}
+
+	urls := make([]string, 0, len(rpcs))
+	for _, rpc := range rpcs {
+		urls = append(urls, rpc.URL)
+	}
+
+	client, err := polkadot.NewClientWithEndpoints(urls)
+	if err != nil {
+		log.Printf("%s indexer: failed to connect to People chain: %v", ni.networkName, err)
+		return nil
+	}
+	if !client.HasPallet("Identity") {
+		log.Printf("%s indexer: %s has no Identity pallet, identities disabled", ni.networkName, client.ActiveEndpoint())
+		client.Close()
+		return nil
+	}
+
+	log.Printf("%s indexer: resolving identities via %s", ni.networkName, client.ActiveEndpoint())
+	ni.identityClient = client
+	return client
+}
+
+// dropIdentityChain closes the People chain client so that identityChain
+// connects again
+func (ni *NetworkIndexer) dropIdentityChain() {
+	if ni.identityClient == nil {
+		return
+	}
+	ni.identityClient.Close()
+	ni.identityClient = nil
+}
+
+// displayedAddresses lists the submitters and depositors of the network's
+// referenda, the beneficiaries of open referenda's calls and the recipients
+// of tracked treasury spends
+func (ni *NetworkIndexer) displayedAddresses() []string {
+	var refs []sharedgov.Ref
+	if err := ni.db.
+		Select("id", "submitter", "submission_deposit_who", "decision_deposit_who", "decoded_call", "finalized").
+		Where("network_id = ?", ni.networkID).
+		Find(&refs).Error; err != nil {
+		log.Printf("%s indexer: failed to load referendum accounts: %v", ni.networkName, err)
+		return nil
+	}
+
+	var spendBeneficiaries []string
+	if err := ni.db.Model(&sharedgov.RefSpend{}).
+		Where("network_id = ? AND beneficiary IS NOT NULL", ni.networkID).
+		Distinct().
+		Pluck("beneficiary", &spendBeneficiaries).Error; err != nil {
+		log.Printf("%s indexer: failed to load spend beneficiaries: %v", ni.networkName, err)
+	}
+
+	seen := make(map[string]bool)
+	var addresses []string
+	add := func(accounts []sharedgov.RefAccount) {
+		for _, account := range accounts {
+			if !seen[account.Address] {
+				seen[account.Address] = true
+				addresses = append(addresses, account.Address)
+			}
+		}
+	}
+
+	for i := range refs {
+		var beneficiaries []string
+		if !refs[i].Finalized && refs[i].DecodedCall != nil {
+			if call, err := polkadot.ParseProposalCall([]byte(*refs[i].DecodedCall)); err == nil {
+				beneficiaries = call.Beneficiaries
+			}
+		}
+		add(sharedgov.RefAccounts(&refs[i], beneficiaries))
+	}
+	add(sharedgov.RefAccounts(nil, spendBeneficiaries))
+
+	return addresses
+}
+
+// storeIdentity caches a resolved identity, recording accounts without one
+// so they are not queried again until the cache ages out
+func (ni *NetworkIndexer) storeIdentity(address string, identity *polkadot.OnChainIdentity) error {
+	row := sharedgov.AccountIdentity{
+		NetworkID:  ni.networkID,
+		Address:    address,
+		Judgement:  "none",
+		ResolvedAt: time.Now(),
+	}
+
+	if identity != nil {
+		row.HasIdentity = true
+		row.Display = optionalString(identity.Display())
+		row.Judgement = identity.JudgementStatus()
+		row.Verified = identity.Verified()
+		row.SubName = optionalString(identity.SubName)
+		if identity.Parent != "" {
+			row.ParentAddress = &identity.Parent
+			row.ParentDisplay = optionalString(identity.Display())
+		}
+
+		fields := make(map[string]string, len(identity.Fields))
+		for name, value := range identity.Fields {
+			if name != "display" {
+				fields[name] = value
+			}
+		}
+		if len(fields) > 0 {
+			if encoded, err := json.Marshal(fields); err == nil {
+				row.Fields = optionalString(string(encoded))
+			}
+		}
+		if len(identity.Judgements) > 0 {
+			if encoded, err := json.Marshal(identity.Judgements); err == nil {
+				row.Judgements = optionalString(string(encoded))
+			}
+		}
+	}
+
+	return ni.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "network_id"}, {Name: "address"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"has_identity", "display", "fields", "judgements", "judgement", "verified",
+			"parent_address", "parent_display", "sub_name", "resolved_at", "updated_at",
+		}),
+	}).Create(&row).Error
+}
@@ -16,6 +16,7 @@ import (
 type NetworkIndexer struct {
 	networkID    uint8
 	networkName  string
+	symbol       string
 	db           *gorm.DB
 	rpcURL       string
 	client       *polkadot.Client
@@ -23,10 +24,17 @@ type NetworkIndexer struct {
 	running      bool
 	workers      int
 	currentBlock uint32
+	lastBlock    uint64
+	// blockTime is the chain's expected block time, used for wall-clock estimates
+	blockTime time.Duration
+	// People chain client used to resolve identities, connected on first use
+	identityClient  *polkadot.Client
+	identityRetryAt time.Time
 }
 
 type MultiNetworkIndexer struct {
 	indexers map[uint8]*NetworkIndexer
+	mu       sync.Mutex
 	db       *gorm.DB
 	workers  int
 }
@@ -40,17 +48,32 @@ func NewNetworkIndexer(networkID uint8, networkName string, db *gorm.DB, workers
 		networkName = network.Name
 	}
 
-	var rpc sharedgov.NetworkRPC
-	err := db.Where("network_id = ? AND active = ?", networkID, true).First(&rpc).Error
-	if err != nil {
-		return nil, fmt.Errorf("no active RPC for network %d: %w", networkID, err)
+	var rpcs []sharedgov.NetworkRPC
+	if err := db.Where("network_id = ? AND role = ? AND active = ?", networkID, sharedgov.RPCRoleGovernance, true).Order("id").Find(&rpcs).Error; err != nil {
+		return nil, fmt.Errorf("load RPCs for network %d: %w", networkID, err)
+	}
+	if len(rpcs) == 0 {
+		return nil, fmt.Errorf("no active RPC for network %d", networkID)
+	}
+
+	urls := make([]string, 0, len(rpcs))
+	for _, rpc := range rpcs {
+		urls = append(urls, rpc.URL)
 	}
 
-	client, err := polkadot.NewClient(rpc.URL)
+	client, err := polkadot.NewClientWithEndpoints(urls)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create polkadot client for %s: %w", networkName, err)
 	}
 
+	if err := client.SetReferendaPallet(network.ReferendaPallet); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("configure referenda pallet for %s: %w", networkName, err)
+	}
+	if client.ReferendaPallet() != polkadot.DefaultReferendaPallet {
+		log.Printf("%s indexer: using %s (ranked tally: %v)", networkName, client.ReferendaPallet(), client.RankedTally())
+	}
+
 	if prefix, prefixErr := client.GetSS58Prefix(); prefixErr != nil {
 		log.Printf("Failed to determine SS58 prefix for %s: %v", networkName, prefixErr)
 	} else {
@@ -67,10 +90,12 @@ func NewNetworkIndexer(networkID uint8, networkName string, db *gorm.DB, workers
 	return &NetworkIndexer{
 		networkID:   networkID,
 		networkName: networkName,
+		symbol:      network.Symbol,
 		db:          db,
-		rpcURL:      rpc.URL,
+		rpcURL:      client.ActiveEndpoint(),
 		client:      client,
 		workers:     workers,
+		blockTime:   client.ExpectedBlockTime(),
 	}, nil
 }
 
@@ -92,21 +117,52 @@ func (mni *MultiNetworkIndexer) StartAll(ctx context.Context, interval time.Dura
 		indexer, err := NewNetworkIndexer(network.ID, network.Name, mni.db, workers)
 		if err != nil {
 			log.Printf("Failed to create indexer for %s: %v", network.Name, err)
+			go mni.retryStart(ctx, network, interval, workers)
 			continue
 		}
 
-		mni.indexers[network.ID] = indexer
-
-		go func(idx *NetworkIndexer, netName string) {
-			log.Printf("Starting indexer for %s with RPC: %s", netName, idx.rpcURL)
-			idx.Run(ctx, interval)
-			log.Printf("Indexer for %s stopped", netName)
-		}(indexer, network.Name)
+		mni.start(ctx, indexer, network.Name, interval)
 	}
 
 	return nil
 }
 
+func (mni *MultiNetworkIndexer) start(ctx context.Context, indexer *NetworkIndexer, netName string, interval time.Duration) {
+	mni.mu.Lock()
+	mni.indexers[indexer.networkID] = indexer
+	mni.mu.Unlock()
+
+	go func() {
+		log.Printf("Starting indexer for %s with RPC: %s", netName, indexer.rpcURL)
+		indexer.Run(ctx, interval)
+		log.Printf("Indexer for %s stopped", netName)
+	}()
+}
+
+// retryStart keeps trying to create a network's indexer with backoff, for
+// when none of its RPC endpoints were reachable at startup
+func (mni *MultiNetworkIndexer) retryStart(ctx context.Context, network sharedgov.Network, interval time.Duration, workers int) {
+	delay := 30 * time.Second
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		indexer, err := NewNetworkIndexer(network.ID, network.Name, mni.db, workers)
+		if err == nil {
+			mni.start(ctx, indexer, network.Name, interval)
+			return
+		}
+
+		if delay < 10*time.Minute {
+			delay *= 2
+		}
+		log.Printf("Failed to create indexer for %s, retrying in %v: %v", network.Name, delay, err)
+	}
+}
+
 func (ni *NetworkIndexer) Run(ctx context.Context, interval time.Duration) {
 	ni.mu.Lock()
 	if ni.running {
@@ -123,22 +179,12 @@ func (ni *NetworkIndexer) Run(ctx context.Context, interval time.Duration) {
 		if ni.client != nil {
 			ni.client.Close()
 		}
+		if ni.identityClient != nil {
+			ni.identityClient.Close()
+		}
 	}()
 
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-
-	ni.indexOnce(ctx)
-
-	for {
-		select {
-		case <-ctx.Done():
-			log.Printf("Stopping indexer for %s", ni.networkName)
-			return
-		case <-ticker.C:
-			ni.indexOnce(ctx)
-		}
-	}
+	ni.followFinalized(ctx, interval)
 }
 
 func (ni *NetworkIndexer) indexOnce(ctx context.Context) {
@@ -237,6 +283,8 @@ func (ni *NetworkIndexer) indexOnce(ctx context.Context) {
 		}
 	}
 
+	ni.indexConvictionVoting(ctx)
+
 	log.Printf("%s indexer: Completed index run", ni.networkName)
 }
 
@@ -310,13 +358,13 @@ func (ni *NetworkIndexer) processReferendum(refID uint64) {
 			UpdatedAt: time.Now(),
 		}
 
-		// Calculate submitted time - submitted block number * 6 seconds per block
+		// Calculate submitted time from the blocks since submission; a
+		// referendum indexed from its own Submitted event is 0 blocks old
 		if refInfo.Submitted > 0 && ni.currentBlock > 0 {
 			// Calculate blocks ago
 			blocksAgo := int64(ni.currentBlock) - int64(refInfo.Submitted)
-			if blocksAgo > 0 {
-				secondsAgo := blocksAgo * 6
-				submittedTime := time.Now().Add(-time.Duration(secondsAgo) * time.Second)
+			if blocksAgo >= 0 {
+				submittedTime := time.Now().Add(-time.Duration(blocksAgo) * ni.blockTime)
 				// Only set if the date is reasonable (after 1970)
 				if submittedTime.Year() >= 1970 {
 					ref.SubmittedAt = &submittedTime
@@ -329,6 +377,11 @@ func (ni *NetworkIndexer) processReferendum(refID uint64) {
 			if refInfo.ProposalLen > 0 {
 				ref.PreimageLen = &refInfo.ProposalLen
 			}
+			ref.DecodedCall = ni.decodeProposalCall(refID, refInfo)
+		}
+
+		if isOngoing {
+			ref.PassProjection = ni.projectPassing(refID, refInfo)
 		}
 
 		if refInfo.DecisionDeposit != nil {
@@ -356,6 +409,7 @@ func (ni *NetworkIndexer) processReferendum(refID uint64) {
 
 		if refInfo.Decision != nil {
 			ref.DecisionStart = uint64(refInfo.Decision.Since)
+			ref.DecisionEnd, ref.DecisionEndAt = ni.decisionEnd(refID, refInfo)
 			if refInfo.Decision.Confirming != nil {
 				ref.ConfirmStart = uint64(*refInfo.Decision.Confirming)
 			}
@@ -395,6 +449,7 @@ func (ni *NetworkIndexer) processReferendum(refID uint64) {
 		} else {
 			log.Printf("Created %s ref #%d - Status: %s, Track: %d, Submitter: %s",
 				ni.networkName, refID, refInfo.Status, refInfo.Track, refInfo.Submission.Who)
+			ni.recordRefEvents(&ref, ni.lifecycleTransitions(nil, refInfo), refInfo, isOngoing)
 		}
 	} else if dbErr == nil {
 		// Update existing referendum
@@ -419,12 +474,33 @@ func (ni *NetworkIndexer) processReferendum(refID uint64) {
 
 		if refInfo.Decision != nil {
 			updates["decision_start"] = uint64(refInfo.Decision.Since)
+			if end, endsAt := ni.decisionEnd(refID, refInfo); end > 0 {
+				updates["decision_end"] = end
+				updates["decision_end_at"] = endsAt
+			}
 			if refInfo.Decision.Confirming != nil {
 				updates["confirm_start"] = uint64(*refInfo.Decision.Confirming)
 			}
 		}
 
 		isOngoing := refInfo.Status == "Ongoing"
+		transitions := ni.lifecycleTransitions(&ref, refInfo)
+		if isOngoing && refInfo.Decision != nil && refInfo.Decision.Confirming == nil && ref.ConfirmStart > 0 {
+			updates["confirm_start"] = uint64(0)
+		}
+
+		if isOngoing {
+			if projection := ni.projectPassing(refID, refInfo); projection != nil {
+				updates["pass_projection"] = projection
+			}
+		}
+
+		if isOngoing && ref.DecodedCall == nil && refInfo.Proposal != "" {
+			if decoded := ni.decodeProposalCall(refID, refInfo); decoded != nil {
+				updates["decoded_call"] = decoded
+			}
+		}
+
 		if !isOngoing && !ref.Finalized {
 			updates["finalized"] = true
 			updates["approved"] = refInfo.Status == "Approved"
@@ -458,6 +534,8 @@ func (ni *NetworkIndexer) processReferendum(refID uint64) {
 
 		if err := ni.db.Model(&ref).Updates(updates).Error; err != nil {
 			log.Printf("Failed to update %s ref #%d: %v", ni.networkName, refID, err)
+		} else {
+			ni.recordRefEvents(&ref, transitions, refInfo, true)
 		}
 	} else {
 		log.Printf("Database error for %s ref #%d: %v", ni.networkName, refID, dbErr)
@@ -8,14 +8,15 @@ import (
 	"gorm.io/gorm"
 )
 
-// SaveFeedbackMessage persists a feedback message for a referendum.
-func SaveFeedbackMessage(db *gorm.DB, ref *sharedgov.Ref, author, body string) (*sharedgov.RefMessage, error) {
+// SaveFeedbackMessage persists a guild's feedback message for a referendum.
+func SaveFeedbackMessage(db *gorm.DB, ref *sharedgov.Ref, guildID, author, body string) (*sharedgov.RefMessage, error) {
 	if ref == nil {
 		return nil, fmt.Errorf("nil referendum provided")
 	}
 
 	message := sharedgov.RefMessage{
 		RefID:     ref.ID,
+		GuildID:   guildID,
 		Author:    author,
 		Body:      body,
 		CreatedAt: time.Now(),
@@ -28,6 +29,130 @@ func SaveFeedbackMessage(db *gorm.DB, ref *sharedgov.Ref, author, body string) (
 	return &message, nil
 }
 
+// SaveFeedbackDraft persists feedback that waits for review before it is
+// posted to Polkassembly.
+func SaveFeedbackDraft(db *gorm.DB, ref *sharedgov.Ref, guildID, authorID, author, body string) (*sharedgov.RefMessage, error) {
+	if ref == nil {
+		return nil, fmt.Errorf("nil referendum provided")
+	}
+
+	draft := body
+	message := sharedgov.RefMessage{
+		RefID:     ref.ID,
+		GuildID:   guildID,
+		Author:    author,
+		AuthorID:  &authorID,
+		Body:      body,
+		Draft:     &draft,
+		Status:    sharedgov.FeedbackPending,
+		CreatedAt: time.Now(),
+	}
+
+	if err := db.Create(&message).Error; err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// SaveFeedbackReplyDraft stores a Discord reply to a proponent that waits
+// for review before it is posted under parentID on Polkassembly.
+func SaveFeedbackReplyDraft(db *gorm.DB, refDBID uint64, guildID, authorID, author, body, discordMessageID, parentID string) (*sharedgov.RefMessage, error) {
+	if parentID == "" {
+		return nil, fmt.Errorf("parent comment ID is required")
+	}
+
+	draft := body
+	msg := sharedgov.RefMessage{
+		RefID:                refDBID,
+		GuildID:              guildID,
+		Author:               author,
+		AuthorID:             &authorID,
+		Body:                 body,
+		Draft:                &draft,
+		Status:               sharedgov.FeedbackPending,
+		PolkassemblyParentID: &parentID,
+		DiscordMessageID:     &discordMessageID,
+		CreatedAt:            time.Now(),
+	}
+
+	if err := db.Create(&msg).Error; err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+// SaveFeedbackEditDraft stores a revision of posted feedback that waits for
+// review before the Polkassembly comment is edited.
+func SaveFeedbackEditDraft(db *gorm.DB, posted *sharedgov.RefMessage, authorID, author, body string) (*sharedgov.RefMessage, error) {
+	if posted == nil {
+		return nil, fmt.Errorf("nil message provided")
+	}
+
+	draft := body
+	msg := sharedgov.RefMessage{
+		RefID:     posted.RefID,
+		GuildID:   posted.GuildID,
+		Author:    author,
+		AuthorID:  &authorID,
+		Body:      body,
+		Draft:     &draft,
+		Status:    sharedgov.FeedbackPending,
+		RevisesID: &posted.ID,
+		CreatedAt: time.Now(),
+	}
+
+	if err := db.Create(&msg).Error; err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+// HasPendingFeedback reports whether a guild's feedback for a referendum, or
+// an edit of it, is waiting for review or being posted. Replies under review
+// don't count.
+func HasPendingFeedback(db *gorm.DB, refDBID uint64, guildID string) (bool, error) {
+	var count int64
+	if err := db.Model(&sharedgov.RefMessage{}).
+		Where("ref_id = ? AND guild_id = ? AND status IN ?", refDBID, guildID, []string{sharedgov.FeedbackPending, sharedgov.FeedbackApproving}).
+		Where("polkassembly_parent_id IS NULL").
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// SetFeedbackStatus moves feedback from one review status to another and
+// reports whether it was still in the expected status, so that only one
+// reviewer acts on it.
+func SetFeedbackStatus(db *gorm.DB, messageID uint64, from, to string) (bool, error) {
+	result := db.Model(&sharedgov.RefMessage{}).
+		Where("id = ? AND status = ?", messageID, from).
+		Update("status", to)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReopenStalledFeedback returns drafts left in approval, as when the bot
+// stopped while posting one, to the review queue and reports how many it
+// reopened. Drafts that already reached Polkassembly are marked approved
+// instead, so they are not posted twice.
+func ReopenStalledFeedback(db *gorm.DB) (int64, error) {
+	if err := db.Model(&sharedgov.RefMessage{}).
+		Where("status = ? AND polkassembly_comment_id IS NOT NULL AND polkassembly_comment_id <> ''", sharedgov.FeedbackApproving).
+		Update("status", sharedgov.FeedbackApproved).Error; err != nil {
+		return 0, err
+	}
+	result := db.Model(&sharedgov.RefMessage{}).
+		Where("status = ?", sharedgov.FeedbackApproving).
+		Update("status", sharedgov.FeedbackPending)
+	return result.RowsAffected, result.Error
+}
+
 // UpdateFeedbackMessagePolkassembly updates a feedback message with Polkassembly information
 func UpdateFeedbackMessagePolkassembly(db *gorm.DB, messageID uint64, commentID string, userID *uint32, username string) error {
 	updates := map[string]interface{}{
@@ -45,6 +170,105 @@ func UpdateFeedbackMessagePolkassembly(db *gorm.DB, messageID uint64, commentID
 		Updates(updates).Error
 }
 
+// SetFeedbackDiscordMessage records the thread message that shows a feedback
+// message or Polkassembly reply
+func SetFeedbackDiscordMessage(db *gorm.DB, messageID uint64, discordMessageID string) error {
+	return db.Model(&sharedgov.RefMessage{}).
+		Where("id = ?", messageID).
+		Update("discord_message_id", discordMessageID).Error
+}
+
+// GetFeedbackByDiscordMessage returns the live message shown by a thread message.
+func GetFeedbackByDiscordMessage(db *gorm.DB, discordMessageID string) (*sharedgov.RefMessage, error) {
+	var msg sharedgov.RefMessage
+	if err := db.Where("discord_message_id = ? AND removed_at IS NULL", discordMessageID).First(&msg).Error; err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// GetGuildFeedback returns a guild's live feedback posted to Polkassembly for a referendum.
+func GetGuildFeedback(db *gorm.DB, refDBID uint64, guildID string) (*sharedgov.RefMessage, error) {
+	var msg sharedgov.RefMessage
+	if err := db.Where("ref_id = ? AND guild_id = ? AND internal = ? AND removed_at IS NULL AND polkassembly_parent_id IS NULL", refDBID, guildID, false).
+		Where("polkassembly_comment_id IS NOT NULL AND polkassembly_comment_id <> ''").
+		Order("created_at DESC").
+		First(&msg).Error; err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// ReviseFeedbackMessage replaces a message body and records the revision. A
+// message's first revision also records the text it replaces as version 1.
+func ReviseFeedbackMessage(db *gorm.DB, msg *sharedgov.RefMessage, body, editedBy, source string) error {
+	if msg == nil {
+		return fmt.Errorf("nil message provided")
+	}
+
+	now := time.Now()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var latest uint32
+		if err := tx.Model(&sharedgov.RefMessageVersion{}).
+			Where("message_id = ?", msg.ID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		if latest == 0 {
+			original := sharedgov.RefMessageVersion{
+				MessageID: msg.ID,
+				Version:   1,
+				Body:      msg.Body,
+				EditedBy:  msg.Author,
+				Source:    sharedgov.MessageSourceDiscord,
+				CreatedAt: msg.CreatedAt,
+			}
+			if msg.Internal {
+				original.Source = sharedgov.MessageSourcePolkassembly
+			}
+			if err := tx.Create(&original).Error; err != nil {
+				return err
+			}
+			latest = 1
+		}
+
+		revision := sharedgov.RefMessageVersion{
+			MessageID: msg.ID,
+			Version:   latest + 1,
+			Body:      body,
+			EditedBy:  editedBy,
+			Source:    source,
+			CreatedAt: now,
+		}
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&sharedgov.RefMessage{}).
+			Where("id = ?", msg.ID).
+			Updates(map[string]interface{}{
+				"body":      body,
+				"edited_at": now,
+			}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	msg.Body = body
+	msg.EditedAt = &now
+	return nil
+}
+
+// MarkFeedbackRemoved records that a message was withdrawn or deleted on Polkassembly.
+func MarkFeedbackRemoved(db *gorm.DB, messageID uint64) error {
+	return db.Model(&sharedgov.RefMessage{}).
+		Where("id = ?", messageID).
+		Update("removed_at", time.Now()).Error
+}
+
 // CountFeedbackMessages returns how many messages exist for a referendum.
 func CountFeedbackMessages(db *gorm.DB, refDBID uint64) (int64, error) {
 	var count int64
@@ -64,14 +288,26 @@ func GetPolkassemblyMessages(db *gorm.DB, refDBID uint64) ([]sharedgov.RefMessag
 	return messages, nil
 }
 
-// SaveExternalPolkassemblyReply persists a reply that originated on Polkassembly.
-func SaveExternalPolkassemblyReply(db *gorm.DB, refDBID uint64, author, body string, userID *int, username string, commentID string, createdAt time.Time) (*sharedgov.RefMessage, error) {
+// GetGuildPolkassemblyMessages returns a guild's live messages that have a Polkassembly comment ID.
+func GetGuildPolkassemblyMessages(db *gorm.DB, refDBID uint64, guildID string) ([]sharedgov.RefMessage, error) {
+	var messages []sharedgov.RefMessage
+	if err := db.Where("ref_id = ? AND guild_id = ? AND removed_at IS NULL AND polkassembly_comment_id IS NOT NULL AND polkassembly_comment_id <> ''", refDBID, guildID).
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// SaveExternalPolkassemblyReply persists a reply that originated on Polkassembly
+// under the guild whose comment it answers.
+func SaveExternalPolkassemblyReply(db *gorm.DB, refDBID uint64, guildID, author, body string, userID *int, username string, commentID, parentID string, createdAt time.Time) (*sharedgov.RefMessage, error) {
 	if commentID == "" {
 		return nil, fmt.Errorf("comment ID cannot be empty")
 	}
 
 	msg := sharedgov.RefMessage{
 		RefID:                refDBID,
+		GuildID:              guildID,
 		Author:               author,
 		Body:                 body,
 		Internal:             true,
@@ -90,6 +326,35 @@ func SaveExternalPolkassemblyReply(db *gorm.DB, refDBID uint64, author, body str
 
 	msgID := commentID
 	msg.PolkassemblyCommentID = &msgID
+	if parentID != "" {
+		msg.PolkassemblyParentID = &parentID
+	}
+
+	if err := db.Create(&msg).Error; err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+// SaveFeedbackReply persists a Discord reply posted to Polkassembly as an
+// answer to another comment.
+func SaveFeedbackReply(db *gorm.DB, refDBID uint64, guildID, authorID, author, body, discordMessageID, commentID, parentID string) (*sharedgov.RefMessage, error) {
+	if commentID == "" || parentID == "" {
+		return nil, fmt.Errorf("comment and parent comment IDs are required")
+	}
+
+	msg := sharedgov.RefMessage{
+		RefID:                 refDBID,
+		GuildID:               guildID,
+		Author:                author,
+		AuthorID:              &authorID,
+		Body:                  body,
+		PolkassemblyCommentID: &commentID,
+		PolkassemblyParentID:  &parentID,
+		DiscordMessageID:      &discordMessageID,
+		CreatedAt:             time.Now(),
+	}
 
 	if err := db.Create(&msg).Error; err != nil {
 		return nil, err
@@ -0,0 +1,88 @@
+package data
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	polkadot "github.com/stake-plus/govcomms/src/polkadot-go"
+)
+
+// decodeProposalCall decodes the referendum's proposal into a JSON call tree
+// for the refs.decoded_call column. Returns nil when decoding is not possible.
+func (ni *NetworkIndexer) decodeProposalCall(refID uint64, refInfo *polkadot.ReferendumInfo) *string {
+	if refInfo == nil || refInfo.Proposal == "" {
+		return nil
+	}
+
+	call, err := polkadot.NewPreimageDecoder(ni.client).DecodeProposal(refInfo)
+	if err != nil {
+		log.Printf("%s ref #%d: decode proposal call: %v", ni.networkName, refID, err)
+		return nil
+	}
+
+	decimals, symbol, err := ni.client.GetTokenInfo()
+	if err != nil {
+		log.Printf("%s indexer: token info unavailable: %v", ni.networkName, err)
+	}
+	if symbol == "" {
+		symbol = ni.symbol
+	}
+
+	data, err := json.Marshal(polkadot.NewProposalCall(refInfo.Proposal, call, decimals, symbol, ni.client.SpendBeneficiary))
+	if err != nil {
+		log.Printf("%s ref #%d: marshal proposal call: %v", ni.networkName, refID, err)
+		return nil
+	}
+
+	encoded := string(data)
+	return &encoded
+}
+
+// projectPassing evaluates an ongoing referendum against its track curves
+// for the refs.pass_projection column. Returns nil when it cannot be computed.
+func (ni *NetworkIndexer) projectPassing(refID uint64, refInfo *polkadot.ReferendumInfo) *string {
+	if refInfo == nil || refInfo.Status != "Ongoing" || refInfo.Tally.Ranked || ni.currentBlock == 0 {
+		return nil
+	}
+
+	projection, err := ni.client.ProjectReferendum(refInfo, ni.currentBlock)
+	if err != nil {
+		log.Printf("%s ref #%d: pass projection: %v", ni.networkName, refID, err)
+		return nil
+	}
+
+	data, err := json.Marshal(projection)
+	if err != nil {
+		log.Printf("%s ref #%d: marshal pass projection: %v", ni.networkName, refID, err)
+		return nil
+	}
+
+	encoded := string(data)
+	return &encoded
+}
+
+// decisionEnd returns the block the referendum's decision period ends at and
+// its estimated time, which is in the past once that block is behind the
+// chain. Returns zero while the referendum is not deciding.
+func (ni *NetworkIndexer) decisionEnd(refID uint64, refInfo *polkadot.ReferendumInfo) (uint64, *time.Time) {
+	if refInfo == nil || refInfo.Status != "Ongoing" || refInfo.Decision == nil || ni.currentBlock == 0 {
+		return 0, nil
+	}
+
+	track, err := ni.client.GetTrackInfo(refInfo.Track)
+	if err != nil {
+		log.Printf("%s ref #%d: decision end: %v", ni.networkName, refID, err)
+		return 0, nil
+	}
+
+	end := uint64(refInfo.Decision.Since) + uint64(track.DecisionPeriod)
+	current := uint64(ni.currentBlock)
+	endsAt := time.Now().UTC()
+	if end > current {
+		endsAt = endsAt.Add(time.Duration(end-current) * ni.blockTime)
+	} else {
+		endsAt = endsAt.Add(-time.Duration(current-end) * ni.blockTime)
+	}
+	return end, &endsAt
+}
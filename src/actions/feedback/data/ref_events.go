@@ -0,0 +1,208 @@
+package data
+
+import (
+	"log"
+	"time"
+
+	polkadot "github.com/stake-plus/govcomms/src/polkadot-go"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+	"gorm.io/gorm/clause"
+)
+
+// Lifecycle phases of an ongoing referendum, used as old/new status in ref_events
+const (
+	phasePreparing  = "Preparing"
+	phaseDeciding   = "Deciding"
+	phaseConfirming = "Confirming"
+)
+
+type refTransition struct {
+	event     string
+	oldStatus string
+	newStatus string
+	block     uint64
+}
+
+// refPhase derives the lifecycle phase from a stored referendum
+func refPhase(ref *sharedgov.Ref) string {
+	if ref.Status != nil && *ref.Status != "Ongoing" {
+		return *ref.Status
+	}
+	switch {
+	case ref.ConfirmStart > 0:
+		return phaseConfirming
+	case ref.DecisionStart > 0:
+		return phaseDeciding
+	default:
+		return phasePreparing
+	}
+}
+
+// lifecycleTransitions compares the stored referendum (nil when new) with
+// fresh chain state and returns the transitions in the order they happened
+func (ni *NetworkIndexer) lifecycleTransitions(old *sharedgov.Ref, info *polkadot.ReferendumInfo) []refTransition {
+	var transitions []refTransition
+	prev := ""
+	if old != nil {
+		prev = refPhase(old)
+	}
+
+	add := func(event, next string, block uint64) {
+		transitions = append(transitions, refTransition{event: event, oldStatus: prev, newStatus: next, block: block})
+		prev = next
+	}
+
+	if old == nil && info.Submitted > 0 {
+		add("Submitted", phasePreparing, uint64(info.Submitted))
+	}
+
+	if info.Status == "Ongoing" {
+		if info.Decision != nil {
+			since := uint64(info.Decision.Since)
+			if old == nil || old.DecisionStart != since {
+				add("DecisionStarted", phaseDeciding, since)
+			}
+			if info.Decision.Confirming != nil {
+				confirming := uint64(*info.Decision.Confirming)
+				if old == nil || old.ConfirmStart != confirming {
+					add("ConfirmStarted", phaseConfirming, ni.confirmStartBlock(info))
+				}
+			} else if old != nil && old.ConfirmStart > 0 {
+				add("ConfirmAborted", phaseDeciding, uint64(ni.currentBlock))
+			}
+		}
+		return transitions
+	}
+
+	if old == nil || prev != info.Status {
+		add(info.Status, info.Status, finishedBlock(info, ni.currentBlock))
+	}
+	return transitions
+}
+
+// confirmStartBlock returns the block confirmation began. The chain stores
+// the block confirmation ends, so the track's confirm period is subtracted.
+func (ni *NetworkIndexer) confirmStartBlock(info *polkadot.ReferendumInfo) uint64 {
+	ends := uint64(*info.Decision.Confirming)
+	track, err := ni.client.GetTrackInfo(info.Track)
+	if err != nil || uint64(track.ConfirmPeriod) > ends {
+		return uint64(ni.currentBlock)
+	}
+	return ends - uint64(track.ConfirmPeriod)
+}
+
+// finishedBlock returns the block a finished referendum reached its final state
+func finishedBlock(info *polkadot.ReferendumInfo, fallback uint32) uint64 {
+	var block uint32
+	switch info.Status {
+	case "Approved":
+		block = info.ApprovedAt
+	case "Rejected":
+		block = info.RejectedAt
+	case "Cancelled":
+		block = info.CancelledAt
+	case "TimedOut":
+		block = info.TimedOutAt
+	case "Killed":
+		block = info.KilledAt
+	}
+	if block == 0 {
+		block = fallback
+	}
+	return uint64(block)
+}
+
+// recordRefEvents appends lifecycle transitions to ref_events. With detailed
+// set, each row is resolved to its block hash, timestamp and the tally at that
+// block; backfilled history of long-finished referenda skips these lookups.
+func (ni *NetworkIndexer) recordRefEvents(ref *sharedgov.Ref, transitions []refTransition, info *polkadot.ReferendumInfo, detailed bool) {
+	if ref.ID == 0 || len(transitions) == 0 {
+		return
+	}
+
+	rows := make([]sharedgov.RefEvent, 0, len(transitions))
+	for _, t := range transitions {
+		row := sharedgov.RefEvent{
+			RefDBID:     ref.ID,
+			NetworkID:   ni.networkID,
+			RefID:       ref.RefID,
+			Event:       t.event,
+			OldStatus:   t.oldStatus,
+			NewStatus:   t.newStatus,
+			BlockNumber: t.block,
+			CreatedAt:   time.Now(),
+		}
+
+		var tally *polkadot.Tally
+		if info.Status == "Ongoing" {
+			tally = &info.Tally
+		}
+
+		if detailed && t.block > 0 {
+			block := t.block
+			if hash, err := ni.client.GetBlockHash(&block); err == nil {
+				row.BlockHash = &hash
+				if ts, err := ni.client.GetBlockTimestamp(hash); err == nil {
+					row.OccurredAt = &ts
+				}
+				if historic := ni.tallyAt(uint32(ref.RefID), block); historic != nil {
+					tally = historic
+				}
+			}
+		}
+
+		if row.OccurredAt == nil {
+			row.OccurredAt = ni.estimateBlockTime(t.block)
+		}
+		if tally != nil {
+			row.Ayes = optionalString(tally.Ayes)
+			row.Nays = optionalString(tally.Nays)
+			row.Support = optionalString(tally.Support)
+		}
+
+		rows = append(rows, row)
+	}
+
+	if err := ni.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+		log.Printf("%s ref #%d: failed to record lifecycle events: %v", ni.networkName, ref.RefID, err)
+		return
+	}
+
+	for _, row := range rows {
+		log.Printf("%s ref #%d: %s (%s -> %s) at block %d", ni.networkName, ref.RefID, row.Event, row.OldStatus, row.NewStatus, row.BlockNumber)
+	}
+}
+
+// tallyAt reads the tally of a referendum as of a block. A finished
+// referendum no longer stores its tally, so the parent block is tried too.
+func (ni *NetworkIndexer) tallyAt(refID uint32, block uint64) *polkadot.Tally {
+	for _, number := range []uint64{block, block - 1} {
+		if number == 0 {
+			continue
+		}
+		hash, err := ni.client.GetBlockHash(&number)
+		if err != nil {
+			return nil
+		}
+		info, err := ni.client.GetReferendumInfoAt(refID, hash)
+		if err != nil {
+			// Pruned state on non-archive nodes
+			return nil
+		}
+		if info.Status == "Ongoing" && info.Tally.Ayes != "" {
+			return &info.Tally
+		}
+	}
+	return nil
+}
+
+// estimateBlockTime approximates a block's time from the current height at
+// the chain's expected block time
+func (ni *NetworkIndexer) estimateBlockTime(block uint64) *time.Time {
+	if block == 0 || ni.currentBlock == 0 || block > uint64(ni.currentBlock) {
+		return nil
+	}
+	blocksAgo := int64(uint64(ni.currentBlock) - block)
+	estimated := time.Now().Add(-time.Duration(blocksAgo) * ni.blockTime).UTC()
+	return &estimated
+}
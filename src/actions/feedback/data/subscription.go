@@ -0,0 +1,297 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	polkadot "github.com/stake-plus/govcomms/src/polkadot-go"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxEventCatchup is the largest block gap replayed from events. Larger
+	// gaps (long downtime, first start) fall back to a full sweep.
+	maxEventCatchup     = 3600
+	resubscribeDelay    = 15 * time.Second
+	healthCheckInterval = time.Minute
+	// fullRefreshInterval is how often every ongoing referendum is re-read,
+	// catching tally changes, which emit no Referenda events
+	fullRefreshInterval = 10 * time.Minute
+)
+
+// followFinalized drives indexing from finalized heads. Referenda named in
+// Referenda events are re-fetched as soon as their block finalizes and every
+// ongoing referendum is re-read once per fullRefreshInterval; the interval
+// tick picks up new referenda and refreshes conviction votes and payouts.
+// When the RPC cannot subscribe (e.g. plain HTTP) the tick also replays
+// missed blocks.
+func (ni *NetworkIndexer) followFinalized(ctx context.Context, interval time.Duration) {
+	ni.checkEndpoints()
+	ni.bootstrap(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	health := time.NewTicker(healthCheckInterval)
+	defer health.Stop()
+	refresh := time.NewTicker(fullRefreshInterval)
+	defer refresh.Stop()
+
+	for {
+		sub, err := ni.client.SubscribeFinalizedHeads()
+		if err != nil {
+			log.Printf("%s indexer: head subscription unavailable, polling every %v: %v", ni.networkName, interval, err)
+		} else {
+			log.Printf("%s indexer: following finalized heads", ni.networkName)
+		}
+
+		if !ni.consume(ctx, sub, ticker, health, refresh) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+// consume processes heads and ticks until the subscription fails or the
+// client fails over (returns true to resubscribe) or the context ends
+// (returns false)
+func (ni *NetworkIndexer) consume(ctx context.Context, sub *polkadot.HeadSubscription, ticker, health, refresh *time.Ticker) bool {
+	var heads <-chan polkadot.FinalizedHead
+	var errs <-chan error
+	if sub != nil {
+		defer sub.Unsubscribe()
+		heads = sub.Heads()
+		errs = sub.Err()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Stopping indexer for %s", ni.networkName)
+			return false
+		case err := <-errs:
+			log.Printf("%s indexer: head subscription failed: %v", ni.networkName, err)
+			ni.checkEndpoints()
+			return true
+		case <-health.C:
+			if ni.checkEndpoints() {
+				return true
+			}
+		case head := <-heads:
+			ni.catchUp(ctx, head.Number)
+		case <-refresh.C:
+			ni.refreshAllOngoing(ctx)
+		case <-ticker.C:
+			if sub == nil {
+				if number, _, err := ni.client.GetFinalizedHead(); err != nil {
+					log.Printf("%s indexer: failed to get finalized head: %v", ni.networkName, err)
+				} else {
+					ni.catchUp(ctx, number)
+				}
+			}
+			ni.refreshOngoing(ctx)
+			if sub == nil {
+				// Retry the subscription on the next tick
+				return true
+			}
+		}
+	}
+}
+
+// bootstrap resumes from the stored checkpoint, replaying events for short
+// gaps and running a full sweep when there is no usable checkpoint
+func (ni *NetworkIndexer) bootstrap(ctx context.Context) {
+	head, hash, err := ni.client.GetFinalizedHead()
+	if err != nil {
+		log.Printf("%s indexer: failed to get finalized head, running full sweep: %v", ni.networkName, err)
+		ni.indexOnce(ctx)
+		return
+	}
+
+	checkpoint, err := ni.loadCheckpoint()
+	if err != nil {
+		log.Printf("%s indexer: failed to load checkpoint: %v", ni.networkName, err)
+	}
+
+	if checkpoint == nil || head < checkpoint.BlockNumber || head-checkpoint.BlockNumber > maxEventCatchup {
+		if checkpoint != nil {
+			log.Printf("%s indexer: checkpoint #%d too far behind finalized #%d, running full sweep",
+				ni.networkName, checkpoint.BlockNumber, head)
+		}
+		ni.indexOnce(ctx)
+		if ctx.Err() == nil {
+			ni.saveCheckpoint(head, hash)
+		}
+		return
+	}
+
+	log.Printf("%s indexer: resuming from checkpoint #%d (finalized #%d)", ni.networkName, checkpoint.BlockNumber, head)
+	ni.lastBlock = checkpoint.BlockNumber
+	ni.catchUp(ctx, head)
+	// Tallies changed while the indexer was down without emitting events
+	if ni.refreshAllOngoing(ctx) {
+		ni.refreshOngoing(ctx)
+	}
+}
+
+// catchUp processes every block after the last checkpoint up to head
+func (ni *NetworkIndexer) catchUp(ctx context.Context, head uint64) {
+	if ni.lastBlock == 0 {
+		// No checkpoint yet (bootstrap swept without a head); start here
+		if hash, err := ni.client.GetBlockHash(&head); err == nil {
+			ni.saveCheckpoint(head, hash)
+		}
+		return
+	}
+	if head <= ni.lastBlock {
+		return
+	}
+
+	if head-ni.lastBlock > maxEventCatchup {
+		log.Printf("%s indexer: fell %d blocks behind, running full sweep", ni.networkName, head-ni.lastBlock)
+		ni.indexOnce(ctx)
+		if ctx.Err() == nil {
+			if hash, err := ni.client.GetBlockHash(&head); err == nil {
+				ni.saveCheckpoint(head, hash)
+			}
+		}
+		return
+	}
+
+	for number := ni.lastBlock + 1; number <= head; number++ {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := ni.processBlock(number); err != nil {
+			log.Printf("%s indexer: block #%d: %v", ni.networkName, number, err)
+			return
+		}
+	}
+}
+
+// processBlock re-fetches the referenda named in a block's Referenda events,
+// records treasury payouts and advances the checkpoint
+func (ni *NetworkIndexer) processBlock(number uint64) error {
+	hash, err := ni.client.GetBlockHash(&number)
+	if err != nil {
+		return fmt.Errorf("get block hash: %w", err)
+	}
+
+	events, err := ni.client.GetEvents(hash, ni.client.ReferendaPallet(), "Treasury")
+	if err != nil {
+		// Leave the checkpoint here so the block is retried with the next
+		// head; a block that keeps failing is eventually covered by the full
+		// sweep once the indexer falls maxEventCatchup blocks behind
+		return fmt.Errorf("get events: %w", err)
+	}
+
+	ni.currentBlock = uint32(number)
+
+	ni.applyTreasuryEvents(number, events)
+
+	changed := make(map[uint32][]string)
+	for _, event := range ni.client.ReferendaEvents(events) {
+		changed[event.RefID] = append(changed[event.RefID], event.Name)
+	}
+
+	refIDs := make([]uint32, 0, len(changed))
+	for refID := range changed {
+		refIDs = append(refIDs, refID)
+	}
+	sort.Slice(refIDs, func(i, j int) bool { return refIDs[i] < refIDs[j] })
+
+	for _, refID := range refIDs {
+		log.Printf("%s indexer: #%d %v on ref #%d", ni.networkName, number, changed[refID], refID)
+		ni.processReferendum(uint64(refID))
+	}
+
+	ni.saveCheckpoint(number, hash)
+	return nil
+}
+
+// refreshOngoing picks up referenda submitted without a decoded event,
+// refreshes conviction votes and treasury payout status and resolves the
+// identities of the accounts involved. Referenda named in events are
+// refreshed by processBlock and the rest by refreshAllOngoing.
+func (ni *NetworkIndexer) refreshOngoing(ctx context.Context) {
+	refCount, err := ni.client.GetReferendumCount()
+	if err != nil {
+		log.Printf("%s indexer: Failed to get referendum count: %v", ni.networkName, err)
+	} else {
+		var maxStored uint64
+		if err := ni.db.Model(&sharedgov.Ref{}).
+			Where("network_id = ?", ni.networkID).
+			Select("COALESCE(MAX(ref_id), 0)").
+			Scan(&maxStored).Error; err == nil {
+			for i := maxStored + 1; i < uint64(refCount); i++ {
+				if ctx.Err() != nil {
+					return
+				}
+				ni.processReferendum(i)
+			}
+		}
+	}
+
+	ni.indexConvictionVoting(ctx)
+	ni.trackTreasurySpends(ctx)
+	ni.resolveIdentities(ctx)
+}
+
+// refreshAllOngoing re-reads every referendum not yet finalized. Returns
+// false when the context ended first.
+func (ni *NetworkIndexer) refreshAllOngoing(ctx context.Context) bool {
+	var ongoingRefs []sharedgov.Ref
+	if err := ni.db.
+		Where("network_id = ?", ni.networkID).
+		Where("(finalized IS NULL OR finalized = ?)", false).
+		Find(&ongoingRefs).Error; err != nil {
+		log.Printf("%s indexer: failed to load ongoing referenda: %v", ni.networkName, err)
+		return true
+	}
+
+	for _, ref := range ongoingRefs {
+		if ctx.Err() != nil {
+			return false
+		}
+		ni.processReferendum(ref.RefID)
+	}
+	return true
+}
+
+func (ni *NetworkIndexer) loadCheckpoint() (*sharedgov.IndexerCheckpoint, error) {
+	var checkpoints []sharedgov.IndexerCheckpoint
+	if err := ni.db.Where("network_id = ?", ni.networkID).Limit(1).Find(&checkpoints).Error; err != nil {
+		return nil, err
+	}
+	if len(checkpoints) == 0 {
+		return nil, nil
+	}
+	return &checkpoints[0], nil
+}
+
+func (ni *NetworkIndexer) saveCheckpoint(number uint64, hash string) {
+	ni.lastBlock = number
+	checkpoint := sharedgov.IndexerCheckpoint{
+		NetworkID:   ni.networkID,
+		BlockNumber: number,
+		BlockHash:   hash,
+		BlockTimeMs: uint32(ni.blockTime.Milliseconds()),
+		UpdatedAt:   time.Now(),
+	}
+
+	err := ni.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "network_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"block_number", "block_hash", "block_time_ms", "updated_at"}),
+	}).Create(&checkpoint).Error
+	if err != nil {
+		log.Printf("%s indexer: failed to save checkpoint #%d: %v", ni.networkName, number, err)
+	}
+}
@@ -0,0 +1,327 @@
+package data
+
+import (
+	"context"
+	"log"
+
+	polkadot "github.com/stake-plus/govcomms/src/polkadot-go"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+)
+
+// spendLinkLimit bounds how many approved treasury referenda are scanned when
+// an unlinked spend shows up on chain
+const spendLinkLimit = 500
+
+type spendKey struct {
+	kind  string
+	index uint32
+}
+
+// trackTreasurySpends links spends in Treasury.Spends and Treasury.Proposals
+// to the approved referenda that created them, then follows each spend until
+// it is paid, fails or expires
+func (ni *NetworkIndexer) trackTreasurySpends(ctx context.Context) {
+	if !ni.client.HasPallet("Treasury") {
+		return
+	}
+
+	spends, err := ni.client.GetTreasurySpends()
+	if err != nil {
+		log.Printf("%s indexer: failed to read treasury spends: %v", ni.networkName, err)
+		return
+	}
+	proposals, err := ni.client.GetTreasuryProposals()
+	if err != nil {
+		log.Printf("%s indexer: failed to read treasury proposals: %v", ni.networkName, err)
+		return
+	}
+
+	var tracked []sharedgov.RefSpend
+	if err := ni.db.Where("network_id = ?", ni.networkID).Find(&tracked).Error; err != nil {
+		log.Printf("%s indexer: failed to load tracked spends: %v", ni.networkName, err)
+		return
+	}
+
+	linked := make(map[spendKey]bool, len(tracked))
+	for _, row := range tracked {
+		linked[spendKey{row.Kind, row.SpendIndex}] = true
+	}
+
+	if ctx.Err() == nil {
+		ni.linkSpends(spends, proposals, linked)
+	}
+
+	spendByIndex := make(map[uint32]polkadot.TreasurySpend, len(spends))
+	for _, spend := range spends {
+		spendByIndex[spend.Index] = spend
+	}
+	proposalByIndex := make(map[uint32]polkadot.TreasuryProposal, len(proposals))
+	for _, proposal := range proposals {
+		proposalByIndex[proposal.Index] = proposal
+	}
+
+	var open []sharedgov.RefSpend
+	if err := ni.db.
+		Where("network_id = ? AND status IN ?", ni.networkID, []string{sharedgov.SpendStatusPending, sharedgov.SpendStatusFailed}).
+		Find(&open).Error; err != nil {
+		log.Printf("%s indexer: failed to load open spends: %v", ni.networkName, err)
+		return
+	}
+
+	for i := range open {
+		row := &open[i]
+		var updates map[string]interface{}
+		if row.Kind == sharedgov.SpendKindSpendLocal {
+			proposal, ok := proposalByIndex[row.SpendIndex]
+			updates = ni.proposalUpdates(row, proposal, ok)
+		} else {
+			spend, ok := spendByIndex[row.SpendIndex]
+			updates = ni.spendUpdates(row, spend, ok)
+		}
+		ni.updateSpend(row, updates)
+	}
+}
+
+// linkSpends matches spends not yet tracked against the Treasury calls of
+// approved referenda that have no tracked spends
+func (ni *NetworkIndexer) linkSpends(spends []polkadot.TreasurySpend, proposals []polkadot.TreasuryProposal, linked map[spendKey]bool) {
+	var unlinked int
+	for _, spend := range spends {
+		if !linked[spendKey{sharedgov.SpendKindSpend, spend.Index}] {
+			unlinked++
+		}
+	}
+	for _, proposal := range proposals {
+		if !linked[spendKey{sharedgov.SpendKindSpendLocal, proposal.Index}] {
+			unlinked++
+		}
+	}
+	if unlinked == 0 {
+		return
+	}
+
+	var refs []sharedgov.Ref
+	if err := ni.db.
+		Where("network_id = ? AND status = ? AND decoded_call LIKE ?", ni.networkID, "Approved", `%"pallet":"Treasury"%`).
+		Where("id NOT IN (?)", ni.db.Model(&sharedgov.RefSpend{}).Select("ref_db_id").Where("network_id = ?", ni.networkID)).
+		Order("ref_id DESC").
+		Limit(spendLinkLimit).
+		Find(&refs).Error; err != nil {
+		log.Printf("%s indexer: failed to load approved treasury referenda: %v", ni.networkName, err)
+		return
+	}
+
+	decimals, symbol, err := ni.client.GetTokenInfo()
+	if err != nil || symbol == "" {
+		symbol = ni.symbol
+	}
+
+	for _, ref := range refs {
+		call, err := polkadot.ParseProposalCall([]byte(*ref.DecodedCall))
+		if err != nil {
+			continue
+		}
+
+		var rows []sharedgov.RefSpend
+		for _, request := range call.SpendRequests() {
+			if request.Call == sharedgov.SpendKindSpendLocal {
+				for _, proposal := range proposals {
+					key := spendKey{sharedgov.SpendKindSpendLocal, proposal.Index}
+					if linked[key] || !request.MatchesProposal(proposal) {
+						continue
+					}
+					linked[key] = true
+					asset, display := polkadot.DescribeSpendAmount(nil, proposal.Value, decimals, symbol)
+					rows = append(rows, sharedgov.RefSpend{
+						Kind:          sharedgov.SpendKindSpendLocal,
+						SpendIndex:    proposal.Index,
+						Asset:         optionalString(asset),
+						Amount:        proposal.Value,
+						AmountDisplay: optionalString(display),
+						Beneficiary:   optionalString(proposal.Beneficiary),
+						Status:        sharedgov.SpendStatusPending,
+					})
+					break
+				}
+				continue
+			}
+
+			for _, spend := range spends {
+				key := spendKey{sharedgov.SpendKindSpend, spend.Index}
+				if linked[key] || !request.MatchesSpend(spend) {
+					continue
+				}
+				linked[key] = true
+				asset, display := polkadot.DescribeSpendAmount(spend.AssetKind, spend.Amount, decimals, symbol)
+				rows = append(rows, sharedgov.RefSpend{
+					Kind:          sharedgov.SpendKindSpend,
+					SpendIndex:    spend.Index,
+					Asset:         optionalString(asset),
+					Amount:        spend.Amount,
+					AmountDisplay: optionalString(display),
+					Beneficiary:   optionalString(ni.client.SpendBeneficiary(spend.Beneficiary)),
+					ValidFrom:     uint64(spend.ValidFrom),
+					ExpireAt:      uint64(spend.ExpireAt),
+					Status:        sharedgov.SpendStatusPending,
+				})
+				break
+			}
+		}
+
+		for i := range rows {
+			rows[i].RefDBID = ref.ID
+			rows[i].NetworkID = ni.networkID
+			rows[i].RefID = ref.RefID
+			if err := ni.db.Create(&rows[i]).Error; err != nil {
+				log.Printf("%s ref #%d: failed to record treasury %s #%d: %v", ni.networkName, ref.RefID, rows[i].Kind, rows[i].SpendIndex, err)
+				continue
+			}
+			log.Printf("%s ref #%d: tracking treasury %s #%d (%s)", ni.networkName, ref.RefID, rows[i].Kind, rows[i].SpendIndex, rows[i].Describe())
+		}
+	}
+}
+
+// spendUpdates derives a Treasury.spend's payout status from its storage
+// entry. A payout can only be attempted before expire_at; an entry removed
+// after an attempt was paid, one removed after expiry expired. Otherwise the
+// SpendProcessed or AssetSpendVoided event recorded by applyTreasuryEvents
+// tells what happened, and without one the spend is only known as cleared.
+func (ni *NetworkIndexer) spendUpdates(row *sharedgov.RefSpend, spend polkadot.TreasurySpend, onChain bool) map[string]interface{} {
+	now := uint64(ni.currentBlock)
+	expired := now > 0 && row.ExpireAt > 0 && now >= row.ExpireAt
+	updates := make(map[string]interface{})
+
+	if !onChain {
+		if row.ChainState == nil && row.Status == sharedgov.SpendStatusFailed {
+			// AssetSpendVoided already recorded the removal
+			return nil
+		}
+		updates["chain_state"] = nil
+		switch {
+		case row.PayoutBlock != nil && row.Status != sharedgov.SpendStatusFailed:
+			updates["status"] = sharedgov.SpendStatusPaid
+		case expired:
+			updates["status"] = sharedgov.SpendStatusExpired
+		default:
+			updates["status"] = sharedgov.SpendStatusCleared
+			updates["note"] = "removed from storage, outcome unknown"
+		}
+		return updates
+	}
+
+	updates["chain_state"] = spend.State
+	switch spend.State {
+	case polkadot.SpendStateAttempted:
+		updates["status"] = sharedgov.SpendStatusPending
+		if spend.PaymentID != nil {
+			updates["payment_id"] = *spend.PaymentID
+		}
+		if row.PayoutBlock == nil && now > 0 {
+			updates["payout_block"] = now
+		}
+	case polkadot.SpendStateFailed:
+		if expired {
+			updates["status"] = sharedgov.SpendStatusExpired
+		} else {
+			updates["status"] = sharedgov.SpendStatusFailed
+		}
+	default:
+		if expired {
+			updates["status"] = sharedgov.SpendStatusExpired
+		} else {
+			updates["status"] = sharedgov.SpendStatusPending
+		}
+	}
+	return updates
+}
+
+// proposalUpdates derives a spend_local proposal's status. Approved
+// proposals are paid and removed at the next spend period.
+func (ni *NetworkIndexer) proposalUpdates(row *sharedgov.RefSpend, proposal polkadot.TreasuryProposal, onChain bool) map[string]interface{} {
+	updates := make(map[string]interface{})
+	switch {
+	case !onChain:
+		updates["status"] = sharedgov.SpendStatusPaid
+		if row.PayoutBlock == nil && ni.currentBlock > 0 {
+			updates["payout_block"] = uint64(ni.currentBlock)
+		}
+	case !proposal.Approved:
+		updates["status"] = sharedgov.SpendStatusFailed
+		updates["note"] = "approval removed"
+	default:
+		updates["status"] = sharedgov.SpendStatusPending
+	}
+	return updates
+}
+
+// applyTreasuryEvents records payouts as their events finalize, which pins
+// the payout block even when check_status clears the spend between refreshes
+func (ni *NetworkIndexer) applyTreasuryEvents(number uint64, events []polkadot.PalletEvent) {
+	for _, event := range events {
+		if event.Pallet != "Treasury" {
+			continue
+		}
+
+		kind := sharedgov.SpendKindSpend
+		indexField := "index"
+		if event.Name == "Awarded" {
+			kind = sharedgov.SpendKindSpendLocal
+			indexField = "proposal_index"
+		}
+		index, ok := event.Uint(indexField)
+		if !ok {
+			continue
+		}
+
+		var row sharedgov.RefSpend
+		if err := ni.db.
+			Where("network_id = ? AND kind = ? AND spend_index = ?", ni.networkID, kind, index).
+			Limit(1).Find(&row).Error; err != nil || row.ID == 0 {
+			continue
+		}
+
+		updates := make(map[string]interface{})
+		switch event.Name {
+		case "Paid":
+			updates["chain_state"] = polkadot.SpendStateAttempted
+			updates["payout_block"] = number
+			if id, ok := event.Uint("payment_id"); ok {
+				updates["payment_id"] = id
+			}
+		case "PaymentFailed":
+			updates["chain_state"] = polkadot.SpendStateFailed
+			updates["status"] = sharedgov.SpendStatusFailed
+		case "SpendProcessed":
+			updates["chain_state"] = nil
+			if row.ChainState != nil && *row.ChainState == polkadot.SpendStateAttempted {
+				updates["status"] = sharedgov.SpendStatusPaid
+			} else {
+				updates["status"] = sharedgov.SpendStatusExpired
+			}
+		case "AssetSpendVoided":
+			updates["chain_state"] = nil
+			updates["status"] = sharedgov.SpendStatusFailed
+			updates["note"] = "spend voided"
+		case "Awarded":
+			updates["status"] = sharedgov.SpendStatusPaid
+			updates["payout_block"] = number
+		default:
+			continue
+		}
+		ni.updateSpend(&row, updates)
+	}
+}
+
+func (ni *NetworkIndexer) updateSpend(row *sharedgov.RefSpend, updates map[string]interface{}) {
+	if len(updates) == 0 {
+		return
+	}
+	previous := row.Status
+	if err := ni.db.Model(row).Updates(updates).Error; err != nil {
+		log.Printf("%s ref #%d: failed to update treasury %s #%d: %v", ni.networkName, row.RefID, row.Kind, row.SpendIndex, err)
+		return
+	}
+	if status, ok := updates["status"].(string); ok && status != previous {
+		log.Printf("%s ref #%d: treasury %s #%d %s -> %s", ni.networkName, row.RefID, row.Kind, row.SpendIndex, previous, status)
+	}
+}
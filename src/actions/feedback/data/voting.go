@@ -0,0 +1,205 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	polkadot "github.com/stake-plus/govcomms/src/polkadot-go"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+	"gorm.io/gorm"
+)
+
+// indexConvictionVoting snapshots per-account votes and delegations for the
+// tracks of all ongoing referenda
+func (ni *NetworkIndexer) indexConvictionVoting(ctx context.Context) {
+	if ni.client.RankedTally() {
+		// Ranked collectives vote per member, not through ConvictionVoting
+		return
+	}
+
+	var ongoing []sharedgov.Ref
+	if err := ni.db.
+		Where("network_id = ? AND status = ?", ni.networkID, "Ongoing").
+		Where("(finalized IS NULL OR finalized = ?)", false).
+		Find(&ongoing).Error; err != nil {
+		log.Printf("%s indexer: failed to load ongoing referenda for votes: %v", ni.networkName, err)
+		return
+	}
+	if len(ongoing) == 0 {
+		return
+	}
+
+	tracks := make(map[uint16]bool)
+	refs := make(map[uint32]bool)
+	for _, ref := range ongoing {
+		if ref.TrackID == nil {
+			continue
+		}
+		tracks[*ref.TrackID] = true
+		refs[uint32(ref.RefID)] = true
+	}
+	if len(tracks) == 0 {
+		return
+	}
+
+	snapshot, err := ni.client.GetConvictionVoting(tracks, refs)
+	if err != nil {
+		log.Printf("%s indexer: conviction voting snapshot failed: %v", ni.networkName, err)
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+		return
+	default:
+	}
+
+	if err := ni.storeConvictionVotes(ongoing, snapshot); err != nil {
+		log.Printf("%s indexer: failed to store conviction votes: %v", ni.networkName, err)
+	}
+	if err := ni.storeDelegations(tracks, snapshot); err != nil {
+		log.Printf("%s indexer: failed to store delegations: %v", ni.networkName, err)
+	}
+
+	log.Printf("%s indexer: indexed %d votes and %d delegations across %d tracks",
+		ni.networkName, len(snapshot.Votes), len(snapshot.Delegations), len(tracks))
+}
+
+// storeConvictionVotes replaces the stored votes of each ongoing referendum
+// with the snapshot, so removed votes disappear
+func (ni *NetworkIndexer) storeConvictionVotes(ongoing []sharedgov.Ref, snapshot *polkadot.VotingSnapshot) error {
+	byRef := make(map[uint64][]polkadot.ConvictionVote)
+	for _, vote := range snapshot.Votes {
+		byRef[uint64(vote.RefID)] = append(byRef[uint64(vote.RefID)], vote)
+	}
+
+	return ni.db.Transaction(func(tx *gorm.DB) error {
+		for _, ref := range ongoing {
+			if ref.TrackID == nil {
+				continue
+			}
+
+			if err := tx.Where("network_id = ? AND ref_id = ?", ni.networkID, ref.RefID).
+				Delete(&sharedgov.ConvictionVote{}).Error; err != nil {
+				return fmt.Errorf("clear votes for ref #%d: %w", ref.RefID, err)
+			}
+
+			votes := byRef[ref.RefID]
+			if len(votes) == 0 {
+				continue
+			}
+
+			rows := make([]sharedgov.ConvictionVote, 0, len(votes))
+			for _, vote := range votes {
+				row := sharedgov.ConvictionVote{
+					RefDBID:          ref.ID,
+					NetworkID:        ni.networkID,
+					RefID:            ref.RefID,
+					Voter:            vote.Account,
+					TrackID:          vote.Track,
+					VoteType:         vote.Type,
+					Conviction:       vote.Conviction,
+					Balance:          optionalString(vote.Balance),
+					AyeBalance:       optionalString(vote.AyeBalance),
+					NayBalance:       optionalString(vote.NayBalance),
+					AbstainBalance:   optionalString(vote.AbstainBalance),
+					DelegatedVotes:   optionalString(vote.DelegatedVotes),
+					DelegatedCapital: optionalString(vote.DelegatedCapital),
+					ClassLock:        classLock(snapshot, vote.Account, vote.Track),
+					SnapshotBlock:    snapshot.BlockHash,
+				}
+				if vote.Type == polkadot.VoteTypeStandard {
+					aye := vote.Aye
+					row.Aye = &aye
+				}
+				rows = append(rows, row)
+			}
+
+			if err := tx.CreateInBatches(rows, 500).Error; err != nil {
+				return fmt.Errorf("insert votes for ref #%d: %w", ref.RefID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// storeDelegations closes delegations that ended or changed and opens rows for
+// new ones, keeping a history of how delegated power moved
+func (ni *NetworkIndexer) storeDelegations(tracks map[uint16]bool, snapshot *polkadot.VotingSnapshot) error {
+	trackIDs := make([]uint16, 0, len(tracks))
+	for track := range tracks {
+		trackIDs = append(trackIDs, track)
+	}
+
+	var active []sharedgov.ConvictionDelegation
+	if err := ni.db.
+		Where("network_id = ? AND ended_at IS NULL AND track_id IN ?", ni.networkID, trackIDs).
+		Find(&active).Error; err != nil {
+		return fmt.Errorf("load active delegations: %w", err)
+	}
+
+	current := make(map[string]polkadot.ConvictionDelegation, len(snapshot.Delegations))
+	for _, d := range snapshot.Delegations {
+		current[delegationKey(d.Account, d.Track)] = d
+	}
+
+	now := time.Now()
+	return ni.db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range active {
+			key := delegationKey(row.Delegator, row.TrackID)
+			d, ok := current[key]
+			if ok && d.Target == row.Target && d.Balance == row.Balance && d.Conviction == row.Conviction {
+				delete(current, key)
+				if err := tx.Model(&row).Update("class_lock", classLock(snapshot, d.Account, d.Track)).Error; err != nil {
+					return fmt.Errorf("update delegation lock: %w", err)
+				}
+				continue
+			}
+			if err := tx.Model(&row).Update("ended_at", now).Error; err != nil {
+				return fmt.Errorf("close delegation: %w", err)
+			}
+		}
+
+		for _, d := range current {
+			row := sharedgov.ConvictionDelegation{
+				NetworkID:  ni.networkID,
+				Delegator:  d.Account,
+				Target:     d.Target,
+				TrackID:    d.Track,
+				Conviction: d.Conviction,
+				Balance:    d.Balance,
+				ClassLock:  classLock(snapshot, d.Account, d.Track),
+				StartedAt:  now,
+			}
+			if err := tx.Create(&row).Error; err != nil {
+				return fmt.Errorf("insert delegation: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func delegationKey(account string, track uint16) string {
+	return fmt.Sprintf("%s|%d", account, track)
+}
+
+func classLock(snapshot *polkadot.VotingSnapshot, account string, track uint16) *string {
+	locks, ok := snapshot.ClassLocks[account]
+	if !ok {
+		return nil
+	}
+	amount, ok := locks[track]
+	if !ok {
+		return nil
+	}
+	return &amount
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,377 @@
+package feedback
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stake-plus/govcomms/src/actions/feedback/data"
+	shareddiscord "github.com/stake-plus/govcomms/src/api/discord"
+	sharedpolkassembly "github.com/stake-plus/govcomms/src/api/polkassembly"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+	"gorm.io/gorm"
+)
+
+// errEditNotRecorded wraps failures to record an edit that already reached
+// Polkassembly
+var errEditNotRecorded = errors.New("the edit could not be recorded")
+
+// HandleEditSlash executes /feedback-edit: the guild's feedback on the
+// thread's referendum is revised on Polkassembly and in the thread, after
+// review when the guild has a review channel.
+func (h *Handler) HandleEditSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if h == nil {
+		return
+	}
+
+	user := i.Member
+	if user == nil || user.User == nil {
+		log.Printf("feedback: edit interaction missing member context")
+		return
+	}
+
+	message := ""
+	for _, opt := range i.ApplicationCommandData().Options {
+		if opt.Name == "message" {
+			message = strings.TrimSpace(opt.StringValue())
+			break
+		}
+	}
+
+	length := utf8.RuneCountInString(message)
+	if length < 10 || length > 5000 {
+		respondFeedbackEphemeral(s, i.Interaction, "Edit Feedback", "Feedback must be between 10 and 5000 characters.")
+		return
+	}
+
+	if err := shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		log.Printf("feedback: failed to acknowledge edit interaction: %v", err)
+		return
+	}
+
+	if h.Deps.EnsureThreadMapping == nil {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback", "Feedback action misconfigured: missing thread mapper.")
+		return
+	}
+
+	threadInfo, err := h.Deps.EnsureThreadMapping(i.ChannelID)
+	if err != nil {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback", "This command must be used in a referendum thread.")
+		return
+	}
+
+	network := h.NetworkManager.GetByID(threadInfo.NetworkID)
+	if network == nil {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback", "Unable to identify the associated network for this thread.")
+		return
+	}
+
+	var ref sharedgov.Ref
+	if err := h.DB.First(&ref, threadInfo.RefDBID).Error; err != nil {
+		log.Printf("feedback: failed to load referendum %d: %v", threadInfo.RefDBID, err)
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback", "Could not load referendum details. Please try again later.")
+		return
+	}
+
+	msg, err := data.GetGuildFeedback(h.DB, ref.ID, threadInfo.GuildID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback",
+				fmt.Sprintf("No feedback has been posted for %s referendum #%d yet.", network.Name, ref.RefID))
+			return
+		}
+		log.Printf("feedback: failed to load feedback for ref %d: %v", ref.ID, err)
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback", "Failed to load the posted feedback. Please try again later.")
+		return
+	}
+
+	if strings.TrimSpace(msg.Body) == message {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback", "The posted feedback already reads like that.")
+		return
+	}
+
+	editorTag := formatDiscordUsername(user.User.Username, user.User.Discriminator)
+
+	// Guilds with a review channel hold edits there until they are approved
+	if h.Guilds != nil {
+		if guild := h.Guilds.Get(i.GuildID); guild != nil && guild.FeedbackReviewChannelID != "" {
+			h.submitEditForReview(s, i, guild.FeedbackReviewChannelID, threadInfo, network, &ref, msg, editorTag, message)
+			return
+		}
+	}
+
+	if err := h.reviseFeedback(s, i.ChannelID, network, &ref, msg, message, editorTag); err != nil {
+		log.Printf("feedback: failed to edit feedback %d: %v", msg.ID, err)
+		if errors.Is(err, errEditNotRecorded) {
+			respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback", "The Polkassembly comment was updated, but the edit could not be recorded.")
+		} else {
+			respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback",
+				fmt.Sprintf("Failed to edit the Polkassembly comment: %v. The feedback was left unchanged.", err))
+		}
+		return
+	}
+
+	log.Printf("feedback: feedback %d for %s ref #%d edited by %s", msg.ID, network.Name, ref.RefID, editorTag)
+	respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback",
+		fmt.Sprintf("✅ The feedback for %s referendum #%d was updated on Polkassembly and in this thread.", network.Name, ref.RefID))
+}
+
+// reviseFeedback replaces the text of posted feedback on Polkassembly,
+// records the revision and shows it in the referendum thread. Polkassembly
+// goes first, so the record never claims an edit that isn't public.
+func (h *Handler) reviseFeedback(s *discordgo.Session, threadID string, network *sharedgov.Network, ref *sharedgov.Ref, msg *sharedgov.RefMessage, body, editorTag string) error {
+	if msg.PolkassemblyCommentID == nil || *msg.PolkassemblyCommentID == "" {
+		return fmt.Errorf("feedback %d has no Polkassembly comment", msg.ID)
+	}
+	if h.Deps.EditPolkassemblyMessage != nil {
+		if err := h.Deps.EditPolkassemblyMessage(msg.GuildID, network, ref, *msg.PolkassemblyCommentID, body); err != nil {
+			return err
+		}
+	}
+
+	previousBody := msg.Body
+	if err := data.ReviseFeedbackMessage(h.DB, msg, body, editorTag, sharedgov.MessageSourceDiscord); err != nil {
+		return fmt.Errorf("%w: %v", errEditNotRecorded, err)
+	}
+
+	if threadID != "" && h.Deps.EditFeedbackMessage != nil {
+		messageID := h.Deps.EditFeedbackMessage(s, threadID, network, ref, msg, previousBody, editorTag)
+		if messageID != "" && (msg.DiscordMessageID == nil || *msg.DiscordMessageID != messageID) {
+			if err := data.SetFeedbackDiscordMessage(h.DB, msg.ID, messageID); err != nil {
+				log.Printf("feedback: failed to record thread message of feedback %d: %v", msg.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// editFeedbackMessage shows revised feedback in its referendum thread and
+// returns the ID of the message now showing it. Feedback that fits one
+// message before and after the edit is edited in place; otherwise the old
+// post points to a new one.
+func (b *Module) editFeedbackMessage(s *discordgo.Session, threadID string, network *sharedgov.Network, ref *sharedgov.Ref, msg *sharedgov.RefMessage, previousBody, editorTag string) string {
+	if network == nil || ref == nil || msg == nil {
+		return ""
+	}
+	title := feedbackTitle(network, ref)
+	footer := fmt.Sprintf("Submitted by %s\n🕒 %s UTC", msg.Author, msg.CreatedAt.UTC().Format(time.RFC822))
+	body := fmt.Sprintf("%s\n\n%s\n✏️ Edited by %s • %s UTC",
+		strings.TrimSpace(msg.Body),
+		footer,
+		editorTag,
+		time.Now().UTC().Format(time.RFC822),
+	)
+
+	payloads := shareddiscord.BuildStyledMessages(title, body, "")
+	if len(payloads) == 0 {
+		return ""
+	}
+
+	if msg.DiscordMessageID != nil && *msg.DiscordMessageID != "" {
+		previousID := *msg.DiscordMessageID
+		if len(payloads) == 1 && len(shareddiscord.BuildStyledMessages(title, strings.TrimSpace(previousBody)+"\n\n"+footer, "")) == 1 {
+			err := editStyledMessage(s, threadID, previousID, payloads[0])
+			if err == nil {
+				return previousID
+			}
+			log.Printf("feedback: failed to edit feedback message %s: %v", previousID, err)
+		} else {
+			notice := shareddiscord.BuildStyledMessage(title, "_This feedback was edited. The current version follows below._")
+			if err := editStyledMessage(s, threadID, previousID, notice); err != nil {
+				log.Printf("feedback: failed to mark feedback message %s as edited: %v", previousID, err)
+			}
+		}
+	}
+
+	messageID, err := sendStyledPayloads(s, threadID, payloads)
+	if err != nil {
+		log.Printf("feedback: failed to post edited feedback message: %v", err)
+	}
+	return messageID
+}
+
+func editStyledMessage(s *discordgo.Session, channelID, messageID string, payload shareddiscord.StyledMessage) error {
+	components := payload.Components
+	if components == nil {
+		components = []discordgo.MessageComponent{}
+	}
+	_, err := shareddiscord.EditMessageComplexNoEmbed(s, &discordgo.MessageEdit{
+		ID:         messageID,
+		Channel:    channelID,
+		Content:    &payload.Content,
+		Components: &components,
+	})
+	return err
+}
+
+// editPolkassemblyMessage replaces the text of a guild's feedback comment
+func (b *Module) editPolkassemblyMessage(guildID string, network *sharedgov.Network, ref *sharedgov.Ref, commentID, message string) error {
+	if b.polkassembly == nil {
+		return fmt.Errorf("polkassembly service is not configured")
+	}
+	if network == nil || ref == nil {
+		return fmt.Errorf("network and referendum are required")
+	}
+	return b.polkassembly.EditComment(guildID, network.Name, int(ref.RefID), commentID, message)
+}
+
+// HandleWithdrawSlash executes /feedback-withdraw: the guild's feedback on
+// the thread's referendum is deleted from Polkassembly and marked withdrawn
+// in the thread. Who may withdraw is governed by the command's permission
+// rules.
+func (h *Handler) HandleWithdrawSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if h == nil {
+		return
+	}
+
+	user := i.Member
+	if user == nil || user.User == nil {
+		log.Printf("feedback: withdraw interaction missing member context")
+		return
+	}
+
+	if err := shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}); err != nil {
+		log.Printf("feedback: failed to acknowledge withdraw interaction: %v", err)
+		return
+	}
+
+	if h.Deps.EnsureThreadMapping == nil {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Withdraw Feedback", "Feedback action misconfigured: missing thread mapper.")
+		return
+	}
+
+	threadInfo, err := h.Deps.EnsureThreadMapping(i.ChannelID)
+	if err != nil {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Withdraw Feedback", "This command must be used in a referendum thread.")
+		return
+	}
+
+	network := h.NetworkManager.GetByID(threadInfo.NetworkID)
+	if network == nil {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Withdraw Feedback", "Unable to identify the associated network for this thread.")
+		return
+	}
+
+	var ref sharedgov.Ref
+	if err := h.DB.First(&ref, threadInfo.RefDBID).Error; err != nil {
+		log.Printf("feedback: failed to load referendum %d: %v", threadInfo.RefDBID, err)
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Withdraw Feedback", "Could not load referendum details. Please try again later.")
+		return
+	}
+
+	msg, err := data.GetGuildFeedback(h.DB, ref.ID, threadInfo.GuildID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondFeedbackWithStyledEdit(s, i.Interaction, "Withdraw Feedback",
+				fmt.Sprintf("No feedback has been posted for %s referendum #%d.", network.Name, ref.RefID))
+			return
+		}
+		log.Printf("feedback: failed to load feedback for ref %d: %v", ref.ID, err)
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Withdraw Feedback", "Failed to load the posted feedback. Please try again later.")
+		return
+	}
+
+	if h.Deps.DeletePolkassemblyMessage == nil {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Withdraw Feedback", "Polkassembly is not configured, so the feedback cannot be withdrawn.")
+		return
+	}
+	if err := h.Deps.DeletePolkassemblyMessage(msg.GuildID, network, &ref, *msg.PolkassemblyCommentID); err != nil {
+		log.Printf("feedback: failed to withdraw polkassembly comment of feedback %d: %v", msg.ID, err)
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Withdraw Feedback",
+			fmt.Sprintf("Failed to delete the Polkassembly comment: %v. The feedback is still published.", err))
+		return
+	}
+
+	if err := data.MarkFeedbackRemoved(h.DB, msg.ID); err != nil {
+		log.Printf("feedback: failed to mark feedback %d as removed: %v", msg.ID, err)
+	}
+
+	withdrawnBy := formatDiscordUsername(user.User.Username, user.User.Discriminator)
+	if msg.DiscordMessageID != nil && *msg.DiscordMessageID != "" {
+		notice := shareddiscord.BuildStyledMessage(feedbackTitle(network, &ref), fmt.Sprintf("_This feedback was withdrawn by %s._", withdrawnBy))
+		if err := editStyledMessage(s, i.ChannelID, *msg.DiscordMessageID, notice); err != nil {
+			log.Printf("feedback: failed to mark feedback message %s as withdrawn: %v", *msg.DiscordMessageID, err)
+		}
+	}
+
+	log.Printf("feedback: feedback %d for %s ref #%d withdrawn by %s, polkassembly comment deleted", msg.ID, network.Name, ref.RefID, withdrawnBy)
+	respondFeedbackWithStyledEdit(s, i.Interaction, "Feedback Withdrawn",
+		fmt.Sprintf("The feedback for %s referendum #%d was deleted from Polkassembly. New feedback can be posted with /feedback.", network.Name, ref.RefID))
+}
+
+// deletePolkassemblyMessage deletes a guild's feedback comment
+func (b *Module) deletePolkassemblyMessage(guildID string, network *sharedgov.Network, ref *sharedgov.Ref, commentID string) error {
+	if b.polkassembly == nil {
+		return fmt.Errorf("polkassembly service is not configured")
+	}
+	if network == nil || ref == nil {
+		return fmt.Errorf("network and referendum are required")
+	}
+	return b.polkassembly.DeleteComment(guildID, network.Name, int(ref.RefID), commentID)
+}
+
+// syncEditedPolkassemblyReply records a reply edited on Polkassembly and
+// refreshes its announcement
+func (b *Module) syncEditedPolkassemblyReply(threadID string, network *sharedgov.Network, ref *sharedgov.Ref, reply *sharedgov.RefMessage, comment sharedpolkassembly.Comment) {
+	if err := data.ReviseFeedbackMessage(b.db, reply, comment.Content, comment.User.Username, sharedgov.MessageSourcePolkassembly); err != nil {
+		log.Printf("feedback: failed to record edit of polkassembly reply %q: %v", comment.ID, err)
+		return
+	}
+	log.Printf("feedback: polkassembly reply %q for ref %d was edited", comment.ID, ref.RefID)
+
+	embed := polkassemblyReplyEmbed(network, ref, comment)
+	embed.Footer.Text = "Polkassembly • edited"
+	b.updateReplyAnnouncement(threadID, reply, embed)
+}
+
+// syncDeletedPolkassemblyReply marks a reply deleted on Polkassembly and
+// strikes through its announcement
+func (b *Module) syncDeletedPolkassemblyReply(threadID string, network *sharedgov.Network, ref *sharedgov.Ref, reply *sharedgov.RefMessage) {
+	if err := data.MarkFeedbackRemoved(b.db, reply.ID); err != nil {
+		log.Printf("feedback: failed to mark polkassembly reply %d as removed: %v", reply.ID, err)
+		return
+	}
+	log.Printf("feedback: polkassembly reply %q for ref %d was deleted", *reply.PolkassemblyCommentID, ref.RefID)
+
+	comment := sharedpolkassembly.Comment{
+		ID:        *reply.PolkassemblyCommentID,
+		CreatedAt: reply.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	comment.User.Username = reply.PolkassemblyUsername
+
+	var struck []string
+	for _, line := range strings.Split(reply.Body, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			struck = append(struck, "~~"+line+"~~")
+		}
+	}
+	comment.Content = strings.Join(struck, "\n")
+
+	embed := polkassemblyReplyEmbed(network, ref, comment)
+	embed.Footer.Text = "Polkassembly • deleted"
+	b.updateReplyAnnouncement(threadID, reply, embed)
+}
+
+func (b *Module) updateReplyAnnouncement(threadID string, reply *sharedgov.RefMessage, embed *discordgo.MessageEmbed) {
+	if b.session == nil || threadID == "" || reply.DiscordMessageID == nil || *reply.DiscordMessageID == "" {
+		return
+	}
+	if _, err := shareddiscord.EditMessageComplexNoEmbed(b.session, &discordgo.MessageEdit{
+		ID:      *reply.DiscordMessageID,
+		Channel: threadID,
+		Embeds:  &[]*discordgo.MessageEmbed{embed},
+	}); err != nil {
+		log.Printf("feedback: failed to update announcement of polkassembly reply %d: %v", reply.ID, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"unicode/utf8"
 
 	"github.com/bwmarrin/discordgo"
@@ -16,18 +17,25 @@ import (
 
 // Dependencies defines callbacks required from the feedback bot.
 type Dependencies struct {
-	EnsureThreadMapping     func(channelID string) (*sharedgov.ThreadInfo, error)
-	PostFeedbackMessage     func(s *discordgo.Session, threadID string, network *sharedgov.Network, ref *sharedgov.Ref, authorTag, message string)
-	PostPolkassemblyMessage func(network *sharedgov.Network, ref *sharedgov.Ref, message string) (string, error)
+	EnsureThreadMapping       func(channelID string) (*sharedgov.ThreadInfo, error)
+	PostFeedbackMessage       func(s *discordgo.Session, threadID string, network *sharedgov.Network, ref *sharedgov.Ref, authorTag, message string) string
+	EditFeedbackMessage       func(s *discordgo.Session, threadID string, network *sharedgov.Network, ref *sharedgov.Ref, msg *sharedgov.RefMessage, previousBody, editorTag string) string
+	PostPolkassemblyMessage   func(guildID string, network *sharedgov.Network, ref *sharedgov.Ref, message string) (string, error)
+	EditPolkassemblyMessage   func(guildID string, network *sharedgov.Network, ref *sharedgov.Ref, commentID, message string) error
+	DeletePolkassemblyMessage func(guildID string, network *sharedgov.Network, ref *sharedgov.Ref, commentID string) error
+	ReplyToPolkassembly       func(guildID string, network *sharedgov.Network, ref *sharedgov.Ref, parentCommentID, message string) (string, error)
 }
 
-// Handler encapsulates the /feedback action.
+// Handler encapsulates the /feedback, /feedback-edit, /feedback-withdraw, /vote and /link-wallet actions.
 type Handler struct {
 	Config         *sharedconfig.FeedbackConfig
 	DB             *gorm.DB
 	NetworkManager *sharedgov.NetworkManager
 	RefManager     *sharedgov.ReferendumManager
+	Guilds         *sharedgov.GuildManager
 	Deps           Dependencies
+
+	voteMu sync.Mutex // serialises DAO tally updates
 }
 
 // HandleSlash executes the /feedback logic.
@@ -63,18 +71,6 @@ func (h *Handler) HandleSlash(s *discordgo.Session, i *discordgo.InteractionCrea
 		return
 	}
 
-	if h.Config.FeedbackRoleID != "" && !shareddiscord.HasRole(s, h.Config.Base.GuildID, user.User.ID, h.Config.FeedbackRoleID) {
-		formatted := shareddiscord.FormatStyledBlock("Feedback", "You don't have permission to use this command.")
-		shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: formatted,
-				Flags:   discordgo.MessageFlagsEphemeral,
-			},
-		})
-		return
-	}
-
 	if err := shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
 	}); err != nil {
@@ -106,8 +102,8 @@ func (h *Handler) HandleSlash(s *discordgo.Session, i *discordgo.InteractionCrea
 		return
 	}
 
-	// Check if feedback has already been posted for this referendum
-	existingMessages, err := data.GetPolkassemblyMessages(h.DB, ref.ID)
+	// Check if this guild has already posted feedback for this referendum
+	existingMessages, err := data.GetGuildPolkassemblyMessages(h.DB, ref.ID, threadInfo.GuildID)
 	if err != nil {
 		log.Printf("feedback: failed to check existing messages: %v", err)
 		respondFeedbackWithStyledEdit(s, i.Interaction, "Feedback", "Failed to check existing feedback. Please try again later.")
@@ -129,10 +125,30 @@ func (h *Handler) HandleSlash(s *discordgo.Session, i *discordgo.InteractionCrea
 		}
 	}
 
+	pending, err := data.HasPendingFeedback(h.DB, ref.ID, threadInfo.GuildID)
+	if err != nil {
+		log.Printf("feedback: failed to check pending feedback: %v", err)
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Feedback", "Failed to check existing feedback. Please try again later.")
+		return
+	}
+	if pending {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Feedback",
+			fmt.Sprintf("Feedback for %s referendum #%d is already waiting for review.", network.Name, ref.RefID))
+		return
+	}
+
 	authorTag := formatDiscordUsername(user.User.Username, user.User.Discriminator)
 
+	// Guilds with a review channel hold feedback there until it is approved
+	if h.Guilds != nil {
+		if guild := h.Guilds.Get(i.GuildID); guild != nil && guild.FeedbackReviewChannelID != "" {
+			h.submitFeedbackForReview(s, i, guild.FeedbackReviewChannelID, threadInfo, network, &ref, authorTag, message)
+			return
+		}
+	}
+
 	// Save the message first
-	savedMsg, err := data.SaveFeedbackMessage(h.DB, &ref, authorTag, message)
+	savedMsg, err := data.SaveFeedbackMessage(h.DB, &ref, threadInfo.GuildID, authorTag, message)
 	if err != nil {
 		log.Printf("feedback: failed to persist message: %v", err)
 		respondFeedbackWithStyledEdit(s, i.Interaction, "Feedback", "Failed to store feedback. Please try again later.")
@@ -141,7 +157,7 @@ func (h *Handler) HandleSlash(s *discordgo.Session, i *discordgo.InteractionCrea
 
 	// Post immediately to Polkassembly (synchronously)
 	if h.Deps.PostPolkassemblyMessage != nil {
-		commentID, postErr := h.Deps.PostPolkassemblyMessage(network, &ref, savedMsg.Body)
+		commentID, postErr := h.Deps.PostPolkassemblyMessage(threadInfo.GuildID, network, &ref, savedMsg.Body)
 		if postErr != nil {
 			log.Printf("feedback: failed to post to polkassembly: %v", postErr)
 			respondFeedbackWithStyledEdit(s, i.Interaction, "Feedback",
@@ -160,7 +176,11 @@ func (h *Handler) HandleSlash(s *discordgo.Session, i *discordgo.InteractionCrea
 	}
 
 	if h.Deps.PostFeedbackMessage != nil {
-		h.Deps.PostFeedbackMessage(s, i.ChannelID, network, &ref, authorTag, message)
+		if messageID := h.Deps.PostFeedbackMessage(s, i.ChannelID, network, &ref, authorTag, message); messageID != "" {
+			if err := data.SetFeedbackDiscordMessage(h.DB, savedMsg.ID, messageID); err != nil {
+				log.Printf("feedback: failed to record thread message of feedback %d: %v", savedMsg.ID, err)
+			}
+		}
 	}
 
 	response := fmt.Sprintf("✅ Thank you %s! Your feedback for %s referendum #%d has been posted to Polkassembly.",
@@ -180,6 +200,16 @@ func respondFeedbackWithStyledEdit(s *discordgo.Session, interaction *discordgo.
 	shareddiscord.InteractionResponseEditNoEmbed(s, interaction, edit)
 }
 
+func respondFeedbackEphemeral(s *discordgo.Session, interaction *discordgo.Interaction, title, body string) {
+	shareddiscord.InteractionRespondNoEmbed(s, interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: shareddiscord.FormatStyledBlock(title, body),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
 // formatDiscordUsername formats a Discord username, handling the deprecated discriminator field
 func formatDiscordUsername(username, discriminator string) string {
 	if discriminator == "" || discriminator == "0" {
@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/stake-plus/govcomms/src/actions/core"
 	"github.com/stake-plus/govcomms/src/actions/feedback/data"
+	aicore "github.com/stake-plus/govcomms/src/api/ai/core"
 	shareddiscord "github.com/stake-plus/govcomms/src/api/discord"
 	sharedpolkassembly "github.com/stake-plus/govcomms/src/api/polkassembly"
+	cache "github.com/stake-plus/govcomms/src/data/cache"
 	sharedconfig "github.com/stake-plus/govcomms/src/data/config"
 	shareddata "github.com/stake-plus/govcomms/src/data/mysql"
 	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
@@ -28,12 +31,20 @@ type Module struct {
 	handler        *Handler
 	networkManager *sharedgov.NetworkManager
 	refManager     *sharedgov.ReferendumManager
+	guilds         *sharedgov.GuildManager
+	permissions    *shareddiscord.PermissionManager
 	polkassembly   *sharedpolkassembly.Service
+	cacheManager   *cache.Manager
+	tldrClient     aicore.Client // built on first use, shared by thread TL;DRs
+	tldrMu         sync.Mutex
 	runtimeCtx     context.Context
 	cancel         context.CancelFunc
 }
 
-const polkassemblyReplyColor = 0xF39C12
+const (
+	polkassemblyReplyColor = 0xF39C12
+	spendExpiredColor      = 0xEF4444
+)
 
 func (b *Module) ensureNetworkManager() error {
 	if b.networkManager != nil {
@@ -67,6 +78,14 @@ func NewModule(cfg *sharedconfig.FeedbackConfig, db *gorm.DB) (*Module, error) {
 	}
 
 	refManager := sharedgov.NewReferendumManager(db)
+	guilds, err := sharedgov.NewGuildManager(db, sharedconfig.LegacyGuild(db))
+	if err != nil {
+		return nil, fmt.Errorf("feedback: load guilds: %w", err)
+	}
+	permissions, err := shareddiscord.NewPermissionManager(db)
+	if err != nil {
+		log.Printf("feedback: command permissions unavailable, using the feedback role only: %v", err)
+	}
 
 	var paService *sharedpolkassembly.Service
 	if networkManager != nil {
@@ -75,7 +94,7 @@ func NewModule(cfg *sharedconfig.FeedbackConfig, db *gorm.DB) (*Module, error) {
 				Endpoint: cfg.PolkassemblyEndpoint,
 				Logger:   log.Default(),
 			},
-			networkManager.GetAll(),
+			polkassemblyAccounts(guilds, networkManager),
 		); svcErr != nil {
 			log.Printf("feedback: polkassembly service disabled: %v", svcErr)
 		} else {
@@ -92,6 +111,8 @@ func NewModule(cfg *sharedconfig.FeedbackConfig, db *gorm.DB) (*Module, error) {
 		session:        session,
 		networkManager: networkManager,
 		refManager:     refManager,
+		guilds:         guilds,
+		permissions:    permissions,
 		polkassembly:   paService,
 	}
 
@@ -100,10 +121,15 @@ func NewModule(cfg *sharedconfig.FeedbackConfig, db *gorm.DB) (*Module, error) {
 		DB:             db,
 		NetworkManager: networkManager,
 		RefManager:     refManager,
+		Guilds:         guilds,
 		Deps: Dependencies{
-			EnsureThreadMapping:     module.ensureThreadMapping,
-			PostFeedbackMessage:     module.postFeedbackMessage,
-			PostPolkassemblyMessage: module.postPolkassemblyMessage,
+			EnsureThreadMapping:       module.ensureThreadMapping,
+			PostFeedbackMessage:       module.postFeedbackMessage,
+			EditFeedbackMessage:       module.editFeedbackMessage,
+			PostPolkassemblyMessage:   module.postPolkassemblyMessage,
+			EditPolkassemblyMessage:   module.editPolkassemblyMessage,
+			DeletePolkassemblyMessage: module.deletePolkassemblyMessage,
+			ReplyToPolkassembly:       module.replyToPolkassemblyComment,
 		},
 	}
 
@@ -119,16 +145,19 @@ func (b *Module) initHandlers() {
 	b.session.AddHandler(b.onInteractionCreate)
 	b.session.AddHandler(b.onThreadCreate)
 	b.session.AddHandler(b.onThreadUpdate)
+	b.session.AddHandler(b.onMessageCreate)
 }
 
 func (b *Module) onReady(s *discordgo.Session, r *discordgo.Ready) {
 	username := formatDiscordUsername(s.State.User.Username, s.State.User.Discriminator)
 	log.Printf("Feedback bot logged in as: %v", username)
 
-	if err := shareddiscord.RegisterSlashCommands(s, b.config.Base.GuildID, shareddiscord.CommandFeedback); err != nil {
-		log.Printf("feedback: failed to register slash commands: %v", err)
-	} else {
-		log.Printf("feedback: slash command registered")
+	for _, guild := range b.guilds.Guilds() {
+		if err := shareddiscord.RegisterSlashCommands(s, guild.GuildID, shareddiscord.CommandFeedback, shareddiscord.CommandFeedbackEdit, shareddiscord.CommandFeedbackWithdraw, shareddiscord.CommandVote, shareddiscord.CommandLinkWallet); err != nil {
+			log.Printf("feedback: failed to register slash commands in guild %s: %v", guild.GuildID, err)
+		} else {
+			log.Printf("feedback: slash commands registered in guild %s", guild.GuildID)
+		}
 	}
 
 	if b.runtimeCtx != nil {
@@ -141,15 +170,72 @@ func (b *Module) onReady(s *discordgo.Session, r *discordgo.Ready) {
 }
 
 func (b *Module) onInteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if i.Type != discordgo.InteractionApplicationCommand {
+	guild := b.guilds.Get(i.GuildID)
+	if guild == nil {
 		return
 	}
 
-	if i.ApplicationCommandData().Name != shareddiscord.CommandFeedback {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+	case discordgo.InteractionMessageComponent:
+		if IsFeedbackReviewInteraction(i.MessageComponentData().CustomID) && b.allowReview(s, i, guild) {
+			b.handler.HandleReviewComponent(s, i)
+		}
+		return
+	case discordgo.InteractionModalSubmit:
+		if IsFeedbackReviewInteraction(i.ModalSubmitData().CustomID) && b.allowReview(s, i, guild) {
+			b.handler.HandleReviewModal(s, i)
+		}
+		return
+	default:
 		return
 	}
 
-	b.handler.HandleSlash(s, i)
+	name := i.ApplicationCommandData().Name
+	switch name {
+	case shareddiscord.CommandFeedback, shareddiscord.CommandFeedbackEdit, shareddiscord.CommandFeedbackWithdraw, shareddiscord.CommandVote, shareddiscord.CommandLinkWallet:
+	default:
+		// Commands of other modules sharing the bot token
+		return
+	}
+
+	// /vote checks DAO membership itself and has no default role
+	defaultRoleID := guild.FeedbackRoleID
+	if name == shareddiscord.CommandVote {
+		defaultRoleID = ""
+	}
+	if decision := shareddiscord.CheckCommandPermission(s, b.permissions, guild.GuildID, i, defaultRoleID); !decision.Allowed {
+		if err := shareddiscord.RespondPermissionDenied(s, i.Interaction, "Permission Denied", decision.Reason); err != nil {
+			log.Printf("feedback: permission denied response failed: %v", err)
+		}
+		return
+	}
+
+	switch name {
+	case shareddiscord.CommandFeedback:
+		b.handler.HandleSlash(s, i)
+	case shareddiscord.CommandFeedbackEdit:
+		b.handler.HandleEditSlash(s, i)
+	case shareddiscord.CommandFeedbackWithdraw:
+		b.handler.HandleWithdrawSlash(s, i)
+	case shareddiscord.CommandVote:
+		b.handler.HandleVoteSlash(s, i)
+	case shareddiscord.CommandLinkWallet:
+		b.handler.HandleLinkWalletSlash(s, i)
+	}
+}
+
+// allowReview checks that a user may act on the feedback review queue. The
+// feedback-review rules apply, defaulting to the guild's feedback role.
+func (b *Module) allowReview(s *discordgo.Session, i *discordgo.InteractionCreate, guild *sharedgov.Guild) bool {
+	decision := shareddiscord.CheckActionPermission(s, b.permissions, guild.GuildID, i, FeedbackReviewAction, guild.FeedbackRoleID)
+	if decision.Allowed {
+		return true
+	}
+	if err := shareddiscord.RespondPermissionDenied(s, i.Interaction, "Permission Denied", decision.Reason); err != nil {
+		log.Printf("feedback: permission denied response failed: %v", err)
+	}
+	return false
 }
 
 func (b *Module) onThreadCreate(s *discordgo.Session, t *discordgo.ThreadCreate) {
@@ -173,6 +259,13 @@ func (b *Module) Start(ctx context.Context) error {
 	b.cancel = cancel
 	b.runtimeCtx = runtimeCtx
 
+	// No approval can be in flight before the session opens
+	if reopened, err := data.ReopenStalledFeedback(b.db); err != nil {
+		log.Printf("feedback: failed to reopen stalled feedback reviews: %v", err)
+	} else if reopened > 0 {
+		log.Printf("feedback: returned %d feedback drafts stuck in approval to review", reopened)
+	}
+
 	if err := b.session.Open(); err != nil {
 		return fmt.Errorf("failed to open Discord connection: %w", err)
 	}
@@ -205,22 +298,34 @@ func (b *Module) Stop(ctx context.Context) {
 	}
 }
 
-func (b *Module) postFeedbackMessage(s *discordgo.Session, threadID string, network *sharedgov.Network, ref *sharedgov.Ref, authorTag, message string) {
+// postFeedbackMessage posts feedback to its referendum thread and returns
+// the ID of the first message
+func (b *Module) postFeedbackMessage(s *discordgo.Session, threadID string, network *sharedgov.Network, ref *sharedgov.Ref, authorTag, message string) string {
 	if network == nil || ref == nil {
-		return
+		return ""
 	}
-	title := fmt.Sprintf("Feedback • %s #%d", network.Name, ref.RefID)
+	title := feedbackTitle(network, ref)
 	body := fmt.Sprintf("%s\n\nSubmitted by %s\n🕒 %s UTC",
 		strings.TrimSpace(message),
 		authorTag,
 		time.Now().UTC().Format(time.RFC822),
 	)
 
-	payloads := shareddiscord.BuildStyledMessages(title, body, "")
-	if len(payloads) == 0 {
-		return
+	messageID, err := sendStyledPayloads(s, threadID, shareddiscord.BuildStyledMessages(title, body, ""))
+	if err != nil {
+		log.Printf("feedback: failed to post feedback message: %v", err)
 	}
+	return messageID
+}
 
+func feedbackTitle(network *sharedgov.Network, ref *sharedgov.Ref) string {
+	return fmt.Sprintf("Feedback • %s #%d", network.Name, ref.RefID)
+}
+
+// sendStyledPayloads posts styled messages in order and returns the ID of
+// the first one
+func sendStyledPayloads(s *discordgo.Session, channelID string, payloads []shareddiscord.StyledMessage) (string, error) {
+	firstID := ""
 	for _, payload := range payloads {
 		msg := &discordgo.MessageSend{
 			Content: payload.Content,
@@ -228,11 +333,15 @@ func (b *Module) postFeedbackMessage(s *discordgo.Session, threadID string, netw
 		if len(payload.Components) > 0 {
 			msg.Components = payload.Components
 		}
-		if _, err := shareddiscord.SendComplexMessageNoEmbed(s, threadID, msg); err != nil {
-			log.Printf("feedback: failed to post feedback message: %v", err)
-			return
+		sent, err := shareddiscord.SendComplexMessageNoEmbed(s, channelID, msg)
+		if err != nil {
+			return firstID, err
+		}
+		if firstID == "" {
+			firstID = sent.ID
 		}
 	}
+	return firstID, nil
 }
 
 func (b *Module) ensureThreadMapping(channelID string) (*sharedgov.ThreadInfo, error) {
@@ -270,17 +379,21 @@ func (b *Module) processThread(thread *discordgo.Channel) error {
 		return fmt.Errorf("network manager not initialized: %w", err)
 	}
 
-	network := b.networkManager.FindByChannelID(thread.ParentID)
-	if network == nil {
+	link := b.guilds.FindByChannelID(thread.ParentID)
+	if link == nil {
 		return fmt.Errorf("no network configured for channel %s", thread.ParentID)
 	}
+	network := b.networkManager.GetByID(link.NetworkID)
+	if network == nil {
+		return fmt.Errorf("unknown network %d for channel %s", link.NetworkID, thread.ParentID)
+	}
 
 	refID, err := sharedgov.ParseRefIDFromTitle(thread.Name)
 	if err != nil {
 		return fmt.Errorf("failed to parse referendum id from thread %s: %w", thread.ID, err)
 	}
 
-	if err := b.refManager.UpsertThreadMapping(network.ID, refID, thread.ID); err != nil {
+	if err := b.refManager.UpsertThreadMapping(link.GuildID, network.ID, refID, thread.ID); err != nil {
 		return fmt.Errorf("failed to upsert thread mapping: %w", err)
 	}
 
@@ -300,6 +413,10 @@ func (b *Module) startReferendumSync(ctx context.Context) {
 		if err := b.syncActiveThreads(ctx); err != nil {
 			log.Printf("feedback: referendum sync failed: %v", err)
 		}
+		b.createReferendumThreads(ctx)
+		b.alertExpiredSpends()
+		b.handler.closeDaoVotes(b.session)
+		b.handler.notifyReferendumUpdates(b.session)
 
 		select {
 		case <-ctx.Done():
@@ -310,22 +427,121 @@ func (b *Module) startReferendumSync(ctx context.Context) {
 }
 
 func (b *Module) syncActiveThreads(ctx context.Context) error {
-	if b.config.Base.GuildID == "" {
+	guilds := b.guilds.Guilds()
+	if len(guilds) == 0 {
 		return fmt.Errorf("guild id not configured")
 	}
 
-	threads, err := b.session.GuildThreadsActive(b.config.Base.GuildID)
-	if err != nil {
-		return err
+	var errs []error
+	for _, guild := range guilds {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		threads, err := b.session.GuildThreadsActive(guild.GuildID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("guild %s: %w", guild.GuildID, err))
+			continue
+		}
+
+		for _, thread := range threads.Threads {
+			// Only threads in mapped channels belong to a referendum
+			if b.guilds.FindByChannelID(thread.ParentID) == nil {
+				continue
+			}
+			if err := b.processThread(thread); err != nil {
+				log.Printf("feedback: failed to sync thread %s: %v", thread.ID, err)
+			}
+		}
 	}
 
-	for _, thread := range threads.Threads {
-		if err := b.processThread(thread); err != nil {
-			log.Printf("feedback: failed to sync thread %s: %v", thread.ID, err)
+	return errors.Join(errs...)
+}
+
+// polkassemblyAccounts lists the Polkassembly logins of every guild, with
+// the guild's feedback intro and outro
+func polkassemblyAccounts(guilds *sharedgov.GuildManager, networks *sharedgov.NetworkManager) []sharedpolkassembly.Account {
+	var accounts []sharedpolkassembly.Account
+	for _, link := range guilds.Accounts() {
+		network := networks.GetByID(link.NetworkID)
+		if network == nil {
+			continue
+		}
+		account := sharedpolkassembly.Account{
+			GuildID: link.GuildID,
+			Network: network.Name,
+			Seed:    link.PolkassemblySeed,
 		}
+		if guild := guilds.Get(link.GuildID); guild != nil {
+			if guild.PolkassemblyIntro != nil {
+				account.Intro = *guild.PolkassemblyIntro
+			}
+			if guild.PolkassemblyOutro != nil {
+				account.Outro = *guild.PolkassemblyOutro
+			}
+		}
+		accounts = append(accounts, account)
 	}
+	return accounts
+}
 
-	return nil
+// alertExpiredSpends posts once in the referendum thread for each treasury
+// spend that expired without being claimed
+func (b *Module) alertExpiredSpends() {
+	if err := b.ensureNetworkManager(); err != nil {
+		log.Printf("feedback: expired spend alerts skipped: %v", err)
+		return
+	}
+
+	var spends []sharedgov.RefSpend
+	if err := b.db.
+		Where("status = ? AND alerted_at IS NULL", sharedgov.SpendStatusExpired).
+		Order("id").
+		Find(&spends).Error; err != nil {
+		log.Printf("feedback: failed to load expired spends: %v", err)
+		return
+	}
+
+	for i := range spends {
+		spend := &spends[i]
+		network := b.networkManager.GetByID(spend.NetworkID)
+		if network == nil {
+			continue
+		}
+
+		threads, err := b.refManager.ListThreads(spend.NetworkID, uint32(spend.RefID))
+		if err != nil {
+			log.Printf("feedback: failed to load threads for %s ref #%d: %v", network.Name, spend.RefID, err)
+			continue
+		}
+		if len(threads) == 0 {
+			log.Printf("feedback: no thread for %s ref #%d, skipping expired spend alert", network.Name, spend.RefID)
+		}
+		posted := 0
+		for _, thread := range threads {
+			embed := &discordgo.MessageEmbed{
+				Title:       fmt.Sprintf("Treasury spend expired • %s #%d", network.Name, spend.RefID),
+				Description: spend.StatusLine(),
+				Color:       spendExpiredColor,
+				Timestamp:   time.Now().UTC().Format(time.RFC3339),
+			}
+			if _, err := shareddiscord.SendComplexMessageNoEmbed(b.session, thread.ThreadID, &discordgo.MessageSend{
+				Embeds: []*discordgo.MessageEmbed{embed},
+			}); err != nil {
+				log.Printf("feedback: failed to post expired spend alert for %s ref #%d in thread %s: %v", network.Name, spend.RefID, thread.ThreadID, err)
+				continue
+			}
+			posted++
+		}
+		// Retry next run only when no thread got the alert, so none repeats it
+		if len(threads) > 0 && posted == 0 {
+			continue
+		}
+
+		if err := b.db.Model(spend).Update("alerted_at", time.Now()).Error; err != nil {
+			log.Printf("feedback: failed to mark spend alert for %s ref #%d: %v", network.Name, spend.RefID, err)
+		}
+	}
 }
 
 func (b *Module) startPolkassemblyMonitor(ctx context.Context) {
@@ -456,14 +672,26 @@ func (b *Module) processPolkassemblyReplies(ref *sharedgov.Ref) error {
 	log.Printf("feedback: found %d polkassembly messages for ref %d", len(messages), ref.RefID)
 
 	knownIDs := make(map[string]struct{}, len(messages))
-	parentCommentIDStrings := make(map[string]struct{}, len(messages))
-	for _, msg := range messages {
+	// Thread messages showing each comment, which replies to it answer
+	discordIDs := make(map[string]string, len(messages))
+	// Replies already mirrored to Discord, checked for edits and deletes
+	knownReplies := make(map[string]*sharedgov.RefMessage)
+	// Replies belong to the guild whose comment they answer
+	parentCommentIDStrings := make(map[string]string, len(messages))
+	for idx := range messages {
+		msg := &messages[idx]
 		if msg.PolkassemblyCommentID == nil || *msg.PolkassemblyCommentID == "" {
 			continue
 		}
 		id := *msg.PolkassemblyCommentID
 		knownIDs[id] = struct{}{}
-		parentCommentIDStrings[id] = struct{}{}
+		if msg.DiscordMessageID != nil && *msg.DiscordMessageID != "" {
+			discordIDs[id] = *msg.DiscordMessageID
+		}
+		if msg.Internal && msg.RemovedAt == nil {
+			knownReplies[id] = msg
+		}
+		parentCommentIDStrings[id] = msg.GuildID
 		log.Printf("feedback: stored comment ID for ref %d: %q", ref.RefID, id)
 	}
 
@@ -482,12 +710,31 @@ func (b *Module) processPolkassemblyReplies(ref *sharedgov.Ref) error {
 
 	log.Printf("feedback: found %d total comments for ref %d", len(comments), ref.RefID)
 
-	threadInfo, err := b.refManager.GetThreadInfo(network.ID, uint32(ref.RefID))
-	if err != nil {
-		return fmt.Errorf("thread mapping not found for network %d ref %d", network.ID, ref.RefID)
+	threadIDs := make(map[string]string)
+	threadFor := func(guildID string) string {
+		if threadID, ok := threadIDs[guildID]; ok {
+			return threadID
+		}
+		var info *sharedgov.ThreadInfo
+		var err error
+		if guildID == "" {
+			info, err = b.refManager.GetThreadInfo(network.ID, uint32(ref.RefID))
+		} else {
+			info, err = b.refManager.GetGuildThreadInfo(guildID, network.ID, uint32(ref.RefID))
+		}
+		threadID := ""
+		if err == nil {
+			threadID = info.ThreadID
+		} else {
+			log.Printf("feedback: thread mapping not found for guild %q network %d ref %d", guildID, network.ID, ref.RefID)
+		}
+		threadIDs[guildID] = threadID
+		return threadID
 	}
 
+	listed := make(map[string]struct{}, len(comments))
 	for _, comment := range comments {
+		listed[comment.ID] = struct{}{}
 		if comment.ParentID == nil {
 			log.Printf("feedback: comment %q has no ParentID (top-level comment)", comment.ID)
 			continue
@@ -497,7 +744,8 @@ func (b *Module) processPolkassemblyReplies(ref *sharedgov.Ref) error {
 
 		// Check if this reply's parent matches any of our stored comment IDs (now both are strings)
 		matched := false
-		if _, ok := parentCommentIDStrings[*comment.ParentID]; ok {
+		guildID, ok := parentCommentIDStrings[*comment.ParentID]
+		if ok {
 			log.Printf("feedback: comment %q matched by ParentID %q", comment.ID, *comment.ParentID)
 			matched = true
 		}
@@ -508,6 +756,10 @@ func (b *Module) processPolkassemblyReplies(ref *sharedgov.Ref) error {
 		}
 
 		if _, exists := knownIDs[comment.ID]; exists {
+			if reply, ok := knownReplies[comment.ID]; ok && strings.TrimSpace(reply.Body) != strings.TrimSpace(comment.Content) {
+				b.syncEditedPolkassemblyReply(threadFor(guildID), network, ref, reply, comment)
+				continue
+			}
 			log.Printf("feedback: comment %q already known, skipping", comment.ID)
 			continue
 		}
@@ -519,14 +771,16 @@ func (b *Module) processPolkassemblyReplies(ref *sharedgov.Ref) error {
 		}
 
 		createdAt := comment.ParsedCreatedAt()
-		_, err := data.SaveExternalPolkassemblyReply(
+		saved, err := data.SaveExternalPolkassemblyReply(
 			b.db,
 			ref.ID,
+			guildID,
 			comment.User.Username,
 			comment.Content,
 			userID,
 			comment.User.Username,
 			comment.ID,
+			*comment.ParentID,
 			createdAt,
 		)
 		if err != nil {
@@ -535,21 +789,59 @@ func (b *Module) processPolkassemblyReplies(ref *sharedgov.Ref) error {
 		}
 
 		knownIDs[comment.ID] = struct{}{}
-		parentCommentIDStrings[comment.ID] = struct{}{}
+		parentCommentIDStrings[comment.ID] = guildID
 
 		log.Printf("feedback: found new reply (ID: %q, ParentID: %q) from %s for ref %d", comment.ID, *comment.ParentID, comment.User.Username, ref.RefID)
-		b.announcePolkassemblyReply(threadInfo.ThreadID, network, ref, comment)
+		if messageID := b.announcePolkassemblyReply(threadFor(guildID), network, ref, comment, discordIDs[*comment.ParentID]); messageID != "" {
+			discordIDs[comment.ID] = messageID
+			if err := data.SetFeedbackDiscordMessage(b.db, saved.ID, messageID); err != nil {
+				log.Printf("feedback: failed to record announcement of reply %q: %v", comment.ID, err)
+			}
+		}
 
 	}
 
+	// Replies missing from the listing were deleted on Polkassembly
+	if len(comments) > 0 {
+		for id, reply := range knownReplies {
+			if _, ok := listed[id]; !ok {
+				b.syncDeletedPolkassemblyReply(threadFor(reply.GuildID), network, ref, reply)
+			}
+		}
+	}
+
 	return nil
 }
 
-func (b *Module) announcePolkassemblyReply(threadID string, network *sharedgov.Network, ref *sharedgov.Ref, comment sharedpolkassembly.Comment) {
+// announcePolkassemblyReply mirrors a Polkassembly reply to a referendum
+// thread, as a Discord reply to the message showing the comment it answers
+// when there is one, and returns the ID of the announcement
+func (b *Module) announcePolkassemblyReply(threadID string, network *sharedgov.Network, ref *sharedgov.Ref, comment sharedpolkassembly.Comment, parentMessageID string) string {
 	if b.session == nil || threadID == "" {
-		return
+		return ""
 	}
 
+	send := &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{polkassemblyReplyEmbed(network, ref, comment)},
+	}
+	if parentMessageID != "" {
+		failIfNotExists := false
+		send.Reference = &discordgo.MessageReference{
+			MessageID:       parentMessageID,
+			ChannelID:       threadID,
+			FailIfNotExists: &failIfNotExists,
+		}
+	}
+
+	sent, err := shareddiscord.SendComplexMessageNoEmbed(b.session, threadID, send)
+	if err != nil {
+		log.Printf("feedback: failed to post polkassembly reply to Discord: %v", err)
+		return ""
+	}
+	return sent.ID
+}
+
+func polkassemblyReplyEmbed(network *sharedgov.Network, ref *sharedgov.Ref, comment sharedpolkassembly.Comment) *discordgo.MessageEmbed {
 	content := strings.TrimSpace(comment.Content)
 	if content == "" {
 		content = "_(no content)_"
@@ -577,15 +869,11 @@ func (b *Module) announcePolkassemblyReply(threadID string, network *sharedgov.N
 	embed.URL = fmt.Sprintf("https://%s.polkassembly.io/referenda/%d#comment-%s",
 		networkNameForURL, ref.RefID, comment.ID)
 
-	if _, err := shareddiscord.SendComplexMessageNoEmbed(b.session, threadID, &discordgo.MessageSend{
-		Embeds: []*discordgo.MessageEmbed{embed},
-	}); err != nil {
-		log.Printf("feedback: failed to post polkassembly reply to Discord: %v", err)
-	}
+	return embed
 }
 
 // postPolkassemblyMessage posts a message to Polkassembly immediately and returns the comment ID
-func (b *Module) postPolkassemblyMessage(network *sharedgov.Network, ref *sharedgov.Ref, message string) (string, error) {
+func (b *Module) postPolkassemblyMessage(guildID string, network *sharedgov.Network, ref *sharedgov.Ref, message string) (string, error) {
 	if b.polkassembly == nil {
 		return "", fmt.Errorf("polkassembly service is not configured")
 	}
@@ -605,7 +893,7 @@ func (b *Module) postPolkassemblyMessage(network *sharedgov.Network, ref *shared
 	gcURL = strings.TrimRight(gcURL, "/")
 	link := fmt.Sprintf("%s/%s/%d", gcURL, strings.ToLower(network.Name), ref.RefID)
 
-	commentID, err := b.polkassembly.PostFirstMessage(network.Name, int(ref.RefID), message, link)
+	commentID, err := b.polkassembly.PostFirstMessage(guildID, network.Name, int(ref.RefID), message, link)
 	if err != nil {
 		log.Printf("feedback: PostFirstMessage failed: %v", err)
 		return "", fmt.Errorf("post to polkassembly failed: %w", err)
@@ -0,0 +1,376 @@
+package feedback
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	shareddiscord "github.com/stake-plus/govcomms/src/api/discord"
+	polkadot "github.com/stake-plus/govcomms/src/polkadot-go"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+)
+
+const (
+	reminderColor      = 0x3498DB
+	confirmingColor    = 0xF59E0B
+	confirmAbortColor  = 0x9CA3AF
+	refApprovedColor   = 0x22C55E
+	refRejectedColor   = 0xEF4444
+	maxDaoMentions     = 50
+	staleEventInterval = 24 * time.Hour
+)
+
+// notifiedEvents are the lifecycle events announced in referendum threads
+var notifiedEvents = []string{"ConfirmStarted", "ConfirmAborted", "Approved", "Rejected", "TimedOut", "Cancelled", "Killed"}
+
+// notifyReferendumUpdates posts lifecycle changes and decision-deadline
+// reminders into the mapped referendum threads
+func (h *Handler) notifyReferendumUpdates(s *discordgo.Session) {
+	if h == nil || s == nil || h.NetworkManager == nil {
+		return
+	}
+
+	checkpoints := make(map[uint8]*sharedgov.IndexerCheckpoint)
+	h.notifyStateChanges(s, checkpoints)
+	h.sendDecisionReminders(s, checkpoints)
+}
+
+// notifyStateChanges announces each lifecycle event once. Events that are
+// stale, e.g. from backfilling history, or whose referendum has no thread are
+// marked without posting.
+func (h *Handler) notifyStateChanges(s *discordgo.Session, checkpoints map[uint8]*sharedgov.IndexerCheckpoint) {
+	var events []sharedgov.RefEvent
+	if err := h.DB.
+		Where("notified_at IS NULL AND event IN ?", notifiedEvents).
+		Order("id").
+		Find(&events).Error; err != nil {
+		log.Printf("feedback: failed to load referendum events: %v", err)
+		return
+	}
+
+	staleBefore := time.Now().Add(-staleEventInterval)
+	for i := range events {
+		event := &events[i]
+		if event.OccurredAt == nil || event.OccurredAt.After(staleBefore) {
+			if err := h.postStateChange(s, event, checkpoints); err != nil {
+				log.Printf("feedback: failed to announce %s for ref #%d: %v", event.Event, event.RefID, err)
+				continue
+			}
+		}
+
+		if err := h.DB.Model(event).Update("notified_at", time.Now()).Error; err != nil {
+			log.Printf("feedback: failed to mark %s event of ref #%d: %v", event.Event, event.RefID, err)
+		}
+	}
+}
+
+func (h *Handler) postStateChange(s *discordgo.Session, event *sharedgov.RefEvent, checkpoints map[uint8]*sharedgov.IndexerCheckpoint) error {
+	network := h.NetworkManager.GetByID(event.NetworkID)
+	if network == nil {
+		return nil
+	}
+	threads, err := h.RefManager.ListThreads(event.NetworkID, uint32(event.RefID))
+	if err != nil || len(threads) == 0 {
+		return err
+	}
+
+	var ref sharedgov.Ref
+	if err := h.DB.First(&ref, event.RefDBID).Error; err != nil {
+		return err
+	}
+	checkpoint := h.checkpoint(event.NetworkID, checkpoints)
+
+	at := blockTimeLine(event.BlockNumber, event.OccurredAt, checkpoint)
+	var title, body string
+	var color int
+	mentionVoters := false
+	switch event.Event {
+	case "ConfirmStarted":
+		title, color, mentionVoters = "Confirming", confirmingColor, true
+		body = "Entered confirmation at " + at + "."
+		if ref.ConfirmStart > 0 {
+			// The chain stores the block confirmation completes
+			body += "\nPasses at " + blockTimeLine(ref.ConfirmStart, nil, checkpoint) + " if it stays above the approval and support thresholds."
+		}
+	case "ConfirmAborted":
+		title, color, mentionVoters = "Confirmation aborted", confirmAbortColor, true
+		body = "Left confirmation at " + at + " and is deciding again."
+		if ref.DecisionEnd > 0 {
+			body += "\nThe decision period ends at " + blockTimeLine(ref.DecisionEnd, ref.DecisionEndAt, checkpoint) + "."
+		}
+	case "Approved":
+		title, color = "Approved", refApprovedColor
+		body = "Approved at " + at + "."
+	default:
+		title, color = event.Event, refRejectedColor
+		body = fmt.Sprintf("%s at %s.", event.Event, at)
+	}
+
+	return h.postReferendumNotices(s, threads, network, &ref, title, body, color, mentionVoters)
+}
+
+// sendDecisionReminders posts the tightest due reminder offset before each
+// deciding referendum's decision end, once per offset
+func (h *Handler) sendDecisionReminders(s *discordgo.Session, checkpoints map[uint8]*sharedgov.IndexerCheckpoint) {
+	offsets := h.Config.ReminderOffsetHours
+	if len(offsets) == 0 {
+		return
+	}
+
+	var refs []sharedgov.Ref
+	if err := h.DB.
+		Where("finalized = ? AND decision_end > 0", false).
+		Order("id").
+		Find(&refs).Error; err != nil {
+		log.Printf("feedback: failed to load deciding referenda: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for i := range refs {
+		ref := &refs[i]
+		checkpoint := h.checkpoint(ref.NetworkID, checkpoints)
+		endsAt := ref.DecisionEndAt
+		if checkpoint != nil {
+			estimated := checkpoint.EstimateBlockTime(ref.DecisionEnd)
+			endsAt = &estimated
+		}
+		if endsAt == nil || !endsAt.After(now) {
+			continue
+		}
+
+		// Offsets are sorted largest first, so the last one due is the tightest
+		remaining := endsAt.Sub(now)
+		due := 0
+		for _, hours := range offsets {
+			if remaining <= time.Duration(hours)*time.Hour {
+				due = hours
+			}
+		}
+		if due == 0 {
+			continue
+		}
+
+		var sent int64
+		if err := h.DB.Model(&sharedgov.RefReminder{}).
+			Where("ref_db_id = ? AND offset_hours = ? AND decision_end = ?", ref.ID, due, ref.DecisionEnd).
+			Count(&sent).Error; err != nil || sent > 0 {
+			continue
+		}
+
+		network := h.NetworkManager.GetByID(ref.NetworkID)
+		if network == nil {
+			continue
+		}
+		threads, err := h.RefManager.ListThreads(ref.NetworkID, uint32(ref.RefID))
+		if err != nil || len(threads) == 0 {
+			continue
+		}
+
+		body := fmt.Sprintf("The decision period ends in about %s, at %s.",
+			formatRemaining(remaining), blockTimeLine(ref.DecisionEnd, endsAt, checkpoint))
+		if projection := passProjection(ref); projection != nil {
+			if projection.Passing {
+				body += "\nIt is currently passing."
+			} else {
+				body += "\nIt is currently failing."
+			}
+		}
+		title := fmt.Sprintf("%dh reminder", due)
+		if err := h.postReferendumNotices(s, threads, network, ref, title, body, reminderColor, true); err != nil {
+			log.Printf("feedback: failed to post %dh reminder for %s ref #%d: %v", due, network.Name, ref.RefID, err)
+			continue
+		}
+
+		reminder := sharedgov.RefReminder{
+			RefDBID:     ref.ID,
+			NetworkID:   ref.NetworkID,
+			RefID:       ref.RefID,
+			OffsetHours: uint16(due),
+			DecisionEnd: ref.DecisionEnd,
+			ThreadID:    threads[0].ThreadID,
+			SentAt:      time.Now(),
+		}
+		if err := h.DB.Create(&reminder).Error; err != nil {
+			log.Printf("feedback: failed to record %dh reminder for %s ref #%d: %v", due, network.Name, ref.RefID, err)
+		}
+	}
+}
+
+// postReferendumNotices posts a notice in every guild's thread for a
+// referendum, followed by that guild's DAO decision line. It fails only when
+// no thread received the notice, so a retry does not repeat it where it was
+// posted.
+func (h *Handler) postReferendumNotices(s *discordgo.Session, threads []sharedgov.ThreadInfo, network *sharedgov.Network, ref *sharedgov.Ref, title, body string, color int, mentionVoters bool) error {
+	var errs []error
+	for _, thread := range threads {
+		if err := h.postReferendumNotice(s, thread, network, ref, title, body, color, mentionVoters); err != nil {
+			errs = append(errs, fmt.Errorf("thread %s: %w", thread.ThreadID, err))
+		}
+	}
+	if len(errs) < len(threads) {
+		for _, err := range errs {
+			log.Printf("feedback: %s • %s #%d not posted: %v", title, network.Name, ref.RefID, err)
+		}
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// postReferendumNotice posts a notice embed in a guild's referendum thread.
+// With mentionVoters set and the guild's DAO voting open, its members who
+// have not voted yet are mentioned.
+func (h *Handler) postReferendumNotice(s *discordgo.Session, thread sharedgov.ThreadInfo, network *sharedgov.Network, ref *sharedgov.Ref, title, body string, color int, mentionVoters bool) error {
+	if line := h.daoDecisionLine(thread.GuildID, ref); line != "" {
+		body += "\n\n" + line
+	}
+	message := &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       fmt.Sprintf("%s • %s #%d", title, network.Name, ref.RefID),
+			Description: body,
+			Color:       color,
+			Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		}},
+	}
+
+	if mentionVoters && h.daoVotingOpen(thread.GuildID, ref) {
+		pending, err := h.daoNonVoters(thread.GuildID, ref.ID)
+		if err != nil {
+			log.Printf("feedback: failed to load guild %s DAO members yet to vote on ref %d: %v", thread.GuildID, ref.ID, err)
+		} else if len(pending) > 0 {
+			message.Content, message.AllowedMentions = daoMentions(pending)
+		}
+	}
+
+	_, err := shareddiscord.SendComplexMessageNoEmbed(s, thread.ThreadID, message)
+	return err
+}
+
+// daoVotingOpen reports whether a guild's DAO members can still /vote on a
+// referendum
+func (h *Handler) daoVotingOpen(guildID string, ref *sharedgov.Ref) bool {
+	if ref.Finalized {
+		return false
+	}
+	var decision sharedgov.DaoDecision
+	if err := h.DB.Where("ref_db_id = ? AND guild_id = ?", ref.ID, guildID).First(&decision).Error; err == nil && decision.Decided() {
+		return false
+	}
+	closesAt := sharedgov.DaoVoteCutoff(ref, h.voteCutoff())
+	return closesAt == nil || time.Now().Before(*closesAt)
+}
+
+// daoDecisionLine describes a guild DAO's internal vote on a referendum: its
+// decision once recorded, otherwise when voting closes
+func (h *Handler) daoDecisionLine(guildID string, ref *sharedgov.Ref) string {
+	var decision sharedgov.DaoDecision
+	if err := h.DB.Where("ref_db_id = ? AND guild_id = ?", ref.ID, guildID).First(&decision).Error; err == nil && decision.Decided() {
+		return "DAO decision: " + daoOutcomeLabel(*decision.Outcome)
+	}
+	if !h.daoVotingOpen(guildID, ref) {
+		return ""
+	}
+	if closesAt := sharedgov.DaoVoteCutoff(ref, h.voteCutoff()); closesAt != nil {
+		return fmt.Sprintf("DAO voting closes <t:%d:f> (<t:%d:R>). Use /vote to cast or change your vote.", closesAt.Unix(), closesAt.Unix())
+	}
+	return "Use /vote to cast your DAO vote."
+}
+
+// daoNonVoters returns the Discord IDs of a guild's DAO members who have not
+// voted on the referendum
+func (h *Handler) daoNonVoters(guildID string, refDBID uint64) ([]string, error) {
+	voted := h.DB.Model(&sharedgov.DaoVote{}).
+		Select("discord").
+		Where("ref_id = ? AND guild_id = ?", refDBID, guildID)
+
+	var ids []string
+	err := h.DB.Model(&sharedgov.DaoMember{}).
+		Where("guild_id = ? AND discord <> '' AND discord NOT IN (?)", guildID, voted).
+		Distinct("discord").
+		Order("discord").
+		Pluck("discord", &ids).Error
+	return ids, err
+}
+
+// checkpoint returns the network's indexer checkpoint, cached for one run
+func (h *Handler) checkpoint(networkID uint8, checkpoints map[uint8]*sharedgov.IndexerCheckpoint) *sharedgov.IndexerCheckpoint {
+	if checkpoint, ok := checkpoints[networkID]; ok {
+		return checkpoint
+	}
+	var rows []sharedgov.IndexerCheckpoint
+	if err := h.DB.Where("network_id = ?", networkID).Limit(1).Find(&rows).Error; err != nil {
+		log.Printf("feedback: failed to load indexer checkpoint for network %d: %v", networkID, err)
+	}
+	var checkpoint *sharedgov.IndexerCheckpoint
+	if len(rows) > 0 && rows[0].BlockNumber > 0 {
+		checkpoint = &rows[0]
+	}
+	checkpoints[networkID] = checkpoint
+	return checkpoint
+}
+
+// daoMentions renders mentions of the given Discord users, allowing only
+// them to be pinged
+func daoMentions(ids []string) (string, *discordgo.MessageAllowedMentions) {
+	shown := ids
+	if len(shown) > maxDaoMentions {
+		shown = shown[:maxDaoMentions]
+	}
+	mentions := make([]string, 0, len(shown))
+	for _, id := range shown {
+		mentions = append(mentions, "<@"+id+">")
+	}
+
+	content := "DAO members yet to vote: " + strings.Join(mentions, " ")
+	if extra := len(ids) - len(shown); extra > 0 {
+		content += fmt.Sprintf(" and %d more", extra)
+	}
+	return content, &discordgo.MessageAllowedMentions{Users: shown}
+}
+
+// blockTimeLine renders a block with its wall-clock time: the known time
+// when given, otherwise an estimate from the indexer checkpoint
+func blockTimeLine(block uint64, known *time.Time, checkpoint *sharedgov.IndexerCheckpoint) string {
+	line := fmt.Sprintf("block #%d", block)
+	var at *time.Time
+	if known != nil {
+		at = known
+	} else if checkpoint != nil && block > 0 {
+		estimated := checkpoint.EstimateBlockTime(block)
+		at = &estimated
+	}
+	if at != nil {
+		line += fmt.Sprintf(" (~<t:%d:f>, <t:%d:R>)", at.Unix(), at.Unix())
+	}
+	return line
+}
+
+// formatRemaining renders a duration as whole days and hours, or hours and
+// minutes under a day
+func formatRemaining(d time.Duration) string {
+	hours := int(d.Hours())
+	switch {
+	case hours >= 24:
+		return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
+
+// passProjection returns the indexer's latest curve evaluation of an ongoing
+// referendum
+func passProjection(ref *sharedgov.Ref) *polkadot.PassProjection {
+	if ref.PassProjection == nil || ref.Status == nil || *ref.Status != "Ongoing" {
+		return nil
+	}
+	projection, err := polkadot.ParsePassProjection([]byte(*ref.PassProjection))
+	if err != nil {
+		return nil
+	}
+	return projection
+}
@@ -0,0 +1,124 @@
+package feedback
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stake-plus/govcomms/src/actions/feedback/data"
+	shareddiscord "github.com/stake-plus/govcomms/src/api/discord"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+	"gorm.io/gorm"
+)
+
+const (
+	// FeedbackReplyAction is the name permission rules for answering
+	// proponents on Polkassembly use
+	FeedbackReplyAction = "feedback-reply"
+
+	maxReplyLength = 5000
+)
+
+// onMessageCreate posts a Discord reply to a mirrored Polkassembly reply as
+// a nested reply to that comment on Polkassembly, after review when the
+// guild reviews feedback
+func (b *Module) onMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if m.Message == nil || m.Author == nil || m.Author.Bot {
+		return
+	}
+	if m.MessageReference == nil || m.MessageReference.MessageID == "" {
+		return
+	}
+	guild := b.guilds.Get(m.GuildID)
+	if guild == nil {
+		return
+	}
+
+	parent, err := data.GetFeedbackByDiscordMessage(b.db, m.MessageReference.MessageID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("feedback: failed to look up replied message %s: %v", m.MessageReference.MessageID, err)
+		}
+		return
+	}
+	// Only replies to proponents are answered publicly; other replies stay
+	// part of the DAO's discussion
+	if !parent.Internal || parent.PolkassemblyCommentID == nil || *parent.PolkassemblyCommentID == "" {
+		return
+	}
+
+	if decision := shareddiscord.CheckMessagePermission(s, b.permissions, guild.GuildID, m.Message, FeedbackReplyAction, guild.FeedbackRoleID); !decision.Allowed {
+		return
+	}
+
+	text := strings.TrimSpace(m.Content)
+	if text == "" {
+		return
+	}
+	if utf8.RuneCountInString(text) > maxReplyLength {
+		replyToMessage(s, m.Message, "Polkassembly Reply", fmt.Sprintf("Replies to proponents can be at most %d characters. This one was not posted to Polkassembly.", maxReplyLength))
+		return
+	}
+
+	var ref sharedgov.Ref
+	if err := b.db.First(&ref, parent.RefID).Error; err != nil {
+		log.Printf("feedback: failed to load referendum %d of reply %s: %v", parent.RefID, m.ID, err)
+		return
+	}
+	network := b.networkManager.GetByID(ref.NetworkID)
+	if network == nil {
+		log.Printf("feedback: no network configured for id %d of reply %s", ref.NetworkID, m.ID)
+		return
+	}
+
+	authorTag := formatDiscordUsername(m.Author.Username, m.Author.Discriminator)
+	parentCommentID := *parent.PolkassemblyCommentID
+	if guild.FeedbackReviewChannelID != "" {
+		b.handler.submitReplyForReview(s, m.Message, guild.FeedbackReviewChannelID, network, &ref, parentCommentID, authorTag, text)
+		return
+	}
+
+	commentID, err := b.replyToPolkassemblyComment(guild.GuildID, network, &ref, parentCommentID, replyCommentBody(text, authorTag))
+	if err != nil {
+		log.Printf("feedback: failed to post reply %s to polkassembly comment %s: %v", m.ID, parentCommentID, err)
+		replyToMessage(s, m.Message, "Polkassembly Reply", fmt.Sprintf("This reply could not be posted to Polkassembly: %v", err))
+		return
+	}
+
+	if _, err := data.SaveFeedbackReply(b.db, ref.ID, guild.GuildID, m.Author.ID, authorTag, text, m.ID, commentID, parentCommentID); err != nil {
+		log.Printf("feedback: failed to store reply %s (comment %s): %v", m.ID, commentID, err)
+	}
+
+	log.Printf("feedback: posted reply %s by %s to polkassembly comment %s for %s ref #%d", commentID, authorTag, parentCommentID, network.Name, ref.RefID)
+	if err := s.MessageReactionAdd(m.ChannelID, m.ID, "✅"); err != nil {
+		log.Printf("feedback: failed to confirm reply %s: %v", m.ID, err)
+	}
+}
+
+// replyCommentBody signs a reply to a proponent with its Discord author
+func replyCommentBody(text, authorTag string) string {
+	return fmt.Sprintf("%s\n\n— %s", text, authorTag)
+}
+
+// replyToPolkassemblyComment posts a guild's reply under a Polkassembly comment
+func (b *Module) replyToPolkassemblyComment(guildID string, network *sharedgov.Network, ref *sharedgov.Ref, parentCommentID, message string) (string, error) {
+	if b.polkassembly == nil {
+		return "", fmt.Errorf("polkassembly service is not configured")
+	}
+	if network == nil || ref == nil {
+		return "", fmt.Errorf("network and referendum are required")
+	}
+	return b.polkassembly.ReplyToComment(guildID, network.Name, int(ref.RefID), parentCommentID, message)
+}
+
+func replyToMessage(s *discordgo.Session, m *discordgo.Message, title, body string) {
+	if _, err := shareddiscord.SendComplexMessageNoEmbed(s, m.ChannelID, &discordgo.MessageSend{
+		Content:   shareddiscord.FormatStyledBlock(title, body),
+		Reference: m.Reference(),
+	}); err != nil {
+		log.Printf("feedback: failed to answer message %s: %v", m.ID, err)
+	}
+}
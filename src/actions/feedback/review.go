@@ -0,0 +1,570 @@
+package feedback
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stake-plus/govcomms/src/actions/feedback/data"
+	shareddiscord "github.com/stake-plus/govcomms/src/api/discord"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+)
+
+const (
+	// FeedbackReviewAction is the name permission rules for reviewers use
+	FeedbackReviewAction = "feedback-review"
+
+	reviewCustomIDPrefix = "feedback_review:"
+	reviewTextInputID    = "feedback_text"
+	reviewPendingColor   = 0xF59E0B
+	reviewApprovedColor  = 0x22C55E
+	reviewRejectedColor  = 0x9CA3AF
+	maxReviewDescription = 4000
+	maxReviewDraftField  = 1000
+	maxReviewEditLength  = 4000 // Discord's limit for a text input
+)
+
+// Review actions carried in button and modal custom IDs
+const (
+	reviewApprove = "approve"
+	reviewEdit    = "edit"
+	reviewReject  = "reject"
+)
+
+// IsFeedbackReviewInteraction reports whether a component or modal
+// interaction belongs to the feedback review queue
+func IsFeedbackReviewInteraction(customID string) bool {
+	return strings.HasPrefix(customID, reviewCustomIDPrefix)
+}
+
+// submitFeedbackForReview holds feedback in the guild's review channel
+// instead of posting it to Polkassembly
+func (h *Handler) submitFeedbackForReview(s *discordgo.Session, i *discordgo.InteractionCreate, reviewChannelID string, threadInfo *sharedgov.ThreadInfo, network *sharedgov.Network, ref *sharedgov.Ref, authorTag, message string) {
+	saved, err := data.SaveFeedbackDraft(h.DB, ref, threadInfo.GuildID, i.Member.User.ID, authorTag, message)
+	if err != nil {
+		log.Printf("feedback: failed to persist feedback draft: %v", err)
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Feedback", "Failed to store feedback. Please try again later.")
+		return
+	}
+
+	posted, err := shareddiscord.SendComplexMessageNoEmbed(s, reviewChannelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{reviewEmbed(network, ref, saved, threadInfo.ThreadID)},
+		Components: reviewButtons(saved.ID),
+	})
+	if err != nil {
+		log.Printf("feedback: failed to post feedback for review in %s: %v", reviewChannelID, err)
+		if delErr := h.DB.Delete(saved).Error; delErr != nil {
+			log.Printf("feedback: failed to drop unreviewable draft %d: %v", saved.ID, delErr)
+		}
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Feedback", "Failed to send feedback for review. Please try again later.")
+		return
+	}
+
+	if err := h.DB.Model(saved).Update("review_message_id", posted.ID).Error; err != nil {
+		log.Printf("feedback: failed to store review message of draft %d: %v", saved.ID, err)
+	}
+
+	respondFeedbackWithStyledEdit(s, i.Interaction, "Feedback",
+		fmt.Sprintf("✅ Thank you %s! Your feedback for %s referendum #%d was sent for review and will be posted to Polkassembly once approved.",
+			authorTag, network.Name, ref.RefID))
+}
+
+// submitEditForReview holds a revision of posted feedback in the guild's
+// review channel instead of editing the Polkassembly comment
+func (h *Handler) submitEditForReview(s *discordgo.Session, i *discordgo.InteractionCreate, reviewChannelID string, threadInfo *sharedgov.ThreadInfo, network *sharedgov.Network, ref *sharedgov.Ref, posted *sharedgov.RefMessage, editorTag, message string) {
+	pending, err := data.HasPendingFeedback(h.DB, ref.ID, threadInfo.GuildID)
+	if err != nil {
+		log.Printf("feedback: failed to check pending feedback: %v", err)
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback", "Failed to check pending edits. Please try again later.")
+		return
+	}
+	if pending {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback",
+			fmt.Sprintf("An edit of the feedback for %s referendum #%d is already waiting for review.", network.Name, ref.RefID))
+		return
+	}
+
+	saved, err := data.SaveFeedbackEditDraft(h.DB, posted, i.Member.User.ID, editorTag, message)
+	if err != nil {
+		log.Printf("feedback: failed to persist edit draft of feedback %d: %v", posted.ID, err)
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback", "Failed to store the edit. Please try again later.")
+		return
+	}
+
+	reviewMessage, err := shareddiscord.SendComplexMessageNoEmbed(s, reviewChannelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{reviewEmbed(network, ref, saved, threadInfo.ThreadID)},
+		Components: reviewButtons(saved.ID),
+	})
+	if err != nil {
+		log.Printf("feedback: failed to post edit of feedback %d for review in %s: %v", posted.ID, reviewChannelID, err)
+		if delErr := h.DB.Delete(saved).Error; delErr != nil {
+			log.Printf("feedback: failed to drop unreviewable edit draft %d: %v", saved.ID, delErr)
+		}
+		respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback", "Failed to send the edit for review. Please try again later.")
+		return
+	}
+
+	if err := h.DB.Model(saved).Update("review_message_id", reviewMessage.ID).Error; err != nil {
+		log.Printf("feedback: failed to store review message of edit draft %d: %v", saved.ID, err)
+	}
+
+	respondFeedbackWithStyledEdit(s, i.Interaction, "Edit Feedback",
+		fmt.Sprintf("✅ The edit of the feedback for %s referendum #%d was sent for review and will replace the Polkassembly comment once approved.",
+			network.Name, ref.RefID))
+}
+
+// submitReplyForReview holds a Discord reply to a proponent in the guild's
+// review channel instead of posting it to Polkassembly
+func (h *Handler) submitReplyForReview(s *discordgo.Session, m *discordgo.Message, reviewChannelID string, network *sharedgov.Network, ref *sharedgov.Ref, parentCommentID, authorTag, text string) {
+	saved, err := data.SaveFeedbackReplyDraft(h.DB, ref.ID, m.GuildID, m.Author.ID, authorTag, text, m.ID, parentCommentID)
+	if err != nil {
+		log.Printf("feedback: failed to persist reply draft %s: %v", m.ID, err)
+		replyToMessage(s, m, "Polkassembly Reply", "Failed to store this reply. Please try again later.")
+		return
+	}
+
+	posted, err := shareddiscord.SendComplexMessageNoEmbed(s, reviewChannelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{reviewEmbed(network, ref, saved, m.ChannelID)},
+		Components: reviewButtons(saved.ID),
+	})
+	if err != nil {
+		log.Printf("feedback: failed to post reply %s for review in %s: %v", m.ID, reviewChannelID, err)
+		if delErr := h.DB.Delete(saved).Error; delErr != nil {
+			log.Printf("feedback: failed to drop unreviewable reply draft %d: %v", saved.ID, delErr)
+		}
+		replyToMessage(s, m, "Polkassembly Reply", "Failed to send this reply for review. Please try again later.")
+		return
+	}
+
+	if err := h.DB.Model(saved).Update("review_message_id", posted.ID).Error; err != nil {
+		log.Printf("feedback: failed to store review message of reply draft %d: %v", saved.ID, err)
+	}
+
+	log.Printf("feedback: reply %s by %s to polkassembly comment %s sent for review", m.ID, authorTag, parentCommentID)
+	if err := s.MessageReactionAdd(m.ChannelID, m.ID, "⏳"); err != nil {
+		log.Printf("feedback: failed to mark reply %s as under review: %v", m.ID, err)
+	}
+}
+
+// HandleReviewComponent handles the Approve, Edit and Reject buttons of a
+// feedback review
+func (h *Handler) HandleReviewComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if h == nil || i.Member == nil || i.Member.User == nil {
+		return
+	}
+
+	action, messageID, ok := parseReviewCustomID(i.MessageComponentData().CustomID)
+	if !ok {
+		return
+	}
+	msg, network, ref, ok := h.loadReview(s, i, messageID)
+	if !ok {
+		return
+	}
+
+	switch action {
+	case reviewApprove:
+		if msg.AuthorID != nil && *msg.AuthorID == i.Member.User.ID {
+			respondFeedbackEphemeral(s, i.Interaction, "Feedback Review", "Feedback needs a second pair of eyes: someone other than its author has to approve it.")
+			return
+		}
+		h.approveFeedback(s, i, msg, network, ref)
+	case reviewEdit:
+		if utf8.RuneCountInString(msg.Body) > maxReviewEditLength {
+			respondFeedbackEphemeral(s, i.Interaction, "Feedback Review",
+				fmt.Sprintf("This feedback is longer than the %d characters Discord can edit. Reject it and ask the author to resubmit a shorter text.", maxReviewEditLength))
+			return
+		}
+		if err := shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseModal,
+			Data: &discordgo.InteractionResponseData{
+				CustomID: reviewCustomID(reviewEdit, msg.ID),
+				Title:    fmt.Sprintf("Edit feedback • %s #%d", network.Name, ref.RefID),
+				Components: []discordgo.MessageComponent{discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{discordgo.TextInput{
+						CustomID:  reviewTextInputID,
+						Label:     "Feedback",
+						Style:     discordgo.TextInputParagraph,
+						Value:     msg.Body,
+						Required:  true,
+						MinLength: 10,
+						MaxLength: maxReviewEditLength,
+					}},
+				}},
+			},
+		}); err != nil {
+			log.Printf("feedback: failed to open edit dialog for draft %d: %v", msg.ID, err)
+		}
+	case reviewReject:
+		h.rejectFeedback(s, i, msg, network, ref)
+	}
+}
+
+// HandleReviewModal stores the text edited in a review's Edit dialog
+func (h *Handler) HandleReviewModal(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if h == nil || i.Member == nil || i.Member.User == nil {
+		return
+	}
+
+	modal := i.ModalSubmitData()
+	action, messageID, ok := parseReviewCustomID(modal.CustomID)
+	if !ok || action != reviewEdit {
+		return
+	}
+	text := strings.TrimSpace(modalTextValue(modal.Components, reviewTextInputID))
+	if length := utf8.RuneCountInString(text); length < 10 || length > maxReviewEditLength {
+		respondFeedbackEphemeral(s, i.Interaction, "Feedback Review", fmt.Sprintf("Feedback must be between 10 and %d characters.", maxReviewEditLength))
+		return
+	}
+
+	msg, network, ref, ok := h.loadReview(s, i, messageID)
+	if !ok {
+		return
+	}
+
+	result := h.DB.Model(&sharedgov.RefMessage{}).
+		Where("id = ? AND status = ?", msg.ID, sharedgov.FeedbackPending).
+		Update("body", text)
+	if result.Error != nil || result.RowsAffected == 0 {
+		if result.Error != nil {
+			log.Printf("feedback: failed to store edit of draft %d: %v", msg.ID, result.Error)
+		}
+		respondFeedbackEphemeral(s, i.Interaction, "Feedback Review", "The feedback could not be edited. It may have been reviewed meanwhile.")
+		return
+	}
+	msg.Body = text
+	log.Printf("feedback: draft %d edited by %s", msg.ID, i.Member.User.Username)
+
+	embed := reviewEmbed(network, ref, msg, h.feedbackThreadID(msg.GuildID, network.ID, ref.RefID))
+	if err := shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: reviewButtons(msg.ID),
+		},
+	}); err != nil {
+		log.Printf("feedback: failed to refresh review of draft %d: %v", msg.ID, err)
+	}
+}
+
+// approveFeedback posts the reviewed text to Polkassembly and the referendum
+// thread, or applies a reviewed edit to the feedback it revises, then closes
+// the review
+func (h *Handler) approveFeedback(s *discordgo.Session, i *discordgo.InteractionCreate, msg *sharedgov.RefMessage, network *sharedgov.Network, ref *sharedgov.Ref) {
+	claimed, err := data.SetFeedbackStatus(h.DB, msg.ID, sharedgov.FeedbackPending, sharedgov.FeedbackApproving)
+	if err != nil || !claimed {
+		if err != nil {
+			log.Printf("feedback: failed to claim draft %d: %v", msg.ID, err)
+		}
+		respondFeedbackEphemeral(s, i.Interaction, "Feedback Review", "This feedback is already being reviewed by someone else.")
+		return
+	}
+
+	if err := shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	}); err != nil {
+		log.Printf("feedback: failed to acknowledge approval of draft %d: %v", msg.ID, err)
+	}
+
+	var commentID string
+	var postErr error
+	if isReviewedEdit(msg) {
+		postErr = h.applyReviewedEdit(s, msg, network, ref)
+	} else {
+		commentID, postErr = h.postReviewedMessage(msg, network, ref)
+	}
+	if postErr != nil {
+		log.Printf("feedback: failed to post approved draft %d to polkassembly: %v", msg.ID, postErr)
+		if _, err := data.SetFeedbackStatus(h.DB, msg.ID, sharedgov.FeedbackApproving, sharedgov.FeedbackPending); err != nil {
+			log.Printf("feedback: failed to reopen draft %d: %v", msg.ID, err)
+		}
+		reviewFollowup(s, i.Interaction, fmt.Sprintf("Failed to post feedback to Polkassembly: %v. It is still waiting for review.", postErr))
+		return
+	}
+	if commentID != "" {
+		if err := data.UpdateFeedbackMessagePolkassembly(h.DB, msg.ID, commentID, nil, ""); err != nil {
+			log.Printf("feedback: failed to update message with comment ID: %v", err)
+		}
+		log.Printf("feedback: posted approved feedback (comment ID: %s) for %s ref #%d", commentID, network.Name, ref.RefID)
+	}
+
+	h.closeReview(msg, sharedgov.FeedbackApproved, i.Member.User)
+
+	// An approved reply is already the author's message in the thread
+	threadID := h.feedbackThreadID(msg.GuildID, network.ID, ref.RefID)
+	if isReviewedReply(msg) {
+		if threadID != "" && msg.DiscordMessageID != nil {
+			if err := s.MessageReactionAdd(threadID, *msg.DiscordMessageID, "✅"); err != nil {
+				log.Printf("feedback: failed to confirm reply %s: %v", *msg.DiscordMessageID, err)
+			}
+		}
+	} else if !isReviewedEdit(msg) && threadID != "" && h.Deps.PostFeedbackMessage != nil {
+		if messageID := h.Deps.PostFeedbackMessage(s, threadID, network, ref, msg.Author, msg.Body); messageID != "" {
+			if err := data.SetFeedbackDiscordMessage(h.DB, msg.ID, messageID); err != nil {
+				log.Printf("feedback: failed to record thread message of feedback %d: %v", msg.ID, err)
+			}
+		}
+	}
+
+	embed := reviewEmbed(network, ref, msg, threadID)
+	noComponents := []discordgo.MessageComponent{}
+	if _, err := shareddiscord.InteractionResponseEditNoEmbed(s, i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &noComponents,
+	}); err != nil {
+		log.Printf("feedback: failed to close review of draft %d: %v", msg.ID, err)
+	}
+}
+
+// postReviewedMessage posts approved feedback to Polkassembly, or an
+// approved reply under the comment it answers
+func (h *Handler) postReviewedMessage(msg *sharedgov.RefMessage, network *sharedgov.Network, ref *sharedgov.Ref) (string, error) {
+	if isReviewedReply(msg) {
+		if h.Deps.ReplyToPolkassembly == nil {
+			return "", nil
+		}
+		return h.Deps.ReplyToPolkassembly(msg.GuildID, network, ref, *msg.PolkassemblyParentID, replyCommentBody(msg.Body, msg.Author))
+	}
+	if h.Deps.PostPolkassemblyMessage == nil {
+		return "", nil
+	}
+	return h.Deps.PostPolkassemblyMessage(msg.GuildID, network, ref, msg.Body)
+}
+
+// applyReviewedEdit revises the posted feedback an approved edit replaces.
+// An edit that reached Polkassembly but could not be recorded is only
+// logged, so that approving it again does not repeat the edit.
+func (h *Handler) applyReviewedEdit(s *discordgo.Session, draft *sharedgov.RefMessage, network *sharedgov.Network, ref *sharedgov.Ref) error {
+	var posted sharedgov.RefMessage
+	if err := h.DB.Where("id = ? AND removed_at IS NULL", *draft.RevisesID).First(&posted).Error; err != nil {
+		return fmt.Errorf("the feedback it edits is no longer published")
+	}
+
+	threadID := h.feedbackThreadID(draft.GuildID, network.ID, ref.RefID)
+	err := h.reviseFeedback(s, threadID, network, ref, &posted, draft.Body, draft.Author)
+	if errors.Is(err, errEditNotRecorded) {
+		log.Printf("feedback: approved edit %d of feedback %d: %v", draft.ID, posted.ID, err)
+		return nil
+	}
+	if err == nil {
+		log.Printf("feedback: approved edit %d revised feedback %d for %s ref #%d", draft.ID, posted.ID, network.Name, ref.RefID)
+	}
+	return err
+}
+
+// isReviewedEdit reports whether a draft revises posted feedback
+func isReviewedEdit(msg *sharedgov.RefMessage) bool {
+	return msg.RevisesID != nil
+}
+
+// isReviewedReply reports whether a draft answers a proponent's comment
+func isReviewedReply(msg *sharedgov.RefMessage) bool {
+	return msg.PolkassemblyParentID != nil && *msg.PolkassemblyParentID != ""
+}
+
+// rejectFeedback closes a review without posting the feedback
+func (h *Handler) rejectFeedback(s *discordgo.Session, i *discordgo.InteractionCreate, msg *sharedgov.RefMessage, network *sharedgov.Network, ref *sharedgov.Ref) {
+	rejected, err := data.SetFeedbackStatus(h.DB, msg.ID, sharedgov.FeedbackPending, sharedgov.FeedbackRejected)
+	if err != nil || !rejected {
+		if err != nil {
+			log.Printf("feedback: failed to reject draft %d: %v", msg.ID, err)
+		}
+		respondFeedbackEphemeral(s, i.Interaction, "Feedback Review", "This feedback is already being reviewed by someone else.")
+		return
+	}
+
+	h.closeReview(msg, sharedgov.FeedbackRejected, i.Member.User)
+
+	embed := reviewEmbed(network, ref, msg, h.feedbackThreadID(msg.GuildID, network.ID, ref.RefID))
+	if err := shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: []discordgo.MessageComponent{},
+		},
+	}); err != nil {
+		log.Printf("feedback: failed to close review of draft %d: %v", msg.ID, err)
+	}
+}
+
+// closeReview records the reviewer and final status of feedback
+func (h *Handler) closeReview(msg *sharedgov.RefMessage, status string, reviewer *discordgo.User) {
+	now := time.Now()
+	reviewedBy := formatDiscordUsername(reviewer.Username, reviewer.Discriminator)
+	if err := h.DB.Model(&sharedgov.RefMessage{}).Where("id = ?", msg.ID).Updates(map[string]interface{}{
+		"status":      status,
+		"reviewed_by": reviewedBy,
+		"reviewed_at": now,
+	}).Error; err != nil {
+		log.Printf("feedback: failed to record review of draft %d: %v", msg.ID, err)
+	}
+	msg.Status = status
+	msg.ReviewedBy = &reviewedBy
+	msg.ReviewedAt = &now
+}
+
+// loadReview loads feedback under review with its referendum, telling the
+// reviewer when it is gone or no longer pending
+func (h *Handler) loadReview(s *discordgo.Session, i *discordgo.InteractionCreate, messageID uint64) (*sharedgov.RefMessage, *sharedgov.Network, *sharedgov.Ref, bool) {
+	var msg sharedgov.RefMessage
+	if err := h.DB.First(&msg, messageID).Error; err != nil {
+		respondFeedbackEphemeral(s, i.Interaction, "Feedback Review", "This feedback no longer exists.")
+		return nil, nil, nil, false
+	}
+	if msg.Status != sharedgov.FeedbackPending {
+		respondFeedbackEphemeral(s, i.Interaction, "Feedback Review", reviewStatusLine(&msg)+".")
+		return nil, nil, nil, false
+	}
+
+	var ref sharedgov.Ref
+	if err := h.DB.First(&ref, msg.RefID).Error; err != nil {
+		log.Printf("feedback: failed to load referendum %d of draft %d: %v", msg.RefID, msg.ID, err)
+		respondFeedbackEphemeral(s, i.Interaction, "Feedback Review", "Could not load referendum details. Please try again later.")
+		return nil, nil, nil, false
+	}
+	network := h.NetworkManager.GetByID(ref.NetworkID)
+	if network == nil {
+		respondFeedbackEphemeral(s, i.Interaction, "Feedback Review", "Unable to identify the associated network for this feedback.")
+		return nil, nil, nil, false
+	}
+	return &msg, network, &ref, true
+}
+
+// feedbackThreadID returns the guild's thread for a referendum, if mapped
+func (h *Handler) feedbackThreadID(guildID string, networkID uint8, refID uint64) string {
+	var info *sharedgov.ThreadInfo
+	var err error
+	if guildID == "" {
+		info, err = h.RefManager.GetThreadInfo(networkID, uint32(refID))
+	} else {
+		info, err = h.RefManager.GetGuildThreadInfo(guildID, networkID, uint32(refID))
+	}
+	if err != nil {
+		return ""
+	}
+	return info.ThreadID
+}
+
+func reviewEmbed(network *sharedgov.Network, ref *sharedgov.Ref, msg *sharedgov.RefMessage, threadID string) *discordgo.MessageEmbed {
+	color := reviewPendingColor
+	switch msg.Status {
+	case sharedgov.FeedbackApproved:
+		color = reviewApprovedColor
+	case sharedgov.FeedbackRejected:
+		color = reviewRejectedColor
+	}
+
+	author := msg.Author
+	if msg.AuthorID != nil && *msg.AuthorID != "" {
+		author = fmt.Sprintf("%s (<@%s>)", msg.Author, *msg.AuthorID)
+	}
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Submitted by", Value: author, Inline: true},
+	}
+	if threadID != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Thread", Value: "<#" + threadID + ">", Inline: true})
+	}
+	fields = append(fields, &discordgo.MessageEmbedField{Name: "Status", Value: reviewStatusLine(msg)})
+	if msg.Draft != nil && *msg.Draft != msg.Body {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Original draft", Value: truncateReviewText(*msg.Draft, maxReviewDraftField)})
+	}
+
+	title := fmt.Sprintf("Feedback review • %s #%d", network.Name, ref.RefID)
+	if isReviewedReply(msg) {
+		title = fmt.Sprintf("Reply review • %s #%d", network.Name, ref.RefID)
+	} else if isReviewedEdit(msg) {
+		title = fmt.Sprintf("Feedback edit review • %s #%d", network.Name, ref.RefID)
+	}
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: truncateReviewText(msg.Body, maxReviewDescription),
+		Color:       color,
+		Fields:      fields,
+		Timestamp:   msg.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func reviewStatusLine(msg *sharedgov.RefMessage) string {
+	reviewer := "a reviewer"
+	if msg.ReviewedBy != nil && *msg.ReviewedBy != "" {
+		reviewer = *msg.ReviewedBy
+	}
+	switch msg.Status {
+	case sharedgov.FeedbackPending:
+		return "Waiting for review"
+	case sharedgov.FeedbackApproving:
+		return "Being posted to Polkassembly"
+	case sharedgov.FeedbackApproved:
+		return "Approved by " + reviewer + " and posted to Polkassembly"
+	case sharedgov.FeedbackRejected:
+		return "Rejected by " + reviewer
+	default:
+		return "Posted without review"
+	}
+}
+
+func reviewButtons(messageID uint64) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "Approve", Style: discordgo.SuccessButton, CustomID: reviewCustomID(reviewApprove, messageID)},
+			discordgo.Button{Label: "Edit", Style: discordgo.SecondaryButton, CustomID: reviewCustomID(reviewEdit, messageID)},
+			discordgo.Button{Label: "Reject", Style: discordgo.DangerButton, CustomID: reviewCustomID(reviewReject, messageID)},
+		},
+	}}
+}
+
+func reviewCustomID(action string, messageID uint64) string {
+	return reviewCustomIDPrefix + action + ":" + strconv.FormatUint(messageID, 10)
+}
+
+func parseReviewCustomID(customID string) (string, uint64, bool) {
+	action, id, ok := strings.Cut(strings.TrimPrefix(customID, reviewCustomIDPrefix), ":")
+	if !ok || !IsFeedbackReviewInteraction(customID) {
+		return "", 0, false
+	}
+	messageID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return action, messageID, true
+}
+
+// modalTextValue returns the value of a modal's text input
+func modalTextValue(components []discordgo.MessageComponent, customID string) string {
+	for _, component := range components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, inner := range row.Components {
+			if input, ok := inner.(*discordgo.TextInput); ok && input.CustomID == customID {
+				return input.Value
+			}
+		}
+	}
+	return ""
+}
+
+// reviewFollowup tells the reviewer privately about a failure after the
+// interaction was acknowledged
+func reviewFollowup(s *discordgo.Session, interaction *discordgo.Interaction, body string) {
+	if _, err := s.FollowupMessageCreate(interaction, true, &discordgo.WebhookParams{
+		Content: shareddiscord.FormatStyledBlock("Feedback Review", body),
+		Flags:   discordgo.MessageFlagsEphemeral,
+	}); err != nil {
+		log.Printf("feedback: review followup failed: %v", err)
+	}
+}
+
+func truncateReviewText(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
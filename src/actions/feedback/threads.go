@@ -0,0 +1,504 @@
+package feedback
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+	aicore "github.com/stake-plus/govcomms/src/api/ai/core"
+	shareddiscord "github.com/stake-plus/govcomms/src/api/discord"
+	cache "github.com/stake-plus/govcomms/src/data/cache"
+	sharedconfig "github.com/stake-plus/govcomms/src/data/config"
+	polkadot "github.com/stake-plus/govcomms/src/polkadot-go"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+)
+
+const (
+	referendumThreadColor = 0xE6007A
+	maxThreadNameLength   = 100
+	maxAppliedTags        = 5
+	maxTLDRLength         = 1500
+	threadTLDRTimeout     = 2 * time.Minute
+	pendingTLDR           = "Summarizing the proposal…"
+)
+
+// createReferendumThreads opens a forum post in each guild's channel for the
+// network of every newly submitted referendum that guild has no thread for
+// yet, and maps it right away so feedback and votes work before anyone
+// touches it. The posts' TL;DRs are written in the background afterwards.
+func (b *Module) createReferendumThreads(ctx context.Context) {
+	if !b.config.AutoCreateThreads || b.session == nil {
+		return
+	}
+	if err := b.ensureNetworkManager(); err != nil {
+		log.Printf("feedback: thread creation skipped: %v", err)
+		return
+	}
+
+	// Channels each guild maps per network
+	targets := make(map[uint8][]sharedgov.GuildNetwork)
+	for _, guild := range b.guilds.Guilds() {
+		for _, link := range b.guilds.Networks(guild.GuildID) {
+			if link.DiscordChannelID != "" {
+				targets[link.NetworkID] = append(targets[link.NetworkID], link)
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	// Referenda indexed without a submission time count from when they were
+	// first indexed
+	since := time.Now().Add(-time.Duration(b.config.AutoThreadMaxAgeHours) * time.Hour)
+	var refs []sharedgov.Ref
+	if err := b.db.
+		Where("finalized = ? AND (submitted_at >= ? OR (submitted_at IS NULL AND created_at >= ?))", false, since, since).
+		Order("network_id, ref_id").
+		Find(&refs).Error; err != nil {
+		log.Printf("feedback: failed to load new referenda: %v", err)
+		return
+	}
+
+	var summaries []*threadOverview
+	defer func() {
+		if len(summaries) > 0 {
+			go b.writeReferendumTLDRs(ctx, summaries)
+		}
+	}()
+
+	channels := make(map[string]*discordgo.Channel)
+	for i := range refs {
+		if ctx.Err() != nil {
+			return
+		}
+
+		ref := &refs[i]
+		network := b.networkManager.GetByID(ref.NetworkID)
+		if network == nil || len(targets[ref.NetworkID]) == 0 {
+			continue
+		}
+
+		threads, err := b.refManager.ListThreads(ref.NetworkID, uint32(ref.RefID))
+		if err != nil {
+			log.Printf("feedback: failed to load threads for %s ref #%d: %v", network.Name, ref.RefID, err)
+			continue
+		}
+		mapped := make(map[string]bool, len(threads))
+		for _, thread := range threads {
+			guildID := thread.GuildID
+			if guildID == "" {
+				// Mapped before guilds were recorded, by the settings' guild
+				guildID = b.config.Base.GuildID
+			}
+			mapped[guildID] = true
+		}
+
+		// The overview is built once for all guilds
+		var overview *threadOverview
+		for _, link := range targets[ref.NetworkID] {
+			if mapped[link.GuildID] {
+				continue
+			}
+
+			channel, ok := channels[link.DiscordChannelID]
+			if !ok {
+				fetched, err := b.session.Channel(link.DiscordChannelID)
+				if err != nil {
+					log.Printf("feedback: failed to load channel %s for %s: %v", link.DiscordChannelID, network.Name, err)
+				}
+				channel = fetched
+				channels[link.DiscordChannelID] = channel
+			}
+			if channel == nil {
+				continue
+			}
+
+			if overview == nil {
+				overview = b.referendumOverview(network, ref)
+				if overview.content != "" {
+					summaries = append(summaries, overview)
+				}
+			}
+			threadID, err := b.createReferendumThread(channel, link.GuildID, network, ref, overview)
+			if err != nil {
+				log.Printf("feedback: failed to create thread for %s ref #%d in guild %s: %v", network.Name, ref.RefID, link.GuildID, err)
+				continue
+			}
+			log.Printf("feedback: created thread %s for %s ref #%d in guild %s", threadID, network.Name, ref.RefID, link.GuildID)
+		}
+	}
+}
+
+// threadOverview is the opening post of a referendum thread
+type threadOverview struct {
+	network      *sharedgov.Network
+	ref          sharedgov.Ref
+	title        string
+	content      string // proposal text the TL;DR is written from
+	proposalCall *polkadot.ProposalCall
+	name         string
+	embed        *discordgo.MessageEmbed
+	tags         []string
+	posts        []overviewPost
+}
+
+// overviewPost locates an opening post, to add the TL;DR once written
+type overviewPost struct {
+	channelID string
+	messageID string
+}
+
+// referendumOverview builds the opening post of a referendum's threads and
+// stores the proposal title on the referendum. With proposal text to
+// summarize, the post says its TL;DR is on the way.
+func (b *Module) referendumOverview(network *sharedgov.Network, ref *sharedgov.Ref) *threadOverview {
+	content := b.proposalContent(network, ref)
+	title := proposalTitle(content)
+	if title == "" && ref.Title != nil {
+		title = strings.TrimSpace(*ref.Title)
+	}
+	if title != "" && (ref.Title == nil || *ref.Title != title) {
+		if err := b.db.Model(ref).Update("title", title).Error; err != nil {
+			log.Printf("feedback: failed to store title for %s ref #%d: %v", network.Name, ref.RefID, err)
+		}
+		ref.Title = &title
+	}
+
+	var proposalCall *polkadot.ProposalCall
+	if ref.DecodedCall != nil {
+		if parsed, err := polkadot.ParseProposalCall([]byte(*ref.DecodedCall)); err == nil {
+			proposalCall = parsed
+		}
+	}
+
+	tldr := ""
+	if strings.TrimSpace(content) != "" {
+		tldr = pendingTLDR
+	}
+	return &threadOverview{
+		network:      network,
+		ref:          *ref,
+		title:        title,
+		content:      strings.TrimSpace(content),
+		proposalCall: proposalCall,
+		name:         referendumThreadName(network, ref, title),
+		embed:        referendumThreadEmbed(network, ref, title, proposalCall, tldr),
+		tags:         referendumTagNames(network, ref),
+	}
+}
+
+// writeReferendumTLDRs asks the AI provider for the TL;DR of each new
+// referendum in turn and edits it into the opening posts. A failed TL;DR
+// leaves the posts pointing at /summary.
+func (b *Module) writeReferendumTLDRs(ctx context.Context, overviews []*threadOverview) {
+	for _, overview := range overviews {
+		if ctx.Err() != nil {
+			return
+		}
+		if len(overview.posts) == 0 {
+			continue
+		}
+
+		tldr := b.referendumTLDR(ctx, overview.network, &overview.ref, overview.title, overview.content, overview.proposalCall)
+		embeds := []*discordgo.MessageEmbed{referendumThreadEmbed(overview.network, &overview.ref, overview.title, overview.proposalCall, tldr)}
+		for _, post := range overview.posts {
+			if _, err := shareddiscord.EditMessageComplexNoEmbed(b.session, &discordgo.MessageEdit{
+				Channel: post.channelID,
+				ID:      post.messageID,
+				Embeds:  &embeds,
+			}); err != nil {
+				log.Printf("feedback: failed to add TL;DR to %s ref #%d post %s: %v", overview.network.Name, overview.ref.RefID, post.messageID, err)
+			}
+		}
+	}
+}
+
+// threadTLDRClient returns the AI client thread TL;DRs share, building it on
+// first use
+func (b *Module) threadTLDRClient() (aicore.Client, error) {
+	b.tldrMu.Lock()
+	defer b.tldrMu.Unlock()
+	if b.tldrClient != nil {
+		return b.tldrClient, nil
+	}
+	client, err := aicore.NewClient(sharedconfig.LoadQAConfig(b.db).AIConfig.FactoryConfig())
+	if err != nil {
+		return nil, err
+	}
+	b.tldrClient = client
+	return client, nil
+}
+
+// createReferendumThread posts the referendum overview as a forum post, or
+// as a message with a thread in a text channel, and maps the new thread to
+// the guild
+func (b *Module) createReferendumThread(channel *discordgo.Channel, guildID string, network *sharedgov.Network, ref *sharedgov.Ref, overview *threadOverview) (string, error) {
+	message := &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{overview.embed}}
+
+	var thread *discordgo.Channel
+	var post overviewPost
+	var err error
+	if channel.Type == discordgo.ChannelTypeGuildForum {
+		thread, err = shareddiscord.ForumThreadStartNoEmbed(b.session, channel.ID, &discordgo.ThreadStart{
+			Name:        overview.name,
+			AppliedTags: matchForumTags(channel.AvailableTags, overview.tags...),
+		}, message)
+		if err == nil {
+			// A forum post's opening message shares the thread's ID
+			post = overviewPost{channelID: thread.ID, messageID: thread.ID}
+		}
+	} else {
+		var posted *discordgo.Message
+		posted, err = shareddiscord.SendComplexMessageNoEmbed(b.session, channel.ID, message)
+		if err == nil {
+			post = overviewPost{channelID: channel.ID, messageID: posted.ID}
+			thread, err = b.session.MessageThreadStartComplex(channel.ID, posted.ID, &discordgo.ThreadStart{
+				Name: overview.name,
+				Type: discordgo.ChannelTypeGuildPublicThread,
+			})
+		}
+	}
+	if post.messageID != "" {
+		overview.posts = append(overview.posts, post)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := b.refManager.UpsertThreadMapping(guildID, network.ID, uint32(ref.RefID), thread.ID); err != nil {
+		return "", fmt.Errorf("map thread %s: %w", thread.ID, err)
+	}
+	return thread.ID, nil
+}
+
+// proposalContent fetches the proposal text from Polkassembly through the
+// shared referendum cache; empty when it is not available yet
+func (b *Module) proposalContent(network *sharedgov.Network, ref *sharedgov.Ref) string {
+	if b.cacheManager == nil {
+		manager, err := cache.NewManager(sharedconfig.LoadQAConfig(b.db).TempDir)
+		if err != nil {
+			log.Printf("feedback: referendum cache unavailable: %v", err)
+			return ""
+		}
+		b.cacheManager = manager
+	}
+
+	content, err := b.cacheManager.GetProposalContent(network.Name, uint32(ref.RefID))
+	if err != nil {
+		log.Printf("feedback: failed to fetch proposal for %s ref #%d: %v", network.Name, ref.RefID, err)
+		return ""
+	}
+	return content
+}
+
+// referendumTLDR asks the configured AI provider for a short summary of the
+// proposal; empty when there is nothing to summarize or the call fails
+func (b *Module) referendumTLDR(ctx context.Context, network *sharedgov.Network, ref *sharedgov.Ref, title, content string, proposalCall *polkadot.ProposalCall) string {
+	if strings.TrimSpace(content) == "" {
+		return ""
+	}
+
+	aiClient, err := b.threadTLDRClient()
+	if err != nil {
+		log.Printf("feedback: TL;DR skipped for %s ref #%d: %v", network.Name, ref.RefID, err)
+		return ""
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("Write a TL;DR of this blockchain governance proposal in at most 3 short sentences. ")
+	prompt.WriteString("State what is requested, by whom and for what. Reply with the TL;DR only, no heading.\n\n")
+	prompt.WriteString(fmt.Sprintf("Network: %s\nReferendum #%d\nTitle: %s\n\n", network.Name, ref.RefID, title))
+	if proposalCall != nil {
+		prompt.WriteString("On-chain Call (executes on enactment):\n")
+		for _, line := range proposalCall.Lines() {
+			prompt.WriteString(line)
+			prompt.WriteString("\n")
+		}
+		prompt.WriteString("\n")
+	}
+	prompt.WriteString(content)
+
+	ctx, cancel := context.WithTimeout(ctx, threadTLDRTimeout)
+	defer cancel()
+	ctx = aicore.WithUsageScope(ctx, aicore.UsageScope{Feature: aicore.FeatureSummary, NetworkID: network.ID, RefID: uint32(ref.RefID)})
+
+	response, err := aiClient.Respond(ctx, prompt.String(), nil, aicore.Options{})
+	if err != nil {
+		log.Printf("feedback: TL;DR generation failed for %s ref #%d: %v", network.Name, ref.RefID, err)
+		return ""
+	}
+
+	response = strings.TrimSpace(response)
+	if len([]rune(response)) > maxTLDRLength {
+		response = string([]rune(response)[:maxTLDRLength-3]) + "..."
+	}
+	return response
+}
+
+func referendumThreadEmbed(network *sharedgov.Network, ref *sharedgov.Ref, title string, proposalCall *polkadot.ProposalCall, tldr string) *discordgo.MessageEmbed {
+	embedTitle := fmt.Sprintf("%s #%d", network.Name, ref.RefID)
+	if title != "" {
+		embedTitle += " - " + title
+	}
+	if len([]rune(embedTitle)) > 256 {
+		embedTitle = string([]rune(embedTitle)[:253]) + "..."
+	}
+
+	description := "**TL;DR**\n"
+	if tldr != "" {
+		description += tldr
+	} else {
+		description += "No summary yet. Use /summary in this thread once the proposal text is published."
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Track", Value: referendumTrackName(ref), Inline: true},
+	}
+	if ref.Origin != nil && *ref.Origin != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Origin", Value: *ref.Origin, Inline: true})
+	}
+	if proposalCall != nil && len(proposalCall.Amounts) > 0 {
+		amounts := make([]string, 0, len(proposalCall.Amounts))
+		for _, amount := range proposalCall.Amounts {
+			amounts = append(amounts, amount.Amount)
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Requested", Value: strings.Join(amounts, "\n"), Inline: true})
+	}
+	if ref.Submitter != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Proposer", Value: ref.Submitter})
+	}
+
+	links := referendumLinks(network, ref.RefID)
+	if len(links) > 0 {
+		rendered := make([]string, 0, len(links))
+		for _, link := range links {
+			rendered = append(rendered, fmt.Sprintf("[%s](%s)", link.name, link.url))
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Links", Value: strings.Join(rendered, " • ")})
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       embedTitle,
+		Description: description,
+		Color:       referendumThreadColor,
+		Fields:      fields,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+	}
+	if len(links) > 0 {
+		embed.URL = links[0].url
+	}
+	return embed
+}
+
+// referendumThreadName follows the "<id>: <title>" convention that
+// ParseRefIDFromTitle maps
+func referendumThreadName(network *sharedgov.Network, ref *sharedgov.Ref, title string) string {
+	if title == "" {
+		title = fmt.Sprintf("%s referendum", network.Name)
+	}
+	name := fmt.Sprintf("%d: %s", ref.RefID, title)
+	if len([]rune(name)) > maxThreadNameLength {
+		name = string([]rune(name)[:maxThreadNameLength-3]) + "..."
+	}
+	return name
+}
+
+// referendumTrackName returns the track name from the indexer's pass
+// projection, falling back to the track ID
+func referendumTrackName(ref *sharedgov.Ref) string {
+	if ref.PassProjection != nil {
+		if projection, err := polkadot.ParsePassProjection([]byte(*ref.PassProjection)); err == nil && projection.Track != "" {
+			return projection.Track
+		}
+	}
+	if ref.TrackID != nil {
+		return fmt.Sprintf("Track %d", *ref.TrackID)
+	}
+	return "Unknown"
+}
+
+// referendumTagNames lists the forum tag names a referendum post should carry
+func referendumTagNames(network *sharedgov.Network, ref *sharedgov.Ref) []string {
+	names := []string{referendumTrackName(ref)}
+	if ref.Origin != nil {
+		names = append(names, *ref.Origin)
+	}
+	return append(names, network.Name)
+}
+
+// matchForumTags returns the IDs of the channel's tags whose names match any
+// of the given names, ignoring case, spaces and punctuation, so the track
+// "small_spender" matches a tag named "Small Spender"
+func matchForumTags(available []discordgo.ForumTag, names ...string) []string {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		if key := tagKey(name); key != "" {
+			wanted[key] = true
+		}
+	}
+
+	var ids []string
+	for _, tag := range available {
+		if len(ids) == maxAppliedTags {
+			break
+		}
+		if wanted[tagKey(tag.Name)] {
+			ids = append(ids, tag.ID)
+		}
+	}
+	return ids
+}
+
+func tagKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+type referendumLink struct {
+	name string
+	url  string
+}
+
+// referendumLinks returns the explorer pages of a referendum for the
+// network's referenda pallet
+func referendumLinks(network *sharedgov.Network, refID uint64) []referendumLink {
+	switch network.ReferendaPallet {
+	case "FellowshipReferenda":
+		return []referendumLink{
+			{"Polkassembly", fmt.Sprintf("https://collectives.polkassembly.io/member-referenda/%d", refID)},
+			{"Subsquare", fmt.Sprintf("https://collectives.subsquare.io/fellowship/referenda/%d", refID)},
+		}
+	case "AmbassadorReferenda":
+		return []referendumLink{
+			{"Subsquare", fmt.Sprintf("https://collectives.subsquare.io/ambassador/referenda/%d", refID)},
+		}
+	default:
+		name := strings.ToLower(network.Name)
+		return []referendumLink{
+			{"Polkassembly", fmt.Sprintf("https://%s.polkassembly.io/referenda/%d", name, refID)},
+			{"Subsquare", fmt.Sprintf("https://%s.subsquare.io/referenda/%d", name, refID)},
+		}
+	}
+}
+
+// proposalTitle extracts the "Title: " line the referendum cache writes at
+// the top of the proposal text
+func proposalTitle(content string) string {
+	lines := strings.SplitN(content, "\n", 6)
+	for _, line := range lines[:min(len(lines), 5)] {
+		if title, ok := strings.CutPrefix(strings.TrimSpace(line), "Title: "); ok {
+			return strings.TrimSpace(title)
+		}
+	}
+	return ""
+}
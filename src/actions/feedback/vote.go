@@ -0,0 +1,334 @@
+package feedback
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	shareddiscord "github.com/stake-plus/govcomms/src/api/discord"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	daoVoteOpenColor = 0x3498DB
+	daoVoteAyeColor  = 0x22C55E
+	daoVoteNayColor  = 0xEF4444
+	daoVoteNoneColor = 0x9CA3AF
+)
+
+// HandleVoteSlash records a DAO member's /vote in a referendum thread and
+// refreshes the thread's live tally.
+func (h *Handler) HandleVoteSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if h == nil {
+		return
+	}
+
+	user := i.Member
+	if user == nil || user.User == nil {
+		log.Printf("feedback: vote interaction missing member context")
+		return
+	}
+
+	var choiceValue string
+	for _, opt := range i.ApplicationCommandData().Options {
+		if opt.Name == "choice" {
+			choiceValue = opt.StringValue()
+			break
+		}
+	}
+	choice, ok := sharedgov.ParseDaoChoice(choiceValue)
+	if !ok {
+		respondVoteEphemeral(s, i.Interaction, "Choose aye, nay or abstain.")
+		return
+	}
+
+	if err := shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		log.Printf("feedback: failed to acknowledge vote interaction: %v", err)
+		return
+	}
+
+	if h.Deps.EnsureThreadMapping == nil {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "DAO Vote", "Vote action misconfigured: missing thread mapper.")
+		return
+	}
+
+	threadInfo, err := h.Deps.EnsureThreadMapping(i.ChannelID)
+	if err != nil {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "DAO Vote", "This command must be used in a referendum thread.")
+		return
+	}
+
+	var member sharedgov.DaoMember
+	if err := h.DB.Where("guild_id = ? AND discord = ?", threadInfo.GuildID, user.User.ID).First(&member).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("feedback: failed to look up DAO member %s: %v", user.User.ID, err)
+		}
+		respondFeedbackWithStyledEdit(s, i.Interaction, "DAO Vote", "Only registered DAO members can vote.")
+		return
+	}
+
+	network := h.NetworkManager.GetByID(threadInfo.NetworkID)
+	if network == nil {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "DAO Vote", "Unable to identify the associated network for this thread.")
+		return
+	}
+
+	var ref sharedgov.Ref
+	if err := h.DB.First(&ref, threadInfo.RefDBID).Error; err != nil {
+		log.Printf("feedback: failed to load referendum %d: %v", threadInfo.RefDBID, err)
+		respondFeedbackWithStyledEdit(s, i.Interaction, "DAO Vote", "Could not load referendum details. Please try again later.")
+		return
+	}
+
+	h.voteMu.Lock()
+	defer h.voteMu.Unlock()
+
+	var decision sharedgov.DaoDecision
+	err = h.DB.Where("ref_db_id = ? AND guild_id = ?", ref.ID, threadInfo.GuildID).First(&decision).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("feedback: failed to load DAO decision for ref %d: %v", ref.ID, err)
+		respondFeedbackWithStyledEdit(s, i.Interaction, "DAO Vote", "Could not load the DAO tally. Please try again later.")
+		return
+	}
+	if decision.Decided() {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "DAO Vote",
+			fmt.Sprintf("DAO voting on %s referendum #%d has closed with the decision: %s.", network.Name, ref.RefID, daoOutcomeLabel(*decision.Outcome)))
+		return
+	}
+
+	closesAt := sharedgov.DaoVoteCutoff(&ref, h.voteCutoff())
+	if ref.Finalized || (closesAt != nil && time.Now().After(*closesAt)) {
+		respondFeedbackWithStyledEdit(s, i.Interaction, "DAO Vote",
+			fmt.Sprintf("DAO voting on %s referendum #%d is closed.", network.Name, ref.RefID))
+		return
+	}
+
+	vote := sharedgov.DaoVote{
+		RefID:   ref.ID,
+		GuildID: threadInfo.GuildID,
+		Discord: member.Discord,
+		Choice:  choice,
+	}
+	if err := h.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ref_id"}, {Name: "guild_id"}, {Name: "discord"}},
+		DoUpdates: clause.AssignmentColumns([]string{"choice", "updated_at"}),
+	}).Create(&vote).Error; err != nil {
+		log.Printf("feedback: failed to store DAO vote for ref %d: %v", ref.ID, err)
+		respondFeedbackWithStyledEdit(s, i.Interaction, "DAO Vote", "Failed to record your vote. Please try again later.")
+		return
+	}
+
+	if decision.ID == 0 {
+		decision = sharedgov.DaoDecision{
+			RefDBID:   ref.ID,
+			GuildID:   threadInfo.GuildID,
+			NetworkID: network.ID,
+			RefID:     ref.RefID,
+			ThreadID:  &threadInfo.ThreadID,
+		}
+	}
+	if err := h.updateDaoTally(s, &decision, network, &ref, closesAt, false); err != nil {
+		log.Printf("feedback: failed to update DAO tally for %s ref #%d: %v", network.Name, ref.RefID, err)
+	}
+
+	response := fmt.Sprintf("Your vote on %s referendum #%d is recorded as %s.", network.Name, ref.RefID, sharedgov.DaoChoiceName(choice))
+	if closesAt != nil {
+		response += fmt.Sprintf(" You can change it until <t:%d:f>.", closesAt.Unix())
+	} else {
+		response += " You can change it until voting closes."
+	}
+	respondFeedbackWithStyledEdit(s, i.Interaction, "DAO Vote", response)
+}
+
+// closeDaoVotes records each guild's DAO decision on every referendum whose
+// voting cutoff passed or that left the Ongoing state, and marks the guild's
+// tally final. Referenda with a guild thread that no member voted on get a
+// decision too.
+func (h *Handler) closeDaoVotes(s *discordgo.Session) {
+	if h == nil || h.NetworkManager == nil {
+		return
+	}
+
+	h.voteMu.Lock()
+	defer h.voteMu.Unlock()
+
+	var open []sharedgov.DaoDecision
+	if err := h.DB.Where("outcome IS NULL").Order("id").Find(&open).Error; err != nil {
+		log.Printf("feedback: failed to load open DAO votes: %v", err)
+		return
+	}
+
+	var unvoted []sharedgov.RefThread
+	if err := h.DB.
+		Where("NOT EXISTS (SELECT 1 FROM dao_decisions WHERE dao_decisions.ref_db_id = ref_threads.ref_db_id AND dao_decisions.guild_id = ref_threads.guild_id)").
+		Order("id").
+		Find(&unvoted).Error; err != nil {
+		log.Printf("feedback: failed to load referendum threads without DAO votes: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, thread := range unvoted {
+		key := fmt.Sprintf("%s/%d", thread.GuildID, thread.RefDBID)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		threadID := thread.ThreadID
+		open = append(open, sharedgov.DaoDecision{
+			RefDBID:   thread.RefDBID,
+			GuildID:   thread.GuildID,
+			NetworkID: thread.NetworkID,
+			RefID:     thread.RefID,
+			ThreadID:  &threadID,
+		})
+	}
+
+	now := time.Now()
+	for i := range open {
+		decision := &open[i]
+
+		var ref sharedgov.Ref
+		if err := h.DB.First(&ref, decision.RefDBID).Error; err != nil {
+			log.Printf("feedback: failed to load referendum %d for DAO decision: %v", decision.RefDBID, err)
+			continue
+		}
+
+		closesAt := sharedgov.DaoVoteCutoff(&ref, h.voteCutoff())
+		if !ref.Finalized && (closesAt == nil || now.Before(*closesAt)) {
+			continue
+		}
+
+		network := h.NetworkManager.GetByID(ref.NetworkID)
+		if network == nil {
+			continue
+		}
+
+		if err := h.updateDaoTally(s, decision, network, &ref, closesAt, true); err != nil {
+			log.Printf("feedback: failed to record guild %s DAO decision for %s ref #%d: %v", decision.GuildID, network.Name, ref.RefID, err)
+			continue
+		}
+		log.Printf("feedback: guild %s DAO decision for %s ref #%d: %s", decision.GuildID, network.Name, ref.RefID, *decision.Outcome)
+	}
+}
+
+// updateDaoTally recounts the guild's votes onto the decision, records the
+// outcome when final, saves it and posts or edits the tally message in the
+// guild's referendum thread
+func (h *Handler) updateDaoTally(s *discordgo.Session, decision *sharedgov.DaoDecision, network *sharedgov.Network, ref *sharedgov.Ref, closesAt *time.Time, final bool) error {
+	tally, err := sharedgov.TallyDaoVotes(h.DB, decision.GuildID, ref.ID, h.Config.DaoQuorumPercent)
+	if err != nil {
+		return err
+	}
+	decision.Apply(tally)
+	if final {
+		outcome := tally.Outcome()
+		decidedAt := time.Now()
+		decision.Outcome = &outcome
+		decision.DecidedAt = &decidedAt
+	}
+
+	// Guilds without DAO members still get a decision row but no tally post
+	if s != nil && decision.ThreadID != nil && (tally.Members > 0 || decision.TallyMessageID != nil) {
+		embed := daoTallyEmbed(network, ref, decision, tally, closesAt)
+		var posted *discordgo.Message
+		if decision.TallyMessageID != nil {
+			embeds := []*discordgo.MessageEmbed{embed}
+			posted, err = shareddiscord.EditMessageComplexNoEmbed(s, &discordgo.MessageEdit{
+				Channel: *decision.ThreadID,
+				ID:      *decision.TallyMessageID,
+				Embeds:  &embeds,
+			})
+			if err != nil {
+				log.Printf("feedback: failed to edit DAO tally for %s ref #%d, reposting: %v", network.Name, ref.RefID, err)
+			}
+		}
+		if posted == nil {
+			posted, err = shareddiscord.SendComplexMessageNoEmbed(s, *decision.ThreadID, &discordgo.MessageSend{
+				Embeds: []*discordgo.MessageEmbed{embed},
+			})
+			if err != nil {
+				log.Printf("feedback: failed to post DAO tally for %s ref #%d: %v", network.Name, ref.RefID, err)
+			} else {
+				decision.TallyMessageID = &posted.ID
+			}
+		}
+	}
+
+	return h.DB.Save(decision).Error
+}
+
+// voteCutoff returns how long before the decision end DAO voting closes
+func (h *Handler) voteCutoff() time.Duration {
+	return time.Duration(h.Config.DaoVoteCutoffHours) * time.Hour
+}
+
+func daoTallyEmbed(network *sharedgov.Network, ref *sharedgov.Ref, decision *sharedgov.DaoDecision, tally sharedgov.DaoTally, closesAt *time.Time) *discordgo.MessageEmbed {
+	lines := tally.Lines()
+	color := daoVoteOpenColor
+
+	switch {
+	case decision.Decided():
+		lines = append(lines, "", "**DAO decision: "+daoOutcomeLabel(*decision.Outcome)+"**")
+		switch *decision.Outcome {
+		case sharedgov.DaoOutcomeAye:
+			color = daoVoteAyeColor
+		case sharedgov.DaoOutcomeNay:
+			color = daoVoteNayColor
+		default:
+			color = daoVoteNoneColor
+		}
+	case closesAt != nil:
+		lines = append(lines, "", fmt.Sprintf("Voting closes <t:%d:f> (<t:%d:R>). Use /vote to cast or change your vote.", closesAt.Unix(), closesAt.Unix()))
+	default:
+		lines = append(lines, "", "Voting stays open until shortly before the decision period ends. Use /vote to cast or change your vote.")
+	}
+
+	title := fmt.Sprintf("DAO vote • %s #%d", network.Name, ref.RefID)
+	if ref.Title != nil && *ref.Title != "" {
+		title += " - " + *ref.Title
+	}
+	if len([]rune(title)) > 256 {
+		title = string([]rune(title)[:253]) + "..."
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: strings.Join(lines, "\n"),
+		Color:       color,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+func daoOutcomeLabel(outcome string) string {
+	switch outcome {
+	case sharedgov.DaoOutcomeAye:
+		return "Aye"
+	case sharedgov.DaoOutcomeNay:
+		return "Nay"
+	case sharedgov.DaoOutcomeAbstain:
+		return "Abstain"
+	case sharedgov.DaoOutcomeNoQuorum:
+		return "No quorum"
+	default:
+		return outcome
+	}
+}
+
+func respondVoteEphemeral(s *discordgo.Session, interaction *discordgo.Interaction, body string) {
+	shareddiscord.InteractionRespondNoEmbed(s, interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: shareddiscord.FormatStyledBlock("DAO Vote", body),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
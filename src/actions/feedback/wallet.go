@@ -0,0 +1,242 @@
+package feedback
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	shareddiscord "github.com/stake-plus/govcomms/src/api/discord"
+	polkadot "github.com/stake-plus/govcomms/src/polkadot-go"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// walletChallengeTTL is how long a /link-wallet message can be signed
+const walletChallengeTTL = 15 * time.Minute
+
+// genericSS58Prefix encodes member addresses of guilds whose networks have
+// no known prefix yet
+const genericSS58Prefix = 42
+
+// HandleLinkWalletSlash runs the /link-wallet subcommands: start issues a
+// message to sign, verify checks the signature and makes the address a
+// DaoMember of the guild linked to the caller, list and revoke manage the
+// caller's linked addresses.
+func (h *Handler) HandleLinkWalletSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if h == nil {
+		return
+	}
+
+	user := i.Member
+	if user == nil || user.User == nil {
+		log.Printf("feedback: link-wallet interaction missing member context")
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "Choose start, verify, list or revoke.")
+		return
+	}
+	subcommand := options[0]
+	args := make(map[string]string, len(subcommand.Options))
+	for _, opt := range subcommand.Options {
+		args[opt.Name] = strings.TrimSpace(opt.StringValue())
+	}
+
+	switch subcommand.Name {
+	case "start":
+		h.startWalletLink(s, i, i.GuildID, user.User.ID, args["address"])
+	case "verify":
+		h.verifyWalletLink(s, i, i.GuildID, user.User.ID, args["address"], args["signature"])
+	case "list":
+		h.listWalletLinks(s, i, i.GuildID, user.User.ID)
+	case "revoke":
+		h.revokeWalletLink(s, i, i.GuildID, user.User.ID, args["address"])
+	default:
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "Choose start, verify, list or revoke.")
+	}
+}
+
+func (h *Handler) startWalletLink(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, discordID, address string) {
+	address, err := polkadot.NormalizeSS58(address, h.memberAddressPrefix(guildID))
+	if err != nil {
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "That is not a valid SS58 address.")
+		return
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		log.Printf("feedback: failed to generate wallet link nonce: %v", err)
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "Could not start linking. Please try again later.")
+		return
+	}
+
+	expiresAt := time.Now().Add(walletChallengeTTL)
+	challenge := sharedgov.WalletChallenge{
+		GuildID:   guildID,
+		DiscordID: discordID,
+		Address:   address,
+		Message:   sharedgov.WalletLinkMessage(guildID, discordID, address, hex.EncodeToString(nonce), expiresAt),
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := h.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "guild_id"}, {Name: "discord_id"}, {Name: "address"}},
+		DoUpdates: clause.AssignmentColumns([]string{"message", "expires_at", "created_at"}),
+	}).Create(&challenge).Error; err != nil {
+		log.Printf("feedback: failed to store wallet challenge for %s: %v", discordID, err)
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "Could not start linking. Please try again later.")
+		return
+	}
+
+	instructions := fmt.Sprintf("Sign the message below with %s, for example with the Sign & Verify tool of polkadot.js or your wallet extension, "+
+		"then run /link-wallet verify with the hex signature. The message expires at %s.",
+		address, expiresAt.UTC().Format("2006-01-02 15:04 UTC"))
+	shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: shareddiscord.FormatStyledBlock("Link Wallet", instructions) + "\n```\n" + challenge.Message + "\n```",
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func (h *Handler) verifyWalletLink(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, discordID, address, signature string) {
+	address, err := polkadot.NormalizeSS58(address, h.memberAddressPrefix(guildID))
+	if err != nil {
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "That is not a valid SS58 address.")
+		return
+	}
+
+	var challenge sharedgov.WalletChallenge
+	if err := h.DB.Where("guild_id = ? AND discord_id = ? AND address = ?", guildID, discordID, address).First(&challenge).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("feedback: failed to load wallet challenge for %s: %v", discordID, err)
+		}
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "No pending link for that address. Run /link-wallet start first.")
+		return
+	}
+	if time.Now().After(challenge.ExpiresAt) {
+		h.DB.Delete(&challenge)
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "The link message has expired. Run /link-wallet start again.")
+		return
+	}
+
+	raw, err := polkadot.DecodeHex(signature)
+	if err != nil || len(raw) == 0 {
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "The signature must be hex encoded.")
+		return
+	}
+
+	scheme, err := polkadot.VerifySignature(address, []byte(challenge.Message), raw)
+	if err != nil {
+		log.Printf("feedback: wallet link verification failed for %s (%s): %v", discordID, address, err)
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "The signature does not match the address and link message.")
+		return
+	}
+
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := linkDaoMember(tx, guildID, address, discordID, scheme, time.Now()); err != nil {
+			return err
+		}
+		return tx.Delete(&challenge).Error
+	})
+	if err != nil {
+		log.Printf("feedback: failed to link %s to %s: %v", address, discordID, err)
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "Could not save the link. Please try again later.")
+		return
+	}
+
+	log.Printf("feedback: linked %s to Discord user %s (%s)", address, discordID, scheme)
+	respondFeedbackEphemeral(s, i.Interaction, "Link Wallet",
+		fmt.Sprintf("✅ %s is now linked to your account (%s signature).", address, scheme))
+}
+
+// linkDaoMember makes a proven address a DaoMember of the guild linked to a
+// Discord user. An address that is already a member moves to the new Discord
+// account and keeps its admin flag.
+func linkDaoMember(db *gorm.DB, guildID, address, discordID, scheme string, linkedAt time.Time) error {
+	member := sharedgov.DaoMember{
+		GuildID:  guildID,
+		Address:  address,
+		Discord:  discordID,
+		Scheme:   &scheme,
+		LinkedAt: &linkedAt,
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "guild_id"}, {Name: "address"}},
+		DoUpdates: clause.AssignmentColumns([]string{"discord", "scheme", "linked_at"}),
+	}).Create(&member).Error
+}
+
+// memberAddressPrefix returns the SS58 prefix a guild's member addresses are
+// stored with: that of its first network with a known prefix
+func (h *Handler) memberAddressPrefix(guildID string) uint16 {
+	if h.Guilds == nil || h.NetworkManager == nil {
+		return genericSS58Prefix
+	}
+	for _, link := range h.Guilds.Networks(guildID) {
+		if network := h.NetworkManager.GetByID(link.NetworkID); network != nil && network.SS58Prefix != nil {
+			return *network.SS58Prefix
+		}
+	}
+	return genericSS58Prefix
+}
+
+func (h *Handler) listWalletLinks(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, discordID string) {
+	var members []sharedgov.DaoMember
+	if err := h.DB.Where("guild_id = ? AND discord = ?", guildID, discordID).Order("address").Find(&members).Error; err != nil {
+		log.Printf("feedback: failed to list linked addresses of %s: %v", discordID, err)
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "Could not load your linked addresses. Please try again later.")
+		return
+	}
+	if len(members) == 0 {
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "No addresses are linked to your account. Use /link-wallet start to link one.")
+		return
+	}
+
+	lines := make([]string, 0, len(members))
+	for _, member := range members {
+		line := "- " + member.Address
+		if member.LinkedAt != nil {
+			line += " (linked " + member.LinkedAt.UTC().Format("2006-01-02") + ")"
+		}
+		if member.IsAdmin {
+			line += " [admin]"
+		}
+		lines = append(lines, line)
+	}
+	respondFeedbackEphemeral(s, i.Interaction, "Linked Addresses", strings.Join(lines, "\n"))
+}
+
+func (h *Handler) revokeWalletLink(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, discordID, address string) {
+	address, err := polkadot.NormalizeSS58(address, h.memberAddressPrefix(guildID))
+	if err != nil {
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "That is not a valid SS58 address.")
+		return
+	}
+
+	// The address stays a member; only its Discord link is removed
+	result := h.DB.Model(&sharedgov.DaoMember{}).
+		Where("guild_id = ? AND address = ? AND discord = ?", guildID, address, discordID).
+		Updates(map[string]interface{}{"discord": "", "scheme": nil, "linked_at": nil})
+	if result.Error != nil {
+		log.Printf("feedback: failed to revoke %s for %s: %v", address, discordID, result.Error)
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "Could not revoke the link. Please try again later.")
+		return
+	}
+	if result.RowsAffected == 0 {
+		respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", "That address is not linked to your account.")
+		return
+	}
+
+	log.Printf("feedback: unlinked %s from Discord user %s", address, discordID)
+	respondFeedbackEphemeral(s, i.Interaction, "Link Wallet", fmt.Sprintf("%s is no longer linked to your account.", address))
+}
@@ -17,6 +17,7 @@ import (
 	cache "github.com/stake-plus/govcomms/src/data/cache"
 	sharedconfig "github.com/stake-plus/govcomms/src/data/config"
 	"github.com/stake-plus/govcomms/src/data/mcp"
+	polkadot "github.com/stake-plus/govcomms/src/polkadot-go"
 	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
 	"gorm.io/gorm"
 )
@@ -24,11 +25,25 @@ import (
 const answerEmbedColor = 0x3B82F6
 const defaultInteractionTimeout = 2 * time.Minute
 
+// Bounds of the /question text
+const (
+	minQuestionLength = 5
+	maxQuestionLength = 2000
+)
+
+// meteredCommands call the AI provider and are checked against command quotas
+var meteredCommands = map[string]bool{
+	shareddiscord.CommandQuestion: true,
+	shareddiscord.CommandRefresh:  true,
+	shareddiscord.CommandSummary:  true,
+	shareddiscord.CommandReport:   true,
+}
+
 var _ core.Module = (*Module)(nil)
 
 // ReportsGenerator is an interface for generating PDF reports
 type ReportsGenerator interface {
-	GenerateReport(s *discordgo.Session, channelID string, network string, refID uint32, refDBID uint64)
+	GenerateReport(s *discordgo.Session, channelID string, network string, refID uint32, refDBID uint64, invoker aicore.UsageScope)
 }
 
 // Module owns the Discord session and logic for the Q&A action set.
@@ -40,6 +55,9 @@ type Module struct {
 	contextStore    *cache.ContextStore
 	networkManager  *sharedgov.NetworkManager
 	refManager      *sharedgov.ReferendumManager
+	guilds          *sharedgov.GuildManager
+	permissions     *shareddiscord.PermissionManager
+	quotas          *shareddiscord.QuotaManager
 	cancel          context.CancelFunc
 	mcpEnabled      bool
 	mcpBaseURL      string
@@ -84,6 +102,18 @@ func NewModuleWithReports(cfg *sharedconfig.QAConfig, db *gorm.DB, reportsGen Re
 		return nil, fmt.Errorf("question: network manager: %w", err)
 	}
 	refManager := sharedgov.NewReferendumManager(db)
+	guilds, err := sharedgov.NewGuildManager(db, sharedconfig.LegacyGuild(db))
+	if err != nil {
+		return nil, fmt.Errorf("question: load guilds: %w", err)
+	}
+	permissions, err := shareddiscord.NewPermissionManager(db)
+	if err != nil {
+		log.Printf("question: command permissions unavailable, using the QA role only: %v", err)
+	}
+	quotas, err := shareddiscord.NewQuotaManager(db)
+	if err != nil {
+		log.Printf("question: command quotas unavailable, AI commands are unmetered: %v", err)
+	}
 
 	if cfg.AIConfig.OpenAIKey == "" &&
 		cfg.AIConfig.ClaudeKey == "" &&
@@ -107,6 +137,9 @@ func NewModuleWithReports(cfg *sharedconfig.QAConfig, db *gorm.DB, reportsGen Re
 		contextStore:    cache.NewContextStore(db),
 		networkManager:  networkManager,
 		refManager:      refManager,
+		guilds:          guilds,
+		permissions:     permissions,
+		quotas:          quotas,
 		mcpEnabled:      mcpCfg.Enabled,
 		mcpBaseURL:      mcpCfg.Listen,
 		mcpAuthToken:    mcpCfg.AuthToken,
@@ -170,12 +203,6 @@ func (m *Module) initHandlers() {
 		username := formatDiscordUsername(s.State.User.Username, s.State.User.Discriminator)
 		log.Printf("question: logged in as %s", username)
 
-		// Delete old/unwanted commands first
-		unwantedCommands := []string{"research", "team"}
-		if err := shareddiscord.DeleteUnwantedCommands(s, m.cfg.Base.GuildID, unwantedCommands); err != nil {
-			log.Printf("question: failed to delete unwanted commands: %v", err)
-		}
-
 		commands := []string{
 			shareddiscord.CommandQuestion,
 			shareddiscord.CommandRefresh,
@@ -187,13 +214,60 @@ func (m *Module) initHandlers() {
 			commands = append(commands, shareddiscord.CommandReport)
 			log.Printf("question: registering /report command")
 		}
-		if err := shareddiscord.RegisterSlashCommands(s, m.cfg.Base.GuildID, commands...); err != nil {
-			log.Printf("question: register commands failed: %v", err)
+
+		unwantedCommands := []string{"research", "team"}
+		for _, guild := range m.guilds.Guilds() {
+			// Delete old/unwanted commands first
+			if err := shareddiscord.DeleteUnwantedCommands(s, guild.GuildID, unwantedCommands); err != nil {
+				log.Printf("question: failed to delete unwanted commands in guild %s: %v", guild.GuildID, err)
+			}
+			if err := shareddiscord.RegisterSlashCommands(s, guild.GuildID, commands...); err != nil {
+				log.Printf("question: register commands failed in guild %s: %v", guild.GuildID, err)
+			}
 		}
 	})
 
 	m.session.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		switch i.ApplicationCommandData().Name {
+		// Buttons and dialogs belong to other modules
+		if i.Type != discordgo.InteractionApplicationCommand {
+			return
+		}
+		guild := m.guilds.Get(i.GuildID)
+		if guild == nil {
+			return
+		}
+
+		name := i.ApplicationCommandData().Name
+		switch name {
+		case shareddiscord.CommandQuestion, shareddiscord.CommandRefresh, shareddiscord.CommandContext, shareddiscord.CommandSummary, shareddiscord.CommandReport:
+		default:
+			// Commands of other modules sharing the bot token
+			return
+		}
+
+		// /report keeps no default role; the Q&A commands require the
+		// guild's QA role
+		defaultRoleID := guild.QARoleID
+		if name == shareddiscord.CommandReport {
+			defaultRoleID = ""
+		}
+		if decision := shareddiscord.CheckCommandPermission(s, m.permissions, guild.GuildID, i, defaultRoleID); !decision.Allowed {
+			if err := shareddiscord.RespondPermissionDenied(s, i.Interaction, "Permission Denied", decision.Reason); err != nil {
+				log.Printf("question: permission denied response failed: %v", err)
+			}
+			return
+		}
+		// Requests the handlers reject spend no quota
+		if meteredCommands[name] && m.meteredRequestValid(i) {
+			if decision := shareddiscord.CheckCommandQuota(m.quotas, i); !decision.Allowed {
+				if err := shareddiscord.RespondPermissionDenied(s, i.Interaction, "Quota Exceeded", decision.Reason); err != nil {
+					log.Printf("question: quota exceeded response failed: %v", err)
+				}
+				return
+			}
+		}
+
+		switch name {
 		case "question":
 			m.handleQuestionSlash(s, i)
 		case "refresh":
@@ -219,19 +293,31 @@ func (m *Module) initHandlers() {
 	})
 }
 
-func (m *Module) handleQuestionSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if m.cfg.QARoleID != "" && (i.Member == nil || i.Member.User == nil || !shareddiscord.HasRole(s, m.cfg.Base.GuildID, i.Member.User.ID, m.cfg.QARoleID)) {
-		formatted := shareddiscord.FormatStyledBlock("Question", "You don't have permission to use this command.")
-		shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: formatted,
-				Flags:   discordgo.MessageFlagsEphemeral,
-			},
-		})
-		return
+// meteredRequestValid reports whether a metered command was invoked in a
+// referendum thread of a known network and, for /question, with a question
+// of acceptable length
+func (m *Module) meteredRequestValid(i *discordgo.InteractionCreate) bool {
+	threadInfo, err := m.refManager.FindThread(i.ChannelID)
+	if err != nil || threadInfo == nil || m.networkManager.GetByID(threadInfo.NetworkID) == nil {
+		return false
+	}
+	if i.ApplicationCommandData().Name != shareddiscord.CommandQuestion {
+		return true
+	}
+	length := len(questionOption(i))
+	return length >= minQuestionLength && length <= maxQuestionLength
+}
+
+func questionOption(i *discordgo.InteractionCreate) string {
+	for _, opt := range i.ApplicationCommandData().Options {
+		if opt.Name == "question" {
+			return opt.StringValue()
+		}
 	}
+	return ""
+}
 
+func (m *Module) handleQuestionSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	// Respond immediately to avoid "thinking" indicator
 	if err := shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -243,16 +329,7 @@ func (m *Module) handleQuestionSlash(s *discordgo.Session, i *discordgo.Interact
 		return
 	}
 
-	data := i.ApplicationCommandData()
-	var question string
-	for _, opt := range data.Options {
-		if opt.Name == "question" {
-			question = opt.StringValue()
-			break
-		}
-	}
-	const minQuestionLength = 5
-	const maxQuestionLength = 2000
+	question := questionOption(i)
 	if len(question) < minQuestionLength {
 		if _, err := shareddiscord.SendMessageNoEmbed(s, i.ChannelID, "Please provide a valid question (at least 5 characters)."); err != nil {
 			log.Printf("question: failed to send error: %v", err)
@@ -320,6 +397,12 @@ func (m *Module) handleQuestionSlash(s *discordgo.Session, i *discordgo.Interact
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
+	scope := invokerScope(i)
+	scope.Feature = aicore.FeatureQuestion
+	scope.NetworkID = threadInfo.NetworkID
+	scope.RefID = uint32(threadInfo.RefID)
+	ctx = aicore.WithUsageScope(ctx, scope)
+	ctx, servedBy := aicore.WithServedBy(ctx)
 	basePrompt := strings.TrimSpace(aiCfg.AISystemPrompt)
 	respondOpts := aicore.Options{
 		Model:        aiCfg.AIModel,
@@ -341,7 +424,8 @@ func (m *Module) handleQuestionSlash(s *discordgo.Session, i *discordgo.Interact
 		tools = append(tools, *mcptool)
 	}
 
-	answer, err := aiClient.Respond(ctx, input, tools, respondOpts)
+	stream := newAnswerStream(s, i.Interaction, question, providerInfo, modelDisplay)
+	answer, err := aicore.RespondStream(ctx, aiClient, input, tools, respondOpts, stream.onEvent)
 	if err != nil {
 		log.Printf("question: web search failed, fallback: %v", err)
 		fallbackOpts := respondOpts
@@ -353,23 +437,21 @@ func (m *Module) handleQuestionSlash(s *discordgo.Session, i *discordgo.Interact
 	}
 	if err != nil {
 		log.Printf("question: AI failure: %v", err)
-		if _, err := shareddiscord.SendMessageNoEmbed(s, i.ChannelID, "Failed to generate answer. Please try again."); err != nil {
-			log.Printf("question: failed to send error: %v", err)
-		}
+		stream.fail(s, i.ChannelID, "Failed to generate answer. Please try again.")
 		return
 	}
-
-	userID := ""
-	if i.Member != nil && i.Member.User != nil {
-		userID = i.Member.User.ID
-	} else if i.User != nil {
-		userID = i.User.ID
+	if provider, model := servedBy.Provider(); provider != "" {
+		// A fallback provider may have answered instead of the configured one
+		providerInfo, _ = aicore.GetProviderInfo(provider)
+		modelDisplay = formatModelName(provider, model)
 	}
+
+	userID := interactionUserID(i.Interaction)
 	if err := m.contextStore.SaveQA(threadInfo.NetworkID, uint32(threadInfo.RefID), i.ChannelID, userID, question, answer); err != nil {
 		log.Printf("question: save QA history: %v", err)
 	}
 
-	m.sendLongMessageSlash(s, i.Interaction, question, answer, providerInfo, modelDisplay)
+	m.sendLongMessageSlash(s, i.Interaction, question, answer, providerInfo, modelDisplay, stream.reply)
 }
 
 func (m *Module) buildMCPTool(network string, refID uint32) *aicore.Tool {
@@ -432,20 +514,6 @@ func (m *Module) createAIClient() (aicore.Client, sharedconfig.AIConfig, error)
 }
 
 func (m *Module) handleContextSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	// Check permissions first
-	if m.cfg.QARoleID != "" && (i.Member == nil || i.Member.User == nil || !shareddiscord.HasRole(s, m.cfg.Base.GuildID, i.Member.User.ID, m.cfg.QARoleID)) {
-		if err := shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: "You don't have permission to use this command.",
-				Flags:   discordgo.MessageFlagsEphemeral,
-			},
-		}); err != nil {
-			log.Printf("question: permission check response failed: %v", err)
-		}
-		return
-	}
-
 	// Respond immediately to avoid "thinking" indicator
 	if err := shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -502,18 +570,6 @@ func (m *Module) handleContextSlash(s *discordgo.Session, i *discordgo.Interacti
 }
 
 func (m *Module) handleRefreshSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if m.cfg.QARoleID != "" && (i.Member == nil || i.Member.User == nil || !shareddiscord.HasRole(s, m.cfg.Base.GuildID, i.Member.User.ID, m.cfg.QARoleID)) {
-		formatted := shareddiscord.FormatStyledBlock("Refresh", "You don't have permission to use this command.")
-		shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: formatted,
-				Flags:   discordgo.MessageFlagsEphemeral,
-			},
-		})
-		return
-	}
-
 	// Respond immediately to avoid "thinking" indicator - we'll send status updates as regular messages
 	if err := shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -549,13 +605,17 @@ func (m *Module) handleRefreshSlash(s *discordgo.Session, i *discordgo.Interacti
 		return
 	}
 
+	if _, err := m.contextStore.SyncProposalCall(m.cacheManager, network.Name, uint32(threadInfo.RefID)); err != nil {
+		log.Printf("question: proposal call sync failed: %v", err)
+	}
+
 	// Send plain text status update
 	if _, err := shareddiscord.SendMessageNoEmbed(s, i.ChannelID, fmt.Sprintf("✅ Refreshed content for %s referendum #%d\n\n⏳ Processing claims and team analysis...", network.Name, threadInfo.RefID)); err != nil {
 		log.Printf("question: failed to send status update: %v", err)
 	}
 
 	// Run claims and teams analysis (wait for completion)
-	if err := m.runSilentResearch(network.Name, uint32(threadInfo.RefID), threadInfo.RefDBID, threadInfo.NetworkID); err != nil {
+	if err := m.runSilentResearch(network.Name, uint32(threadInfo.RefID), threadInfo.RefDBID, threadInfo.NetworkID, invokerScope(i)); err != nil {
 		log.Printf("question: research failed: %v", err)
 		if _, err := shareddiscord.SendMessageNoEmbed(s, i.ChannelID, fmt.Sprintf("✅ Refreshed content for %s referendum #%d\n\n⚠️ Research processing failed.", network.Name, threadInfo.RefID)); err != nil {
 			log.Printf("question: failed to send error update: %v", err)
@@ -569,7 +629,7 @@ func (m *Module) handleRefreshSlash(s *discordgo.Session, i *discordgo.Interacti
 	}
 
 	// Generate and save summary
-	summary, err := m.generateSummary(network.Name, uint32(threadInfo.RefID), threadInfo.RefDBID, threadInfo.NetworkID)
+	summary, err := m.generateSummary(network.Name, uint32(threadInfo.RefID), threadInfo.RefDBID, threadInfo.NetworkID, invokerScope(i))
 	if err != nil {
 		log.Printf("question: summary generation failed: %v", err)
 		if _, err := shareddiscord.SendMessageNoEmbed(s, i.ChannelID, fmt.Sprintf("✅ Refreshed content for %s referendum #%d\n\n✅ Research completed\n\n⚠️ Summary generation failed.", network.Name, threadInfo.RefID)); err != nil {
@@ -631,39 +691,34 @@ func (m *Module) handleRefreshSlash(s *discordgo.Session, i *discordgo.Interacti
 	// Trigger PDF report generation if reports module is available
 	if m.reportsGen != nil {
 		log.Printf("question: triggering PDF report generation for %s #%d", network.Name, threadInfo.RefID)
-		go m.reportsGen.GenerateReport(s, i.ChannelID, network.Name, uint32(threadInfo.RefID), threadInfo.RefDBID)
+		go m.reportsGen.GenerateReport(s, i.ChannelID, network.Name, uint32(threadInfo.RefID), threadInfo.RefDBID, invokerScope(i))
 	} else {
 		log.Printf("question: reports generator not available, skipping PDF generation")
 	}
 }
 
-func (m *Module) sendLongMessageSlash(s *discordgo.Session, interaction *discordgo.Interaction, question string, message string, providerInfo aicore.ProviderInfo, model string) {
-	userID := ""
-	if interaction.Member != nil && interaction.Member.User != nil {
-		userID = interaction.Member.User.ID
-	} else if interaction.User != nil {
-		userID = interaction.User.ID
-	}
-
-	answerCleaned, refs := shareddiscord.ReplaceURLsAndCollect(message)
-	if strings.TrimSpace(answerCleaned) == "" {
-		answerCleaned = "_No content_"
+// sendLongMessageSlash posts the final answer. With a streaming reply the
+// streamed messages are replaced; otherwise the answer is sent as follow-ups.
+func (m *Module) sendLongMessageSlash(s *discordgo.Session, interaction *discordgo.Interaction, question string, message string, providerInfo aicore.ProviderInfo, model string, reply *shareddiscord.StreamingReply) {
+	payloads, buttons := buildAnswerPayloads(interactionUserID(interaction), question, message, providerInfo, model)
+	if len(payloads) == 0 {
+		return
 	}
-	answerBody := buildQuestionResponseBody(providerInfo, model, question, strings.TrimSpace(answerCleaned))
+	// Add buttons to first message
+	payloads[0].Components = buttons
 
-	payloads := shareddiscord.BuildStyledMessages("", answerBody, userID)
-	if len(payloads) == 0 {
+	if reply != nil {
+		if err := reply.Flush(payloads); err != nil {
+			log.Printf("question: response send failed: %v", err)
+		}
 		return
 	}
 
 	// Send all messages as follow-ups since we already responded immediately
-	for idx, payload := range payloads {
+	for _, payload := range payloads {
 		msg := &discordgo.MessageSend{
-			Content: payload.Content,
-		}
-		if len(refs) > 0 && idx == 0 {
-			// Add buttons to first message
-			msg.Components = shareddiscord.BuildLinkButtons(refs)
+			Content:    payload.Content,
+			Components: payload.Components,
 		}
 		if _, err := shareddiscord.SendComplexMessageNoEmbed(s, interaction.ChannelID, msg); err != nil {
 			log.Printf("question: response send failed: %v", err)
@@ -715,9 +770,13 @@ func buildQuestionResponseBody(providerInfo aicore.ProviderInfo, model, question
 
 // runSilentResearch runs claims and teams analysis silently and saves results to cache metadata.
 // Returns an error if research fails completely, nil if successful or partially successful.
-func (m *Module) runSilentResearch(network string, refID uint32, refDBID uint64, networkID uint8) error {
+// Token usage is billed to invoker, the command and member that asked for it.
+func (m *Module) runSilentResearch(network string, refID uint32, refDBID uint64, networkID uint8, invoker aicore.UsageScope) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
 	defer cancel()
+	scope := invoker
+	scope.Feature, scope.NetworkID, scope.RefID = aicore.FeatureResearch, networkID, refID
+	ctx = aicore.WithUsageScope(ctx, scope)
 
 	// Load AI config to get provider/model info
 	aiCfg := sharedconfig.LoadQAConfig(m.db).AIConfig
@@ -966,7 +1025,7 @@ resultLoop:
 }
 
 // generateSummary creates a summary of the referendum using AI and research data.
-func (m *Module) generateSummary(network string, refID uint32, refDBID uint64, networkID uint8) (*cache.SummaryData, error) {
+func (m *Module) generateSummary(network string, refID uint32, refDBID uint64, networkID uint8, invoker aicore.UsageScope) (*cache.SummaryData, error) {
 	// Get cache entry to access research data
 	entry, err := m.cacheManager.EnsureEntry(network, refID)
 	if err != nil {
@@ -996,6 +1055,18 @@ func (m *Module) generateSummary(network string, refID uint32, refDBID uint64, n
 	summaryContext.WriteString(proposalContent)
 	summaryContext.WriteString("\n\n")
 
+	// Add the decoded on-chain call so the summary reflects what actually executes
+	if ref.DecodedCall != nil {
+		if proposalCall, err := polkadot.ParseProposalCall([]byte(*ref.DecodedCall)); err == nil {
+			summaryContext.WriteString("On-chain Call (executes on enactment):\n")
+			for _, line := range proposalCall.Lines() {
+				summaryContext.WriteString(line)
+				summaryContext.WriteString("\n")
+			}
+			summaryContext.WriteString("\n")
+		}
+	}
+
 	// Add claims data with full details
 	if entry.Claims != nil && len(entry.Claims.Results) > 0 {
 		summaryContext.WriteString("Verified Claims (with URLs and evidence):\n")
@@ -1107,6 +1178,10 @@ Proposal Data:
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
+	scope := invoker
+	scope.Feature, scope.NetworkID, scope.RefID = aicore.FeatureSummary, networkID, refID
+	ctx = aicore.WithUsageScope(ctx, scope)
+	ctx, servedBy := aicore.WithServedBy(ctx)
 
 	log.Printf("question: calling AI to generate summary for %s #%d", network, refID)
 	response, err := aiClient.Respond(ctx, prompt, nil, aicore.Options{})
@@ -1115,6 +1190,12 @@ Proposal Data:
 		return nil, fmt.Errorf("AI response: %w", err)
 	}
 	log.Printf("question: received AI summary response for %s #%d (length: %d)", network, refID, len(response))
+	if provider, model := servedBy.Provider(); provider != "" {
+		if info, ok := aicore.GetProviderInfo(provider); ok && info.Company != "" {
+			providerCompany = info.Company
+		}
+		modelName = model
+	}
 
 	// Parse AI response (extract JSON)
 	var aiResponse struct {
@@ -1598,6 +1679,132 @@ func (m *Module) formatSummary(summary *cache.SummaryData, channelTitle string)
 	return embeds
 }
 
+// passProjectionEmbed shows how the tally compares with the track's approval
+// and support curves, as last evaluated by the indexer
+func (m *Module) passProjectionEmbed(refDBID uint64) *SummaryEmbed {
+	var ref sharedgov.Ref
+	if err := m.db.Select("id", "status", "pass_projection").Where("id = ?", refDBID).First(&ref).Error; err != nil {
+		return nil
+	}
+	if ref.PassProjection == nil || ref.Status == nil || *ref.Status != "Ongoing" {
+		return nil
+	}
+
+	projection, err := polkadot.ParsePassProjection([]byte(*ref.PassProjection))
+	if err != nil {
+		log.Printf("question: %v", err)
+		return nil
+	}
+
+	color := 0xEF4444 // Red
+	switch {
+	case projection.Passing:
+		color = 0x22C55E // Green
+	case projection.WillPass:
+		color = 0xF59E0B // Amber
+	}
+
+	return &SummaryEmbed{
+		Title:       "On-chain Vote Status 🗳️",
+		Description: strings.Join(projection.Lines(), "\n"),
+		Color:       color,
+	}
+}
+
+// treasuryPayoutEmbed reports whether the treasury spends created by an
+// approved referendum have been paid
+func (m *Module) treasuryPayoutEmbed(refDBID uint64) *SummaryEmbed {
+	var spends []sharedgov.RefSpend
+	if err := m.db.Where("ref_db_id = ?", refDBID).Order("kind, spend_index").Find(&spends).Error; err != nil {
+		log.Printf("question: failed to load treasury spends: %v", err)
+		return nil
+	}
+	if len(spends) == 0 {
+		return nil
+	}
+
+	color := 0x22C55E // Green
+	lines := make([]string, 0, len(spends))
+	for i := range spends {
+		lines = append(lines, spends[i].StatusLine())
+		switch spends[i].Status {
+		case sharedgov.SpendStatusFailed, sharedgov.SpendStatusExpired:
+			color = 0xEF4444 // Red
+		case sharedgov.SpendStatusPending, sharedgov.SpendStatusCleared:
+			if color != 0xEF4444 {
+				color = 0xF59E0B // Amber
+			}
+		}
+	}
+
+	return &SummaryEmbed{
+		Title:       "Treasury Payout 💸",
+		Description: strings.Join(lines, "\n"),
+		Color:       color,
+	}
+}
+
+// accountsEmbed annotates the referendum's submitter, depositors and
+// beneficiaries with their on-chain identities
+func (m *Module) accountsEmbed(refDBID uint64) *SummaryEmbed {
+	var ref sharedgov.Ref
+	if err := m.db.Where("id = ?", refDBID).First(&ref).Error; err != nil {
+		return nil
+	}
+
+	var beneficiaries []string
+	if ref.DecodedCall != nil {
+		if call, err := polkadot.ParseProposalCall([]byte(*ref.DecodedCall)); err == nil {
+			beneficiaries = append(beneficiaries, call.Beneficiaries...)
+		}
+	}
+	var spends []sharedgov.RefSpend
+	if err := m.db.Where("ref_db_id = ?", refDBID).Find(&spends).Error; err == nil {
+		beneficiaries = append(beneficiaries, sharedgov.SpendBeneficiaries(spends)...)
+	}
+
+	accounts := sharedgov.RefAccounts(&ref, beneficiaries)
+	if len(accounts) == 0 {
+		return nil
+	}
+	identities, err := sharedgov.LoadIdentities(m.db, ref.NetworkID, sharedgov.Addresses(accounts))
+	if err != nil {
+		log.Printf("question: failed to load identities: %v", err)
+	}
+
+	var b strings.Builder
+	for _, account := range accounts {
+		identity, resolved := identities[account.Address]
+		if !resolved {
+			fmt.Fprintf(&b, "**%s:** `%s` (identity not resolved yet)\n", account.Role, account.Address)
+			continue
+		}
+		name := identity.Name()
+		if name == "" {
+			fmt.Fprintf(&b, "**%s:** `%s`\n", account.Role, account.Address)
+		} else {
+			if identity.Verified {
+				name += " ✅"
+			}
+			fmt.Fprintf(&b, "**%s:** %s - `%s`\n", account.Role, name, account.Address)
+		}
+		for _, line := range identity.Details() {
+			fmt.Fprintf(&b, "> %s\n", line)
+		}
+	}
+
+	description := strings.TrimRight(b.String(), "\n")
+	if runes := []rune(description); len(runes) > 4000 {
+		description = string(runes[:4000]) + "…"
+	}
+
+	return &SummaryEmbed{
+		Title:       "On-chain Accounts 🪪",
+		Description: description,
+		Color:       0x3B82F6,
+	}
+}
+
 // splitLongText splits a long text into chunks that fit within maxChars, preserving line breaks.
 func splitLongText(prefix string, text string, maxChars int) []string {
 	if len(prefix)+len(text) <= maxChars {
@@ -1671,18 +1878,6 @@ func splitLongText(prefix string, text string, maxChars int) []string {
 
 // handleSummarySlash handles the /summary command.
 func (m *Module) handleSummarySlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if m.cfg.QARoleID != "" && (i.Member == nil || i.Member.User == nil || !shareddiscord.HasRole(s, m.cfg.Base.GuildID, i.Member.User.ID, m.cfg.QARoleID)) {
-		formatted := shareddiscord.FormatStyledBlock("Summary", "You don't have permission to use this command.")
-		shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: formatted,
-				Flags:   discordgo.MessageFlagsEphemeral,
-			},
-		})
-		return
-	}
-
 	// Respond immediately to avoid "thinking" indicator
 	if err := shareddiscord.InteractionRespondNoEmbed(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -1738,6 +1933,15 @@ func (m *Module) handleSummarySlash(s *discordgo.Session, i *discordgo.Interacti
 
 	// Format and send summary as embeds
 	summaryEmbeds := m.formatSummary(entry.Summary, channelName)
+	if embed := m.passProjectionEmbed(threadInfo.RefDBID); embed != nil {
+		summaryEmbeds = append(summaryEmbeds, *embed)
+	}
+	if embed := m.treasuryPayoutEmbed(threadInfo.RefDBID); embed != nil {
+		summaryEmbeds = append(summaryEmbeds, *embed)
+	}
+	if embed := m.accountsEmbed(threadInfo.RefDBID); embed != nil {
+		summaryEmbeds = append(summaryEmbeds, *embed)
+	}
 
 	if len(summaryEmbeds) == 0 {
 		if _, err := shareddiscord.SendMessageNoEmbed(s, i.ChannelID, "Summary formatting failed."); err != nil {
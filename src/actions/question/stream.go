@@ -0,0 +1,124 @@
+package question
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	aicore "github.com/stake-plus/govcomms/src/api/ai/core"
+	shareddiscord "github.com/stake-plus/govcomms/src/api/discord"
+)
+
+// answerStream edits the reply to a /question while the answer is generated.
+type answerStream struct {
+	reply        *shareddiscord.StreamingReply
+	userID       string
+	question     string
+	providerInfo aicore.ProviderInfo
+	model        string
+	draft        strings.Builder
+}
+
+func newAnswerStream(s *discordgo.Session, interaction *discordgo.Interaction, question string, providerInfo aicore.ProviderInfo, model string) *answerStream {
+	header, err := shareddiscord.InteractionHeader(s, interaction)
+	if err != nil {
+		log.Printf("question: failed to look up reply message: %v", err)
+	}
+	return &answerStream{
+		reply:        header.Stream(s, shareddiscord.DefaultStreamInterval),
+		userID:       interactionUserID(interaction),
+		question:     question,
+		providerInfo: providerInfo,
+		model:        model,
+	}
+}
+
+func (a *answerStream) onEvent(event aicore.StreamEvent) error {
+	switch event.Type {
+	case aicore.StreamText:
+		a.draft.WriteString(event.Text)
+		a.show(a.draft.String() + " …")
+	case aicore.StreamToolCall:
+		// Text before a tool call is the model working towards an answer
+		a.draft.Reset()
+		a.show(toolStatus(event))
+	case aicore.StreamRestart:
+		// The provider failed part way; the next one in the chain starts over
+		a.draft.Reset()
+		a.providerInfo, _ = aicore.GetProviderInfo(event.Provider)
+		a.model = formatModelName(event.Provider, event.Model)
+		a.show("_Retrying with another provider…_")
+	}
+	return nil
+}
+
+func (a *answerStream) show(answer string) {
+	payloads, _ := buildAnswerPayloads(a.userID, a.question, answer, a.providerInfo, a.model)
+	if err := a.reply.Update(payloads); err != nil {
+		log.Printf("question: failed to update streamed answer: %v", err)
+	}
+}
+
+// fail replaces whatever was streamed with an error notice.
+func (a *answerStream) fail(s *discordgo.Session, channelID, notice string) {
+	if err := a.reply.Flush(shareddiscord.BuildStyledMessages("", notice, "")); err != nil {
+		log.Printf("question: failed to replace streamed answer: %v", err)
+		if _, err := shareddiscord.SendMessageNoEmbed(s, channelID, notice); err != nil {
+			log.Printf("question: failed to send error: %v", err)
+		}
+	}
+}
+
+func toolStatus(event aicore.StreamEvent) string {
+	var args struct {
+		Resource string `json:"resource"`
+		File     string `json:"file"`
+	}
+	_ = json.Unmarshal([]byte(event.Arguments), &args)
+	switch {
+	case strings.TrimSpace(args.File) != "":
+		return fmt.Sprintf("_Reading %s…_", strings.TrimSpace(args.File))
+	case strings.TrimSpace(args.Resource) != "":
+		return fmt.Sprintf("_Reading the referendum %s…_", strings.TrimSpace(args.Resource))
+	default:
+		return fmt.Sprintf("_Calling %s…_", event.Tool)
+	}
+}
+
+// buildAnswerPayloads formats an answer for Discord, moving its links into
+// buttons, and returns the split messages along with the buttons.
+func buildAnswerPayloads(userID, question, answer string, providerInfo aicore.ProviderInfo, model string) ([]shareddiscord.StyledMessage, []discordgo.MessageComponent) {
+	answerCleaned, refs := shareddiscord.ReplaceURLsAndCollect(answer)
+	if strings.TrimSpace(answerCleaned) == "" {
+		answerCleaned = "_No content_"
+	}
+	answerBody := buildQuestionResponseBody(providerInfo, model, question, strings.TrimSpace(answerCleaned))
+
+	var buttons []discordgo.MessageComponent
+	if len(refs) > 0 {
+		buttons = shareddiscord.BuildLinkButtons(refs)
+	}
+	return shareddiscord.BuildStyledMessages("", answerBody, userID), buttons
+}
+
+// invokerScope bills model calls to the slash command of i and the member
+// and guild that ran it.
+func invokerScope(i *discordgo.InteractionCreate) aicore.UsageScope {
+	return aicore.UsageScope{
+		Command: i.ApplicationCommandData().Name,
+		UserID:  interactionUserID(i.Interaction),
+		GuildID: i.GuildID,
+	}
+}
+
+func interactionUserID(interaction *discordgo.Interaction) string {
+	if interaction.Member != nil && interaction.Member.User != nil {
+		return interaction.Member.User.ID
+	}
+	if interaction.User != nil {
+		return interaction.User.ID
+	}
+	return ""
+}
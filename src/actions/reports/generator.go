@@ -10,6 +10,7 @@ import (
 	"github.com/jung-kurt/gofpdf/v2"
 	"github.com/stake-plus/govcomms/src/actions/research/claims"
 	"github.com/stake-plus/govcomms/src/data/cache"
+	polkadot "github.com/stake-plus/govcomms/src/polkadot-go"
 	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
 )
 
@@ -402,6 +403,12 @@ type ReportData struct {
 	TeamMembers  *cache.TeamsData
 	ProposalText string
 	Ref          *sharedgov.Ref
+	ProposalCall *polkadot.ProposalCall   // Decoded on-chain call
+	Lifecycle    []sharedgov.RefEvent     // On-chain lifecycle transitions
+	Projection   *polkadot.PassProjection // Tally against track curves
+	Spends       []sharedgov.RefSpend     // Treasury payouts after approval
+	Accounts     []sharedgov.RefAccount   // Submitter, depositors and beneficiaries
+	Identities   sharedgov.IdentityDirectory
 	// Additional analysis sections
 	Financials      *FinancialAnalysis
 	RiskAssessment  *RiskAnalysis
@@ -661,10 +668,182 @@ func (g *Generator) addOverviewPage(pdf *gofpdf.Fpdf, data *ReportData) {
 		pdf.Ln(6)
 	}
 
+	g.addProjectionSection(pdf, data.Projection)
+	g.addAccountsSection(pdf, data.Accounts, data.Identities)
+	g.addSpendsSection(pdf, data.Spends)
+	g.addLifecycleSection(pdf, data.Lifecycle)
+	g.addOnChainCallSection(pdf, data.ProposalCall, data.Identities)
+
 	// Page break after overview page
 	pdf.AddPage()
 }
 
+// addAccountsSection lists the referendum's accounts with their on-chain identities
+func (g *Generator) addAccountsSection(pdf *gofpdf.Fpdf, accounts []sharedgov.RefAccount, identities sharedgov.IdentityDirectory) {
+	if len(accounts) == 0 {
+		return
+	}
+
+	pdf.Ln(6)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 10, "On-chain Accounts", "", 0, "L", false, 0, "")
+	pdf.Ln(8)
+
+	for _, account := range accounts {
+		pdf.SetFont("Arial", "B", 10)
+		g.multiCell(pdf, 0, 6, fmt.Sprintf("%s: %s", account.Role, identities.Label(account.Address)), "", "", false)
+
+		identity, ok := identities[account.Address]
+		if !ok {
+			continue
+		}
+		pdf.SetFont("Arial", "", 9)
+		pdf.SetTextColor(96, 96, 96)
+		for _, line := range identity.Details() {
+			g.multiCell(pdf, 0, 5, "    "+line, "", "", false)
+		}
+		pdf.SetTextColor(0, 0, 0)
+	}
+	pdf.SetFont("Arial", "", 10)
+}
+
+// addSpendsSection lists treasury spends created by the referendum and their payout status
+func (g *Generator) addSpendsSection(pdf *gofpdf.Fpdf, spends []sharedgov.RefSpend) {
+	if len(spends) == 0 {
+		return
+	}
+
+	pdf.Ln(6)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 10, "Treasury Payout", "", 0, "L", false, 0, "")
+	pdf.Ln(8)
+
+	pdf.SetFont("Arial", "", 10)
+	for i := range spends {
+		g.multiCell(pdf, 0, 6, spends[i].StatusLine(), "", "", false)
+	}
+}
+
+// addProjectionSection shows the tally against the track's approval and support curves
+func (g *Generator) addProjectionSection(pdf *gofpdf.Fpdf, projection *polkadot.PassProjection) {
+	if projection == nil {
+		return
+	}
+
+	pdf.Ln(6)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 10, "Vote Status vs Track Thresholds", "", 0, "L", false, 0, "")
+	pdf.Ln(8)
+
+	pdf.SetFont("Arial", "", 10)
+	for _, line := range projection.Lines() {
+		g.multiCell(pdf, 0, 6, line, "", "", false)
+	}
+}
+
+// addLifecycleSection renders the referendum's on-chain lifecycle as a timeline
+func (g *Generator) addLifecycleSection(pdf *gofpdf.Fpdf, events []sharedgov.RefEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	pdf.Ln(6)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 10, "On-chain Timeline", "", 0, "L", false, 0, "")
+	pdf.Ln(8)
+
+	for _, event := range events {
+		when := fmt.Sprintf("Block #%d", event.BlockNumber)
+		if event.OccurredAt != nil {
+			when = fmt.Sprintf("%s (%s)", event.OccurredAt.Format("Jan 2, 2006 15:04 MST"), when)
+		}
+
+		textX, y := g.drawStatusIcon(pdf, pdf.GetX(), pdf.GetY(), timelineIconStatus(event.Event))
+		pdf.SetXY(textX, y)
+
+		pdf.SetFont("Arial", "B", 10)
+		transition := event.Event
+		if event.OldStatus != "" && event.OldStatus != event.NewStatus {
+			transition = fmt.Sprintf("%s: %s -> %s", event.Event, event.OldStatus, event.NewStatus)
+		}
+		g.cellFormat(pdf, 0, 6, transition, "", 1, "L", false, 0, "")
+
+		pdf.SetX(textX)
+		pdf.SetFont("Arial", "", 9)
+		pdf.SetTextColor(96, 96, 96)
+		detail := when
+		if event.Ayes != nil || event.Nays != nil {
+			detail += fmt.Sprintf(" | Ayes %s, Nays %s", valueOrDash(event.Ayes), valueOrDash(event.Nays))
+			if event.Support != nil {
+				detail += fmt.Sprintf(", Support %s", *event.Support)
+			}
+		}
+		g.multiCell(pdf, 0, 5, detail, "", "", false)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.Ln(1)
+	}
+	pdf.SetFont("Arial", "", 10)
+}
+
+// timelineIconStatus maps a lifecycle event to a drawStatusIcon status
+func timelineIconStatus(event string) string {
+	switch event {
+	case "Approved", "ConfirmStarted":
+		return "valid"
+	case "Rejected", "Cancelled", "Killed", "TimedOut", "ConfirmAborted":
+		return "invalid"
+	default:
+		return "unverified"
+	}
+}
+
+func valueOrDash(value *string) string {
+	if value == nil || *value == "" {
+		return "-"
+	}
+	return *value
+}
+
+// addOnChainCallSection lists the decoded call that executes on enactment
+func (g *Generator) addOnChainCallSection(pdf *gofpdf.Fpdf, proposalCall *polkadot.ProposalCall, identities sharedgov.IdentityDirectory) {
+	if proposalCall == nil || proposalCall.Call == nil {
+		return
+	}
+
+	pdf.Ln(6)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 10, "On-chain Call (executes on enactment)", "", 0, "L", false, 0, "")
+	pdf.Ln(8)
+
+	pdf.SetFont("Arial", "", 10)
+	for _, amount := range proposalCall.Amounts {
+		g.multiCell(pdf, 0, 6, fmt.Sprintf("Amount: %s (%s)", amount.Amount, amount.Call), "", "", false)
+	}
+	for _, beneficiary := range proposalCall.Beneficiaries {
+		g.multiCell(pdf, 0, 6, fmt.Sprintf("Beneficiary: %s", identities.Label(beneficiary)), "", "", false)
+	}
+	if len(proposalCall.Amounts) > 0 || len(proposalCall.Beneficiaries) > 0 {
+		pdf.Ln(3)
+	}
+
+	const maxLines = 40
+	lines := proposalCall.Lines()
+	pdf.SetFont("Courier", "", 8)
+	for idx, line := range lines {
+		if idx == maxLines {
+			g.multiCell(pdf, 0, 4, fmt.Sprintf("... %d more lines", len(lines)-maxLines), "", "", false)
+			break
+		}
+		g.multiCell(pdf, 0, 4, line, "", "", false)
+	}
+	pdf.SetFont("Arial", "", 10)
+}
+
 func (g *Generator) addSummaryPage(pdf *gofpdf.Fpdf, data *ReportData) {
 	// Don't force page break - let it flow naturally
 
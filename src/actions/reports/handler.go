@@ -14,6 +14,7 @@ import (
 	cache "github.com/stake-plus/govcomms/src/data/cache"
 	sharedconfig "github.com/stake-plus/govcomms/src/data/config"
 	"github.com/stake-plus/govcomms/src/data/mcp"
+	polkadot "github.com/stake-plus/govcomms/src/polkadot-go"
 	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
 	"gorm.io/gorm"
 )
@@ -44,7 +45,7 @@ func NewHandler(cfg *sharedconfig.ReportsConfig, db *gorm.DB, networkManager *sh
 }
 
 // GenerateReport generates a PDF report for a referendum
-func (h *Handler) GenerateReport(s *discordgo.Session, channelID string, network string, refID uint32, refDBID uint64) {
+func (h *Handler) GenerateReport(s *discordgo.Session, channelID string, network string, refID uint32, refDBID uint64, invoker aicore.UsageScope) {
 	log.Printf("reports: GenerateReport called for %s #%d", network, refID)
 
 	if h.cacheManager == nil {
@@ -67,11 +68,11 @@ func (h *Handler) GenerateReport(s *discordgo.Session, channelID string, network
 
 	log.Printf("reports: summary found, starting PDF generation for %s #%d", network, refID)
 	// Generate PDF in background
-	go h.generateAndSendPDF(s, channelID, network, refID, refDBID, entry)
+	go h.generateAndSendPDF(s, channelID, network, refID, refDBID, invoker, entry)
 }
 
 // generateAndSendPDF generates a comprehensive PDF report and uploads it to Discord
-func (h *Handler) generateAndSendPDF(s *discordgo.Session, channelID string, network string, refID uint32, refDBID uint64, entry *cache.Entry) {
+func (h *Handler) generateAndSendPDF(s *discordgo.Session, channelID string, network string, refID uint32, refDBID uint64, invoker aicore.UsageScope, entry *cache.Entry) {
 	// Get channel name from Discord
 	channelName := ""
 	if channel, err := s.Channel(channelID); err == nil && channel != nil {
@@ -79,6 +80,12 @@ func (h *Handler) generateAndSendPDF(s *discordgo.Session, channelID string, net
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
 	defer cancel()
+	scope := invoker
+	scope.Feature, scope.RefID = aicore.FeatureReport, refID
+	if netInfo := h.NetworkManager.GetByName(network); netInfo != nil {
+		scope.NetworkID = netInfo.ID
+	}
+	ctx = aicore.WithUsageScope(ctx, scope)
 
 	log.Printf("reports: starting PDF report generation for %s #%d", network, refID)
 
@@ -293,7 +300,12 @@ func (h *Handler) generateAndSendPDF(s *discordgo.Session, channelID string, net
 		SummaryNotes:         summaryNotes,
 		FinancialsNotes:      financialsNotes,
 		TeamMemberDetailsMap: teamDetailsMap,
+		ProposalCall:         loadProposalCall(&ref, entry),
+		Lifecycle:            h.loadLifecycle(refDBID),
+		Projection:           loadPassProjection(&ref),
+		Spends:               h.loadSpends(refDBID),
 	}
+	reportData.Accounts, reportData.Identities = h.loadAccounts(&ref, reportData.ProposalCall, reportData.Spends)
 
 	// Generate PDF
 	generator := NewGenerator(h.Config.TempDir)
@@ -408,7 +420,7 @@ func (h *Handler) HandleReportSlash(s *discordgo.Session, i *discordgo.Interacti
 	}
 
 	// Generate and send PDF
-	go h.generateAndSendPDF(s, i.ChannelID, network.Name, uint32(threadInfo.RefID), threadInfo.RefDBID, entry)
+	go h.generateAndSendPDF(s, i.ChannelID, network.Name, uint32(threadInfo.RefID), threadInfo.RefDBID, invokerScope(i), entry)
 
 	// Send initial response
 	formatted := shareddiscord.FormatStyledBlock("Report", fmt.Sprintf("Generating PDF report for %s referendum #%d...", network.Name, threadInfo.RefID))
@@ -416,3 +428,90 @@ func (h *Handler) HandleReportSlash(s *discordgo.Session, i *discordgo.Interacti
 		Content: &formatted,
 	})
 }
+
+// invokerScope bills model calls to the slash command of i and the member
+// and guild that ran it
+func invokerScope(i *discordgo.InteractionCreate) aicore.UsageScope {
+	scope := aicore.UsageScope{Command: i.ApplicationCommandData().Name, GuildID: i.GuildID}
+	if i.Member != nil && i.Member.User != nil {
+		scope.UserID = i.Member.User.ID
+	} else if i.User != nil {
+		scope.UserID = i.User.ID
+	}
+	return scope
+}
+
+// loadProposalCall returns the decoded on-chain call, preferring the indexed
+// copy on the referendum over the cached one
+func loadProposalCall(ref *sharedgov.Ref, entry *cache.Entry) *polkadot.ProposalCall {
+	var raw []byte
+	if ref != nil && ref.DecodedCall != nil {
+		raw = []byte(*ref.DecodedCall)
+	} else if entry != nil && len(entry.ProposalCall) > 0 {
+		raw = entry.ProposalCall
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+
+	proposalCall, err := polkadot.ParseProposalCall(raw)
+	if err != nil {
+		log.Printf("reports: %v", err)
+		return nil
+	}
+	return proposalCall
+}
+
+// loadLifecycle returns the referendum's recorded lifecycle transitions in chain order
+func (h *Handler) loadLifecycle(refDBID uint64) []sharedgov.RefEvent {
+	var events []sharedgov.RefEvent
+	if err := h.DB.Where("ref_db_id = ?", refDBID).Order("block_number ASC, id ASC").Find(&events).Error; err != nil {
+		log.Printf("reports: failed to load lifecycle events: %v", err)
+		return nil
+	}
+	return events
+}
+
+// loadSpends returns the treasury spends the referendum created
+func (h *Handler) loadSpends(refDBID uint64) []sharedgov.RefSpend {
+	var spends []sharedgov.RefSpend
+	if err := h.DB.Where("ref_db_id = ?", refDBID).Order("kind, spend_index").Find(&spends).Error; err != nil {
+		log.Printf("reports: failed to load treasury spends: %v", err)
+		return nil
+	}
+	return spends
+}
+
+// loadAccounts returns the referendum's submitter, depositors and
+// beneficiaries with their cached on-chain identities
+func (h *Handler) loadAccounts(ref *sharedgov.Ref, proposalCall *polkadot.ProposalCall, spends []sharedgov.RefSpend) ([]sharedgov.RefAccount, sharedgov.IdentityDirectory) {
+	var beneficiaries []string
+	if proposalCall != nil {
+		beneficiaries = append(beneficiaries, proposalCall.Beneficiaries...)
+	}
+	beneficiaries = append(beneficiaries, sharedgov.SpendBeneficiaries(spends)...)
+
+	accounts := sharedgov.RefAccounts(ref, beneficiaries)
+	if ref == nil || len(accounts) == 0 {
+		return accounts, sharedgov.IdentityDirectory{}
+	}
+
+	identities, err := sharedgov.LoadIdentities(h.DB, ref.NetworkID, sharedgov.Addresses(accounts))
+	if err != nil {
+		log.Printf("reports: failed to load identities: %v", err)
+	}
+	return accounts, identities
+}
+
+// loadPassProjection returns the indexer's latest curve evaluation for an ongoing referendum
+func loadPassProjection(ref *sharedgov.Ref) *polkadot.PassProjection {
+	if ref == nil || ref.PassProjection == nil || ref.Status == nil || *ref.Status != "Ongoing" {
+		return nil
+	}
+	projection, err := polkadot.ParsePassProjection([]byte(*ref.PassProjection))
+	if err != nil {
+		log.Printf("reports: %v", err)
+		return nil
+	}
+	return projection
+}
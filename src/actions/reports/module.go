@@ -7,6 +7,7 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/stake-plus/govcomms/src/actions/core"
+	aicore "github.com/stake-plus/govcomms/src/api/ai/core"
 	sharedconfig "github.com/stake-plus/govcomms/src/data/config"
 	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
 	"gorm.io/gorm"
@@ -26,12 +27,12 @@ type Module struct {
 }
 
 // GenerateReport triggers PDF report generation for a referendum
-func (m *Module) GenerateReport(s *discordgo.Session, channelID string, network string, refID uint32, refDBID uint64) {
+func (m *Module) GenerateReport(s *discordgo.Session, channelID string, network string, refID uint32, refDBID uint64, invoker aicore.UsageScope) {
 	if m.handler == nil {
 		log.Printf("reports: handler not initialized")
 		return
 	}
-	m.handler.GenerateReport(s, channelID, network, refID, refDBID)
+	m.handler.GenerateReport(s, channelID, network, refID, refDBID, invoker)
 }
 
 // HandleReportSlash handles the /report slash command
@@ -271,7 +271,9 @@ func (a *Analyzer) getProposalInstruction(network string, refID uint32, hasMCP b
 		return fmt.Sprintf(`Use the fetch_referendum_data tool to retrieve metadata and full proposal content before extracting team members.
 Metadata example: {"network":"%s","refId":%d,"resource":"metadata"}
 Content example: {"network":"%s","refId":%d,"resource":"content"}
-Request attachments when metadata lists files. Avoid repeating tool calls after you have the information you need.`, slug, refID, slug, refID)
+Accounts example: {"network":"%s","refId":%d,"resource":"accounts"}
+The accounts resource lists the submitter and beneficiaries with their on-chain identities. When an identity's display name matches a team member, include its twitter, github and web fields among that member's profiles.
+Request attachments when metadata lists files. Avoid repeating tool calls after you have the information you need.`, slug, refID, slug, refID, slug, refID)
 	}
 	return fmt.Sprintf("Network: %s, Referendum ID: %d", network, refID)
 }
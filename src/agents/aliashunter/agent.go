@@ -8,6 +8,7 @@ import (
 	"time"
 
 	agentcore "github.com/stake-plus/govcomms/src/agents/core"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
 )
 
 // Config controls scoring thresholds for alias suggestions.
@@ -55,6 +56,11 @@ func (a *Agent) Capabilities() []agentcore.Capability {
 			Description: "Compare display names, org names, and email stems",
 			Signals:     []string{"display_name_match", "email_domain"},
 		},
+		{
+			Name:        "onchain_identity",
+			Description: "Expand chain addresses into the handles set in their on-chain identities",
+			Signals:     []string{"identity_fields", "registrar_judgement", "parent_identity"},
+		},
 	}
 }
 
@@ -62,6 +68,7 @@ func (a *Agent) Capabilities() []agentcore.Capability {
 func (a *Agent) Execute(ctx context.Context, mission agentcore.Mission) (*agentcore.Result, error) {
 	start := time.Now().UTC()
 	seeds := collectIdentities(mission)
+	seeds = dedupeIdentities(append(seeds, a.onChainIdentities(ctx, seeds)...))
 	if len(seeds) == 0 {
 		return &agentcore.Result{
 			MissionID:   mission.ID,
@@ -176,6 +183,12 @@ func collectIdentities(mission agentcore.Mission) []identity {
 	if accounts, ok := mission.Inputs["accounts"]; ok {
 		out = append(out, identitiesFromValue(accounts, "accounts")...)
 	}
+	if addresses, ok := mission.Inputs["addresses"]; ok {
+		for _, id := range identitiesFromValue(addresses, "addresses") {
+			id.Kind = "address"
+			out = append(out, id)
+		}
+	}
 	for _, artifact := range mission.Artifacts {
 		if artifact.Type != agentcore.ArtifactAliasMapping {
 			continue
@@ -200,6 +213,78 @@ func collectIdentities(mission agentcore.Mission) []identity {
 	return dedupeIdentities(out)
 }
 
+// onChainIdentityFields are the identity fields that name an account on
+// another platform
+var onChainIdentityFields = []string{"twitter", "github", "matrix", "discord", "email", "web"}
+
+// onChainIdentities expands seeds that are chain addresses into the handles
+// set in their on-chain identities, as cached by the referendum indexer
+func (a *Agent) onChainIdentities(ctx context.Context, seeds []identity) []identity {
+	if a.deps.DB == nil || len(seeds) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(seeds))
+	for _, seed := range seeds {
+		values = append(values, seed.Value)
+	}
+
+	var rows []sharedgov.AccountIdentity
+	if err := a.deps.DB.WithContext(ctx).
+		Where("address IN ? AND has_identity = ?", values, true).
+		Find(&rows).Error; err != nil {
+		if a.deps.Logger != nil {
+			a.deps.Logger.Printf("alias_hunter: on-chain identity lookup failed: %v", err)
+		}
+		return nil
+	}
+
+	out := []identity{}
+	for i := range rows {
+		row := &rows[i]
+		name := row.Name()
+		source := "onchain_identity:" + row.Address
+		fields := row.FieldMap()
+		metadata := map[string]string{
+			"address":   row.Address,
+			"judgement": row.Judgement,
+			"verified":  fmt.Sprint(row.Verified),
+		}
+		if email := fields["email"]; email != "" {
+			metadata["email"] = email
+		}
+		if row.ParentAddress != nil {
+			metadata["parent"] = *row.ParentAddress
+		}
+
+		if name != "" {
+			out = append(out, identity{
+				Value:       name,
+				DisplayName: name,
+				Platform:    "onchain",
+				Kind:        "display_name",
+				Source:      source,
+				Metadata:    metadata,
+			})
+		}
+		for _, field := range onChainIdentityFields {
+			value := fields[field]
+			if value == "" {
+				continue
+			}
+			out = append(out, identity{
+				Value:       value,
+				DisplayName: name,
+				Platform:    field,
+				Kind:        "onchain_" + field,
+				Source:      source,
+				Metadata:    metadata,
+			})
+		}
+	}
+	return out
+}
+
 func identityFromSubject(source string, subject agentcore.Subject) identity {
 	return identity{
 		Value:       subject.Identifier,
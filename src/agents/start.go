@@ -87,5 +87,5 @@ func buildAIClient(cfg sharedconfig.AIConfig, logger *log.Logger) aicore.Client
 	if err != nil && logger != nil {
 		logger.Printf("agents: ai client unavailable: %v", err)
 	}
-	return client
+	return aicore.FeatureClient(client, aicore.FeatureAgents)
 }
@@ -0,0 +1,456 @@
+// Package anthropic is the client shared by the Claude providers, which
+// speak Anthropic's Messages API. Each provider only supplies its Model.
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/stake-plus/govcomms/src/api/ai/core"
+	"github.com/stake-plus/govcomms/src/api/webclient"
+)
+
+const (
+	anthropicEndpoint    = "https://api.anthropic.com/v1/messages"
+	maxTokensLimit       = 8192
+	topP                 = 0.8
+	topK                 = 40
+	requestTimeout       = 240 * time.Second
+	retryAttempts        = 3
+	retryBackoff         = 2 * time.Second
+	maxToolIterations    = 20
+	maxAttachmentFetches = 6
+	minTopP              = 0.01
+	maxTopP              = 1.0
+	minTopK              = 1
+	maxTopK              = 500
+)
+
+// Model describes one Claude provider.
+type Model struct {
+	// Provider is the provider key reported with token usage.
+	Provider string
+	// Name prefixes errors and names the agent in logs, e.g. "sonnet-4.5".
+	Name        string
+	Model       string
+	Temperature float64
+	// ExtraPrefix scopes the Extra keys "<prefix>.top_p" and "<prefix>.top_k".
+	ExtraPrefix string
+	// AnswerInstruction closes the AnswerQuestion prompt.
+	AnswerInstruction string
+	// WebSearchHint is put before the input when web search is wanted.
+	WebSearchHint string
+	// PromptTokenBudget caps the agent's prompt, 0 for no cap.
+	PromptTokenBudget int
+}
+
+type client struct {
+	model      Model
+	apiKey     string
+	httpClient *http.Client
+	defaults   core.Options
+	topP       float64
+	topK       int
+	useTopP    bool
+}
+
+// New returns a client for model.
+func New(cfg core.FactoryConfig, model Model) (core.Client, error) {
+	if cfg.ClaudeKey == "" {
+		return nil, fmt.Errorf("%s: Claude API key not configured", model.Name)
+	}
+
+	topPKey := model.ExtraPrefix + ".top_p"
+	topPVal := topP
+	useTopP := false
+	if _, ok := cfg.Extra[topPKey]; ok {
+		topPVal = core.ClampFloat(core.ExtraFloat(cfg.Extra, topPKey, topP), minTopP, maxTopP)
+		useTopP = true
+	}
+	topK := core.ExtraInt(cfg.Extra, model.ExtraPrefix+".top_k", topK)
+	if topK < minTopK {
+		topK = minTopK
+	} else if topK > maxTopK {
+		topK = maxTopK
+	}
+
+	return &client{
+		model:      model,
+		apiKey:     cfg.ClaudeKey,
+		httpClient: webclient.NewDefault(requestTimeout),
+		defaults: core.Options{
+			Model:               valueOrDefault(cfg.Model, model.Model),
+			Temperature:         orFloat(cfg.Temperature, model.Temperature),
+			MaxCompletionTokens: orInt(cfg.MaxCompletionTokens, maxTokensLimit),
+			SystemPrompt:        cfg.SystemPrompt,
+			EnableWebSearch:     core.ExtraBool(cfg.Extra, "enable_web_search", false),
+		},
+		topP:    topPVal,
+		topK:    topK,
+		useTopP: useTopP,
+	}, nil
+}
+
+func (c *client) AnswerQuestion(ctx context.Context, content string, question string, opts core.Options) (string, error) {
+	merged := c.merge(opts)
+	userPrompt := fmt.Sprintf("Proposal Content:\n%s\n\nQuestion: %s\n\n%s", content, question, c.model.AnswerInstruction)
+	reqBody := c.requestBody(merged, merged.SystemPrompt, []anthropicMessage{
+		{Role: "user", Content: []anthropicContentBlock{textBlock(userPrompt)}},
+	})
+	blocks, usage, err := c.createMessage(ctx, reqBody, nil)
+	if err != nil {
+		return "", err
+	}
+	core.ReportUsage(ctx, usage)
+
+	text := anthropicTextFromBlocks(blocks)
+	if text == "" {
+		return "", fmt.Errorf("%s: empty response", c.model.Name)
+	}
+	return text, nil
+}
+
+func (c *client) Respond(ctx context.Context, input string, tools []core.Tool, opts core.Options) (string, error) {
+	merged := c.merge(opts)
+	return c.respond(ctx, c.withWebSearchHint(input, tools, merged), tools, merged, nil)
+}
+
+// RespondStream implements core.StreamingClient.
+func (c *client) RespondStream(ctx context.Context, input string, tools []core.Tool, opts core.Options, onEvent core.StreamHandler) (string, error) {
+	merged := c.merge(opts)
+	return c.respond(ctx, c.withWebSearchHint(input, tools, merged), tools, merged, onEvent)
+}
+
+func (c *client) withWebSearchHint(input string, tools []core.Tool, opts core.Options) string {
+	if c.model.WebSearchHint == "" || !core.WantsWebSearch(opts, tools) {
+		return input
+	}
+	return c.model.WebSearchHint + "\n\n" + input
+}
+
+// requestBody builds a Messages API request with the client's sampling
+// parameters.
+func (c *client) requestBody(opts core.Options, system string, messages []anthropicMessage) map[string]any {
+	maxTokens := opts.MaxCompletionTokens
+	if maxTokens <= 0 {
+		maxTokens = maxTokensLimit
+	}
+	reqBody := map[string]any{
+		"model":      opts.Model,
+		"messages":   messages,
+		"max_tokens": maxTokens,
+	}
+	c.applySampling(reqBody, opts.Temperature)
+	if strings.TrimSpace(system) != "" {
+		reqBody["system"] = system
+	}
+	return reqBody
+}
+
+// respond runs the shared agent loop over Anthropic's Messages API.
+func (c *client) respond(ctx context.Context, input string, tools []core.Tool, opts core.Options, onEvent core.StreamHandler) (string, error) {
+	cfg := core.AgentConfig{
+		Name:                 c.model.Name,
+		HTTPClient:           c.httpClient,
+		MaxIterations:        maxToolIterations,
+		MaxAttachmentFetches: maxAttachmentFetches,
+		PromptTokenBudget:    c.model.PromptTokenBudget,
+	}
+	return core.RunAgent(ctx, cfg, input, tools, opts, onEvent, func(ctx context.Context, req core.TurnRequest) (core.Turn, error) {
+		reqBody := c.requestBody(opts, req.SystemPrompt, buildAnthropicMessages(req.Turns))
+		if len(req.Functions) > 0 {
+			reqBody["tools"] = buildAnthropicToolsPayload(req.Functions)
+			reqBody["tool_choice"] = buildAnthropicToolChoice(req.RequiredFunction)
+		}
+
+		blocks, usage, err := c.createMessage(ctx, reqBody, req.OnEvent)
+		if err != nil {
+			return core.Turn{}, err
+		}
+		turn := anthropicTurn(blocks)
+		turn.Usage = usage
+		return turn, nil
+	})
+}
+
+func (c *client) newRequest(ctx context.Context, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, anthropicEndpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	return req, nil
+}
+
+// createMessage sends one Messages API request and returns the reply's
+// content blocks and token usage, streaming them to onEvent when set.
+func (c *client) createMessage(ctx context.Context, reqBody map[string]any, onEvent core.StreamHandler) ([]anthropicContentBlock, core.Usage, error) {
+	if onEvent != nil {
+		return c.streamMessage(ctx, reqBody, onEvent)
+	}
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	_, payload, err := webclient.DoWithRetry(ctx, retryAttempts, retryBackoff, func() (int, []byte, error) {
+		req, err := c.newRequest(ctx, bodyBytes)
+		if err != nil {
+			return 0, nil, err
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return 0, nil, err
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return resp.StatusCode, nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, b, fmt.Errorf("%s: status %d: %s", c.model.Name, resp.StatusCode, truncatePayload(b, 512))
+		}
+		return resp.StatusCode, b, nil
+	})
+	if err != nil {
+		return nil, core.Usage{}, err
+	}
+
+	var resp anthropicMessageResponse
+	if err := json.Unmarshal(payload, &resp); err != nil {
+		return nil, core.Usage{}, fmt.Errorf("%s: parse error: %w", c.model.Name, err)
+	}
+	if len(resp.Content) == 0 {
+		return nil, core.Usage{}, fmt.Errorf("%s: empty response", c.model.Name)
+	}
+	model, _ := reqBody["model"].(string)
+	return resp.Content, resp.Usage.toCore(c.model.Provider, resp.Model, model), nil
+}
+
+func (c *client) merge(opts core.Options) core.Options {
+	out := c.defaults
+	if strings.TrimSpace(opts.Model) != "" {
+		out.Model = opts.Model
+	}
+	if opts.Temperature != 0 {
+		out.Temperature = opts.Temperature
+	}
+	if opts.MaxCompletionTokens != 0 {
+		out.MaxCompletionTokens = opts.MaxCompletionTokens
+	}
+	if strings.TrimSpace(opts.SystemPrompt) != "" {
+		out.SystemPrompt = opts.SystemPrompt
+	}
+	if opts.EnableWebSearch {
+		out.EnableWebSearch = true
+	}
+	if opts.EnableDeepSearch {
+		out.EnableDeepSearch = true
+	}
+	return out
+}
+
+func valueOrDefault(val, def string) string {
+	if strings.TrimSpace(val) != "" {
+		return val
+	}
+	return def
+}
+
+func orInt(v, def int) int {
+	if v > 0 {
+		return v
+	}
+	return def
+}
+
+func orFloat(v, def float64) float64 {
+	if v != 0 {
+		return v
+	}
+	return def
+}
+
+func buildAnthropicToolsPayload(tools []core.Tool) []map[string]any {
+	out := make([]map[string]any, 0, len(tools))
+	for _, t := range tools {
+		definition := map[string]any{
+			"name":        t.Name,
+			"description": t.Description,
+		}
+		if t.Parameters != nil {
+			definition["input_schema"] = t.Parameters
+		}
+		out = append(out, definition)
+	}
+	return out
+}
+
+func buildAnthropicToolChoice(forced string) map[string]any {
+	if strings.TrimSpace(forced) == "" {
+		return map[string]any{"type": "auto"}
+	}
+	return map[string]any{
+		"type": "tool",
+		"name": forced,
+	}
+}
+
+// buildAnthropicMessages converts agent turns into Messages API messages.
+// Tool results and reminders between two assistant replies share one user
+// message, with the tool results first as the API requires.
+func buildAnthropicMessages(turns []core.Turn) []anthropicMessage {
+	messages := make([]anthropicMessage, 0, len(turns))
+	for _, turn := range turns {
+		var block anthropicContentBlock
+		switch turn.Role {
+		case core.RoleAssistant:
+			var blocks []anthropicContentBlock
+			if strings.TrimSpace(turn.Text) != "" {
+				blocks = append(blocks, textBlock(turn.Text))
+			}
+			for _, call := range turn.ToolCalls {
+				input := map[string]any{}
+				_ = json.Unmarshal([]byte(call.Arguments), &input)
+				blocks = append(blocks, anthropicContentBlock{
+					Type:  "tool_use",
+					ID:    call.ID,
+					Name:  call.Name,
+					Input: input,
+				})
+			}
+			messages = append(messages, anthropicMessage{Role: "assistant", Content: blocks})
+			continue
+		case core.RoleTool:
+			block = anthropicContentBlock{
+				Type:      "tool_result",
+				ToolUseID: turn.ToolCallID,
+				Content:   turn.Text,
+			}
+		default:
+			block = textBlock(turn.Text)
+		}
+		if last := len(messages) - 1; last >= 0 && messages[last].Role == "user" {
+			messages[last].Content = append(messages[last].Content, block)
+			continue
+		}
+		messages = append(messages, anthropicMessage{Role: "user", Content: []anthropicContentBlock{block}})
+	}
+	return messages
+}
+
+func anthropicTextFromBlocks(blocks []anthropicContentBlock) string {
+	var builder strings.Builder
+	for _, block := range blocks {
+		if block.Type != "text" {
+			continue
+		}
+		if strings.TrimSpace(block.Text) == "" {
+			continue
+		}
+		if builder.Len() > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(block.Text)
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+func (c *client) applySampling(target map[string]any, temperature float64) {
+	target["top_k"] = c.topK
+	if c.useTopP {
+		target["top_p"] = c.topP
+		return
+	}
+	if temperature > 0 {
+		target["temperature"] = temperature
+	}
+}
+
+// anthropicTurn converts a reply's content blocks into an agent turn.
+func anthropicTurn(blocks []anthropicContentBlock) core.Turn {
+	turn := core.Turn{Role: core.RoleAssistant, Text: anthropicTextFromBlocks(blocks)}
+	for _, block := range blocks {
+		if block.Type != "tool_use" {
+			continue
+		}
+		args := "{}"
+		if block.Input != nil {
+			if data, err := json.Marshal(block.Input); err == nil {
+				args = string(data)
+			}
+		}
+		turn.ToolCalls = append(turn.ToolCalls, core.ToolCall{
+			ID:        block.ID,
+			Name:      block.Name,
+			Arguments: args,
+		})
+	}
+	return turn
+}
+
+func textBlock(msg string) anthropicContentBlock {
+	return anthropicContentBlock{
+		Type: "text",
+		Text: msg,
+	}
+}
+
+type anthropicMessage struct {
+	Role    string                  `json:"role"`
+	Content []anthropicContentBlock `json:"content"`
+}
+
+type anthropicContentBlock struct {
+	Type      string         `json:"type"`
+	Text      string         `json:"text,omitempty"`
+	ID        string         `json:"id,omitempty"`
+	Name      string         `json:"name,omitempty"`
+	Input     map[string]any `json:"input,omitempty"`
+	ToolUseID string         `json:"tool_use_id,omitempty"`
+	Content   any            `json:"content,omitempty"`
+	IsError   bool           `json:"is_error,omitempty"`
+}
+
+type anthropicMessageResponse struct {
+	Model   string                  `json:"model"`
+	Usage   *anthropicUsage         `json:"usage"`
+	Content []anthropicContentBlock `json:"content"`
+}
+
+type anthropicUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// toCore converts the reported usage. Anthropic counts cache reads and
+// writes apart from input_tokens, so they are added back in.
+func (u *anthropicUsage) toCore(provider, model, requested string) core.Usage {
+	if u == nil {
+		return core.Usage{}
+	}
+	if model == "" {
+		model = requested
+	}
+	return core.Usage{
+		Provider:     provider,
+		Model:        model,
+		InputTokens:  u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens,
+		OutputTokens: u.OutputTokens,
+		CachedTokens: u.CacheReadInputTokens,
+	}
+}
+
+func truncatePayload(b []byte, limit int) string {
+	if len(b) <= limit {
+		return string(b)
+	}
+	return string(b[:limit]) + "... (truncated)"
+}
@@ -0,0 +1,123 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/stake-plus/govcomms/src/api/ai/core"
+	"github.com/stake-plus/govcomms/src/api/webclient"
+)
+
+type anthropicStreamEvent struct {
+	Type         string                    `json:"type"`
+	Index        int                       `json:"index"`
+	ContentBlock *anthropicContentBlock    `json:"content_block"`
+	Message      *anthropicMessageResponse `json:"message"`
+	Usage        *anthropicUsage           `json:"usage"`
+	Delta        struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+	} `json:"delta"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// streamMessage sends one Messages API request with stream enabled,
+// reporting text deltas as they arrive and rebuilding the content blocks.
+// Input tokens arrive with message_start and the output count with
+// message_delta.
+func (c *client) streamMessage(ctx context.Context, reqBody map[string]any, onEvent core.StreamHandler) ([]anthropicContentBlock, core.Usage, error) {
+	reqBody["stream"] = true
+	bodyBytes, _ := json.Marshal(reqBody)
+	resp, err := webclient.OpenStream(ctx, c.httpClient, retryAttempts, retryBackoff, func() (*http.Request, error) {
+		req, err := c.newRequest(ctx, bodyBytes)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "text/event-stream")
+		return req, nil
+	})
+	if err != nil {
+		return nil, core.Usage{}, fmt.Errorf("%s: stream error: %w", c.model.Name, err)
+	}
+	defer resp.Body.Close()
+
+	var blocks []anthropicContentBlock
+	var inputs []strings.Builder
+	var model string
+	var usage anthropicUsage
+	err = webclient.ReadSSE(resp.Body, func(_, data string) error {
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("invalid stream event: %w", err)
+		}
+		switch event.Type {
+		case "message_start":
+			if event.Message != nil {
+				model = event.Message.Model
+				if event.Message.Usage != nil {
+					usage = *event.Message.Usage
+				}
+			}
+		case "message_delta":
+			if event.Usage != nil {
+				usage.OutputTokens = event.Usage.OutputTokens
+			}
+		case "content_block_start":
+			if event.ContentBlock == nil || event.Index < 0 {
+				return nil
+			}
+			for len(blocks) <= event.Index {
+				blocks = append(blocks, anthropicContentBlock{})
+				inputs = append(inputs, strings.Builder{})
+			}
+			blocks[event.Index] = *event.ContentBlock
+			if event.ContentBlock.Text != "" {
+				return onEvent(core.StreamEvent{Type: core.StreamText, Text: event.ContentBlock.Text})
+			}
+		case "content_block_delta":
+			if event.Index < 0 || event.Index >= len(blocks) {
+				return nil
+			}
+			switch event.Delta.Type {
+			case "text_delta":
+				blocks[event.Index].Text += event.Delta.Text
+				return onEvent(core.StreamEvent{Type: core.StreamText, Text: event.Delta.Text})
+			case "input_json_delta":
+				inputs[event.Index].WriteString(event.Delta.PartialJSON)
+			}
+		case "content_block_stop":
+			if event.Index < 0 || event.Index >= len(blocks) {
+				return nil
+			}
+			raw := strings.TrimSpace(inputs[event.Index].String())
+			if blocks[event.Index].Type == "tool_use" && raw != "" {
+				var input map[string]any
+				if err := json.Unmarshal([]byte(raw), &input); err != nil {
+					return fmt.Errorf("invalid tool input: %w", err)
+				}
+				blocks[event.Index].Input = input
+			}
+		case "error":
+			if event.Error != nil {
+				return fmt.Errorf("%s: %s", event.Error.Type, event.Error.Message)
+			}
+			return fmt.Errorf("stream error")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, core.Usage{}, fmt.Errorf("%s: stream error: %w", c.model.Name, err)
+	}
+	if len(blocks) == 0 {
+		return nil, core.Usage{}, fmt.Errorf("%s: empty response", c.model.Name)
+	}
+	requested, _ := reqBody["model"].(string)
+	return blocks, usage.toCore(c.model.Provider, model, requested), nil
+}
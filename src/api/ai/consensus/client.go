@@ -105,6 +105,9 @@ func (c *client) Respond(ctx context.Context, input string, tools []core.Tool, o
 	if len(c.researchers) == 0 {
 		return "", errors.New("consensus: no researchers available")
 	}
+	// Deliberation costs several calls per answer; bill it separately from
+	// the feature that asked
+	ctx = core.WithUsageFeature(ctx, core.FeatureConsensus)
 
 	analysis := c.runResearch(ctx, input, tools, opts)
 	if len(analysis) == 0 {
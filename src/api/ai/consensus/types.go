@@ -165,6 +165,8 @@ func buildParticipants(cfg core.FactoryConfig, specs []participantSpec) ([]parti
 			subCfg.Model = spec.Model
 		}
 		subCfg.Extra = cloneExtras(extra)
+		// Each participant is its own voice; don't let it fall back to another
+		subCfg.Fallbacks = nil
 		client, err := core.NewClient(subCfg)
 		if err != nil {
 			return nil, fmt.Errorf("consensus: init participant %s: %w", spec.Provider, err)
@@ -234,6 +236,9 @@ func defaultSpecsFromKeys(cfg core.FactoryConfig) []participantSpec {
 	if cfg.ClaudeKey != "" {
 		add("sonnet45")
 	}
+	if cfg.CompatBaseURL != "" {
+		add("openaicompat")
+	}
 	return specs
 }
 
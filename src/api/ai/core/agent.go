@@ -0,0 +1,495 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxIterations bounds the model requests of one agent run.
+const DefaultMaxIterations = 20
+
+// Roles of a Turn.
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// ToolCall is a tool invocation requested by the model.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments string // JSON object
+}
+
+// Turn is one message of a provider-neutral conversation.
+type Turn struct {
+	Role       string
+	Text       string
+	ToolCalls  []ToolCall // assistant turns that call tools
+	ToolCallID string     // tool turns: the call answered
+	ToolName   string     // tool turns: the tool that ran
+	Usage      Usage      // assistant turns: tokens the request used
+}
+
+// TurnRequest is one model request made by the agent loop.
+type TurnRequest struct {
+	SystemPrompt string
+	Turns        []Turn
+	// Functions are the tools the model may call this turn. It is empty once
+	// the loop wants a final answer.
+	Functions []Tool
+	// RequiredFunction names a function the model must call rather than
+	// answer; empty lets the model choose.
+	RequiredFunction string
+	// WebSearch is set while tools are offered and the caller asked for web
+	// search, for providers with a native search tool.
+	WebSearch bool
+	// OnEvent receives the reply's text as it streams; nil for a blocking
+	// request.
+	OnEvent StreamHandler
+}
+
+// TurnFunc sends one request in a provider's wire format and returns the
+// model's reply as an assistant turn.
+type TurnFunc func(ctx context.Context, req TurnRequest) (Turn, error)
+
+// AgentConfig holds a provider's limits for the agent loop.
+type AgentConfig struct {
+	// Name prefixes log lines and errors.
+	Name string
+	// HTTPClient carries tool requests such as MCP calls.
+	HTTPClient *http.Client
+	// MaxIterations bounds the model requests; 0 uses DefaultMaxIterations.
+	MaxIterations int
+	// MaxAttachmentFetches bounds attachment downloads; 0 means no limit.
+	MaxAttachmentFetches int
+	// PromptTokenBudget stops tool use once the estimated prompt reaches it;
+	// 0 means no budget.
+	PromptTokenBudget int
+}
+
+// WantsWebSearch reports whether the caller asked for web search.
+func WantsWebSearch(opts Options, tools []Tool) bool {
+	if opts.EnableWebSearch {
+		return true
+	}
+	for _, tool := range tools {
+		if strings.EqualFold(tool.Type, "web_search") {
+			return true
+		}
+	}
+	return false
+}
+
+// RunAgent answers input, running the tools the model calls until it gives a
+// final answer. The provider only translates each TurnRequest through turn;
+// tool dispatch, de-duplication, attachment budgets and reminders live here.
+func RunAgent(ctx context.Context, cfg AgentConfig, input string, tools []Tool, opts Options, onEvent StreamHandler, turn TurnFunc) (string, error) {
+	maxIterations := cfg.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = DefaultMaxIterations
+	}
+	functions, toolMap, required := agentFunctions(tools)
+	webSearch := WantsWebSearch(opts, tools)
+
+	run := &agentRun{
+		cfg:                  cfg,
+		systemPrompt:         opts.SystemPrompt,
+		tools:                toolMap,
+		turns:                []Turn{{Role: RoleUser, Text: input}},
+		cache:                map[string]string{},
+		attachmentsRetrieved: map[string]bool{},
+	}
+
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		req := TurnRequest{
+			SystemPrompt: opts.SystemPrompt,
+			Turns:        run.turns,
+			OnEvent:      onEvent,
+		}
+		if !run.toolsDisabled {
+			req.Functions = functions
+			req.WebSearch = webSearch
+			if !run.stalled && !run.contextComplete() {
+				req.RequiredFunction = required
+			}
+		}
+
+		reply, err := turn(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		ReportUsage(ctx, reply.Usage)
+		reply.Role = RoleAssistant
+		for idx := range reply.ToolCalls {
+			if reply.ToolCalls[idx].ID == "" {
+				run.callSeq++
+				reply.ToolCalls[idx].ID = fmt.Sprintf("call_%d", run.callSeq)
+			}
+		}
+		run.turns = append(run.turns, reply)
+
+		if len(reply.ToolCalls) == 0 {
+			if strings.TrimSpace(reply.Text) == "" {
+				continue
+			}
+			return reply.Text, nil
+		}
+
+		if onEvent != nil {
+			for _, call := range reply.ToolCalls {
+				if err := onEvent(StreamEvent{Type: StreamToolCall, Tool: call.Name, Arguments: call.Arguments}); err != nil {
+					return "", err
+				}
+			}
+		}
+		if err := run.handleCalls(ctx, reply.ToolCalls); err != nil {
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("%s: tool loop exceeded", cfg.Name)
+}
+
+// agentFunctions picks the tools a registered handler can run, naming
+// unnamed ones, and returns the last as the function to require while
+// referendum context is still missing.
+func agentFunctions(tools []Tool) ([]Tool, map[string]Tool, string) {
+	var functions []Tool
+	toolMap := map[string]Tool{}
+	var required string
+	for idx, t := range tools {
+		if _, ok := toolHandler(t.Type); !ok {
+			continue
+		}
+		if strings.TrimSpace(t.Name) == "" {
+			t.Name = fmt.Sprintf("%s_%d", strings.ToLower(strings.TrimSpace(t.Type)), idx+1)
+		}
+		functions = append(functions, t)
+		toolMap[t.Name] = t
+		required = t.Name
+	}
+	return functions, toolMap, required
+}
+
+type agentRun struct {
+	cfg          AgentConfig
+	systemPrompt string
+	tools        map[string]Tool
+	turns        []Turn
+	cache        map[string]string
+	callSeq      int
+
+	metadataFetched bool
+	contentFetched  bool
+	historyFetched  bool
+
+	attachmentNames         []string
+	attachmentsRetrieved    map[string]bool
+	attachmentFetches       int
+	attachmentLimitHit      bool
+	attachmentLimitNotified bool
+	missingFileCount        int
+	budgetExceeded          bool
+
+	stallCount    int
+	stalled       bool
+	toolsDisabled bool
+
+	finalReminderSent   bool
+	base64ReminderSent  bool
+	historyReminderSent bool
+	metadataHintSent    bool
+	contentHintSent     bool
+}
+
+func (r *agentRun) contextComplete() bool {
+	return r.metadataFetched && r.contentFetched && r.historyFetched && !r.hasPendingAttachments()
+}
+
+func (r *agentRun) hasPendingAttachments() bool {
+	return r.nextPendingAttachment() != ""
+}
+
+func (r *agentRun) nextPendingAttachment() string {
+	for _, name := range r.attachmentNames {
+		if !r.attachmentsRetrieved[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+func (r *agentRun) markAllAttachmentsRetrieved() {
+	for _, name := range r.attachmentNames {
+		r.attachmentsRetrieved[name] = true
+	}
+}
+
+func (r *agentRun) remind(text string) {
+	r.turns = append(r.turns, Turn{Role: RoleUser, Text: text})
+}
+
+// handleCalls answers every call of one model reply, then adds whatever
+// reminder steers the model towards a complete, final answer.
+func (r *agentRun) handleCalls(ctx context.Context, calls []ToolCall) error {
+	progressed := false
+	announcedAttachments := false
+	missingFile := false
+	budgetJustExceeded := false
+
+	for _, call := range calls {
+		tool, ok := r.tools[call.Name]
+		if !ok {
+			return fmt.Errorf("%s: unknown tool %s", r.cfg.Name, call.Name)
+		}
+
+		args, argErr := decodeToolArguments(call.Arguments)
+		referenda := argErr == nil && strings.EqualFold(tool.Type, ToolMCPReferenda)
+		resource, file := "", ""
+		if referenda {
+			resource = normalizeResource(normalizedArgValue(args["resource"]))
+			file = normalizedArgValue(args["file"])
+		}
+		key := toolCacheKey(call)
+
+		var output string
+		skipped, fromCache := false, false
+		switch cached, hit := r.cache[key]; {
+		case argErr != nil:
+			log.Printf("%s: tool %s arg parse error: %v", r.cfg.Name, call.Name, argErr)
+			output = toolErrorResult("invalid arguments: " + argErr.Error())
+			progressed = true
+		case hit:
+			output = cached
+			fromCache = true
+		case r.budgetExceeded && resource == "attachments":
+			output = skippedAttachmentResult(file, "token budget")
+			skipped = true
+			progressed = true
+		case resource == "attachments" && r.cfg.MaxAttachmentFetches > 0 && r.attachmentFetches >= r.cfg.MaxAttachmentFetches:
+			output = skippedAttachmentResult(file, "attachment limit")
+			skipped = true
+			r.attachmentLimitHit = true
+			progressed = true
+		default:
+			output = r.execute(ctx, tool, args)
+			r.cache[key] = output
+			progressed = true
+		}
+
+		r.turns = append(r.turns, Turn{
+			Role:       RoleTool,
+			Text:       output,
+			ToolCallID: call.ID,
+			ToolName:   call.Name,
+		})
+
+		switch resource {
+		case "content":
+			r.contentFetched = true
+		case "metadata":
+			r.metadataFetched = true
+			if names := metadataAttachmentNames(output); len(names) > 0 {
+				if len(r.attachmentNames) == 0 {
+					r.attachmentNames = names
+				}
+				if r.hasPendingAttachments() && !r.budgetExceeded {
+					announcedAttachments = true
+				}
+			}
+		case "attachments":
+			switch {
+			case skipped && r.attachmentLimitHit && len(r.attachmentNames) > 0:
+				r.markAllAttachmentsRetrieved()
+			case skipped || fromCache:
+				if file != "" {
+					r.attachmentsRetrieved[file] = true
+				}
+			case file == "":
+				missingFile = true
+			default:
+				r.attachmentFetches++
+				if r.cfg.MaxAttachmentFetches > 0 && r.attachmentFetches >= r.cfg.MaxAttachmentFetches {
+					r.attachmentLimitHit = true
+				}
+				r.attachmentsRetrieved[file] = true
+			}
+		case "history":
+			r.historyFetched = true
+		}
+
+		if r.cfg.PromptTokenBudget > 0 && !r.budgetExceeded && estimatePromptTokens(r.systemPrompt, r.turns) >= r.cfg.PromptTokenBudget {
+			r.budgetExceeded = true
+			budgetJustExceeded = true
+		}
+	}
+
+	// Reminders follow all tool results so that every call is answered
+	// before the next user message
+	if budgetJustExceeded {
+		r.toolsDisabled = true
+		r.markAllAttachmentsRetrieved()
+		r.remind("Token budget is nearly exhausted. Use the information collected so far and provide the final answer without calling tools again.")
+		r.finalReminderSent = true
+		r.base64ReminderSent = true
+	}
+
+	if r.attachmentLimitHit && !r.attachmentLimitNotified {
+		r.remind("Token budget is limited, so additional attachments will not be retrieved. Use the metadata and attachments already downloaded to complete your answer.")
+		r.attachmentLimitHit = false
+		r.attachmentLimitNotified = true
+		r.markAllAttachmentsRetrieved()
+	}
+
+	if missingFile {
+		r.missingFileCount++
+		example := ""
+		if next := r.nextPendingAttachment(); next != "" {
+			example = fmt.Sprintf(" Example: {\"resource\":\"attachments\",\"file\":\"%s\"}.", next)
+		}
+		r.remind("When requesting attachments you must include the \"file\" argument set to one of the attachment paths from metadata." + example + " Retry the attachment request with the proper file.")
+		if r.missingFileCount >= 3 {
+			r.markAllAttachmentsRetrieved()
+			r.attachmentNames = nil
+			r.remind("You have attempted to fetch attachments without a file parameter multiple times. Proceed using the metadata and content you already retrieved and provide the final answer without calling attachments again.")
+			r.toolsDisabled = true
+			r.finalReminderSent = true
+		}
+		return nil
+	}
+
+	if !progressed {
+		r.stallCount++
+		if r.stallCount >= 2 {
+			r.remind("You already retrieved the required referendum context. Use the information you have and provide the final answer without calling the tool again.")
+			r.stallCount = 0
+			r.stalled = true
+		}
+	} else {
+		r.stallCount = 0
+	}
+
+	if announcedAttachments && r.hasPendingAttachments() && !r.budgetExceeded {
+		example := ""
+		if next := r.nextPendingAttachment(); next != "" {
+			example = fmt.Sprintf(" For example: {\"resource\":\"attachments\",\"file\":\"%s\"}.", next)
+		}
+		r.remind("Metadata references attachments." + example + " Retrieve each file before answering.")
+		return nil
+	}
+
+	if !r.hasPendingAttachments() && len(r.attachmentNames) > 0 && !r.base64ReminderSent {
+		r.remind("Attachment content is provided as base64 text in the tool response. Decode the base64 string to inspect the file before answering.")
+		r.base64ReminderSent = true
+	}
+
+	if r.metadataFetched && r.contentFetched && !r.historyFetched && !r.historyReminderSent {
+		r.remind("Retrieve the recent Q&A history with {\"resource\":\"history\"} before answering.")
+		r.historyReminderSent = true
+		return nil
+	}
+
+	if r.contextComplete() && !r.finalReminderSent {
+		r.remind("You now have metadata, the full proposal content, prior Q&A history, and any attachments you needed. Provide the final answer without calling the tool again.")
+		r.finalReminderSent = true
+		r.toolsDisabled = true
+		return nil
+	}
+
+	if r.contentFetched && !r.metadataFetched && !r.metadataHintSent {
+		r.remind("Retrieve the referendum metadata (resource:\"metadata\") so you know the title, proposer, and attachments before answering.")
+		r.metadataHintSent = true
+	} else if r.metadataFetched && !r.contentFetched && !r.contentHintSent {
+		r.remind("Retrieve the full referendum content (resource:\"content\") before answering.")
+		r.contentHintSent = true
+	}
+	return nil
+}
+
+func (r *agentRun) execute(ctx context.Context, tool Tool, args map[string]any) string {
+	handler, ok := toolHandler(tool.Type)
+	if !ok {
+		return toolErrorResult(fmt.Sprintf("unsupported tool %s", tool.Type))
+	}
+	rawArgs := copyArgs(args)
+	args = mergeArgs(args, tool.Defaults)
+	log.Printf("%s: tool call %s raw=%v merged=%v", r.cfg.Name, tool.Name, rawArgs, args)
+	result, err := handler(ctx, r.cfg.HTTPClient, tool, args)
+	if err != nil {
+		log.Printf("%s: tool %s error: %v", r.cfg.Name, tool.Name, err)
+		result = toolErrorResult(err.Error())
+	}
+	log.Printf("%s: tool %s output=%s", r.cfg.Name, tool.Name, truncatePayload(result, 256))
+	return result
+}
+
+func toolCacheKey(call ToolCall) string {
+	return strings.ToLower(strings.TrimSpace(call.Name)) + "::" + strings.TrimSpace(call.Arguments)
+}
+
+func normalizeResource(res string) string {
+	r := strings.TrimSpace(strings.ToLower(res))
+	if r == "" {
+		return "metadata"
+	}
+	if strings.HasPrefix(r, "attachment") {
+		return "attachments"
+	}
+	return r
+}
+
+// metadataAttachmentNames lists the downloadable files named in a metadata
+// response.
+func metadataAttachmentNames(content string) []string {
+	var payload struct {
+		Attachments []struct {
+			File string `json:"file"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal([]byte(content), &payload); err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(payload.Attachments))
+	for _, att := range payload.Attachments {
+		file := strings.TrimSpace(att.File)
+		if file == "" || !strings.HasPrefix(strings.ToLower(file), "files/") {
+			continue
+		}
+		names = append(names, file)
+	}
+	return names
+}
+
+func skippedAttachmentResult(file, reason string) string {
+	name := strings.TrimSpace(file)
+	if name == "" {
+		return toolErrorResult("attachment skipped due to " + reason)
+	}
+	return toolErrorResult(fmt.Sprintf("attachment %s skipped due to %s", name, reason))
+}
+
+func estimatePromptTokens(systemPrompt string, turns []Turn) int {
+	total := estimateTextTokens(systemPrompt)
+	for _, t := range turns {
+		total += estimateTextTokens(t.Text)
+		for _, call := range t.ToolCalls {
+			total += estimateTextTokens(call.Arguments)
+		}
+	}
+	return total
+}
+
+// estimateTextTokens approximates tokens at four characters each.
+func estimateTextTokens(text string) int {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0
+	}
+	return (len(text)+3)/4 + 4
+}
@@ -21,6 +21,15 @@ type FactoryConfig struct {
 	DeepSeekKey string
 	GrokKey     string
 
+	// OpenAI-compatible server for self-hosted models
+	CompatBaseURL string
+	CompatKey     string // optional
+	CompatModel   string // used when Model is empty
+
+	// Fallbacks are tried in order when Provider is rate-limited, down or
+	// fails, given as "provider" or "provider:model"
+	Fallbacks []string
+
 	Extra map[string]string
 }
 
@@ -44,8 +53,18 @@ func RegisterProvider(name string, factory ProviderFactory, aliases ...string) {
 	}
 }
 
-// NewClient returns a provider-agnostic AI client.
+// NewClient returns a provider-agnostic AI client. With fallbacks configured
+// it returns a client that moves down the chain when a provider fails and
+// skips providers whose circuit breaker is open.
 func NewClient(cfg FactoryConfig) (Client, error) {
+	client, err := newProviderClient(cfg)
+	if len(cfg.Fallbacks) == 0 {
+		return client, err
+	}
+	return newFallbackClient(cfg, client, err)
+}
+
+func newProviderClient(cfg FactoryConfig) (Client, error) {
 	providerName := cfg.Provider
 	if strings.TrimSpace(providerName) == "" {
 		providerName = defaultKey
@@ -0,0 +1,231 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stake-plus/govcomms/src/api/webclient"
+)
+
+const (
+	// breakerThreshold is how many failed calls in a row open a provider's
+	// circuit.
+	breakerThreshold = 3
+	// breakerCooldown is how long an open circuit skips its provider. The
+	// next call after it is a trial; one more failure opens it again.
+	breakerCooldown = time.Minute
+)
+
+// breaker tracks the consecutive rate-limit, server and network failures of
+// one provider.
+type breaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   = map[string]*breaker{}
+)
+
+// breakerFor returns the circuit breaker shared by every client of provider.
+func breakerFor(provider string) *breaker {
+	key := strings.ToLower(strings.TrimSpace(provider))
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	b, ok := breakers[key]
+	if !ok {
+		b = &breaker{}
+		breakers[key] = b
+	}
+	return b
+}
+
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !now.Before(b.openUntil)
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.openUntil = time.Time{}
+}
+
+// failure counts a failed call and reports whether it opened the circuit.
+func (b *breaker) failure(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures < breakerThreshold {
+		return false
+	}
+	b.openUntil = now.Add(breakerCooldown)
+	return true
+}
+
+// unavailable reports whether err means the provider is overloaded or down
+// rather than that the request was bad.
+func unavailable(err error) bool {
+	if webclient.Transient(err) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// ServedBy records which provider of a fallback chain answered.
+type ServedBy struct {
+	mu       sync.Mutex
+	provider string
+	model    string
+}
+
+type servedByKey struct{}
+
+// WithServedBy returns a context whose fallback chain calls record the
+// provider and model that answered in the returned ServedBy.
+func WithServedBy(ctx context.Context) (context.Context, *ServedBy) {
+	served := &ServedBy{}
+	return context.WithValue(ctx, servedByKey{}, served), served
+}
+
+// Provider returns the provider key and model that answered, or empty
+// strings when no fallback chain answered.
+func (s *ServedBy) Provider() (string, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.provider, s.model
+}
+
+func noteServedBy(ctx context.Context, provider, model string) {
+	served, _ := ctx.Value(servedByKey{}).(*ServedBy)
+	if served == nil {
+		return
+	}
+	served.mu.Lock()
+	served.provider, served.model = provider, model
+	served.mu.Unlock()
+}
+
+// fallbackMember is one provider of a fallback chain.
+type fallbackMember struct {
+	provider string
+	model    string // "" for the provider's default
+	client   Client
+	primary  bool // keeps the caller's model
+}
+
+// fallbackClient tries its providers in order, skipping those whose circuit
+// is open.
+type fallbackClient struct {
+	members []fallbackMember
+}
+
+// newFallbackClient chains the configured provider with cfg.Fallbacks, given
+// as "provider" or "provider:model". Fallbacks that can't be built are
+// skipped.
+func newFallbackClient(cfg FactoryConfig, primary Client, primaryErr error) (Client, error) {
+	var members []fallbackMember
+	if primaryErr == nil {
+		members = append(members, fallbackMember{provider: cfg.Provider, client: primary, primary: true})
+	} else {
+		log.Printf("ai: provider %s unavailable, using fallbacks: %v", cfg.Provider, primaryErr)
+	}
+
+	for _, spec := range cfg.Fallbacks {
+		provider, model, _ := strings.Cut(strings.TrimSpace(spec), ":")
+		provider = strings.ToLower(strings.TrimSpace(provider))
+		model = strings.TrimSpace(model)
+		if provider == "" || (strings.EqualFold(provider, cfg.Provider) && model == "") {
+			continue
+		}
+		subCfg := cfg
+		subCfg.Provider = provider
+		subCfg.Model = model
+		subCfg.Fallbacks = nil
+		client, err := newProviderClient(subCfg)
+		if err != nil {
+			log.Printf("ai: fallback provider %s skipped: %v", provider, err)
+			continue
+		}
+		members = append(members, fallbackMember{provider: provider, model: model, client: client})
+	}
+
+	switch {
+	case len(members) == 0:
+		return nil, primaryErr
+	case len(members) == 1 && members[0].primary:
+		return primary, nil
+	}
+	return &fallbackClient{members: members}, nil
+}
+
+func (c *fallbackClient) AnswerQuestion(ctx context.Context, content string, question string, opts Options) (string, error) {
+	return c.run(ctx, opts, nil, func(member fallbackMember, opts Options) (string, error) {
+		return member.client.AnswerQuestion(ctx, content, question, opts)
+	})
+}
+
+func (c *fallbackClient) Respond(ctx context.Context, input string, tools []Tool, opts Options) (string, error) {
+	return c.run(ctx, opts, nil, func(member fallbackMember, opts Options) (string, error) {
+		return member.client.Respond(ctx, input, tools, opts)
+	})
+}
+
+// RespondStream implements StreamingClient. Before a fallback provider
+// starts, onEvent receives a StreamRestart naming it.
+func (c *fallbackClient) RespondStream(ctx context.Context, input string, tools []Tool, opts Options, onEvent StreamHandler) (string, error) {
+	return c.run(ctx, opts, onEvent, func(member fallbackMember, opts Options) (string, error) {
+		return RespondStream(ctx, member.client, input, tools, opts, onEvent)
+	})
+}
+
+func (c *fallbackClient) run(ctx context.Context, opts Options, onEvent StreamHandler, call func(fallbackMember, Options) (string, error)) (string, error) {
+	var errs []error
+	attempted := false
+	for _, member := range c.members {
+		b := breakerFor(member.provider)
+		if !b.allow(time.Now()) {
+			errs = append(errs, fmt.Errorf("%s: circuit open", member.provider))
+			continue
+		}
+
+		memberOpts := opts
+		if !member.primary {
+			memberOpts.Model = member.model
+		}
+		model := ResolveModelName(member.provider, memberOpts.Model)
+		if attempted && onEvent != nil {
+			if err := onEvent(StreamEvent{Type: StreamRestart, Provider: member.provider, Model: model}); err != nil {
+				return "", err
+			}
+		}
+		attempted = true
+
+		answer, err := call(member, memberOpts)
+		if err == nil {
+			b.success()
+			noteServedBy(ctx, member.provider, model)
+			return answer, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+		if unavailable(err) && b.failure(time.Now()) {
+			log.Printf("ai: circuit open for %s for %s", member.provider, breakerCooldown)
+		}
+		log.Printf("ai: provider %s failed, trying the next: %v", member.provider, err)
+		errs = append(errs, fmt.Errorf("%s: %w", member.provider, err))
+	}
+	return "", fmt.Errorf("ai: every provider failed: %w", errors.Join(errs...))
+}
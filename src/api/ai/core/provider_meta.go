@@ -51,6 +51,9 @@ var providerInfo = map[string]ProviderInfo{
 		Website: "https://anthropic.com",
 		Model:   "claude-sonnet-4-5",
 	},
+	"openaicompat": {
+		Company: "Self-hosted",
+	},
 }
 
 var providerDefaultModels = map[string]string{
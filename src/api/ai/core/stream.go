@@ -0,0 +1,59 @@
+package core
+
+import "context"
+
+// StreamEventType identifies what a StreamEvent carries.
+type StreamEventType string
+
+const (
+	// StreamText carries the next piece of the model's reply.
+	StreamText StreamEventType = "text"
+	// StreamToolCall reports a tool the model called. Text streamed before it
+	// was the model thinking aloud, not the answer, so consumers showing a
+	// draft start over.
+	StreamToolCall StreamEventType = "tool_call"
+	// StreamRestart reports that the provider failed and the next one in
+	// the fallback chain starts over; consumers discard the draft.
+	StreamRestart StreamEventType = "restart"
+)
+
+// StreamEvent is one increment of a streamed response.
+type StreamEvent struct {
+	Type      StreamEventType
+	Text      string // text delta for StreamText
+	Tool      string // tool name for StreamToolCall
+	Arguments string // JSON arguments for StreamToolCall
+	Provider  string // provider key for StreamRestart
+	Model     string // model for StreamRestart
+}
+
+// StreamHandler receives stream events in order. Returning an error aborts
+// the response with that error.
+type StreamHandler func(StreamEvent) error
+
+// StreamingClient is implemented by clients that can stream a response as it
+// is generated.
+type StreamingClient interface {
+	Client
+	// RespondStream behaves like Respond and also reports text deltas and
+	// tool calls to onEvent while the response is generated. It returns the
+	// final answer.
+	RespondStream(ctx context.Context, input string, tools []Tool, opts Options, onEvent StreamHandler) (string, error)
+}
+
+// RespondStream streams a response when the client supports it. Other
+// clients answer with Respond and report the whole answer as one text event.
+func RespondStream(ctx context.Context, client Client, input string, tools []Tool, opts Options, onEvent StreamHandler) (string, error) {
+	if streaming, ok := client.(StreamingClient); ok && onEvent != nil {
+		return streaming.RespondStream(ctx, input, tools, opts, onEvent)
+	}
+
+	answer, err := client.Respond(ctx, input, tools, opts)
+	if err != nil || onEvent == nil {
+		return answer, err
+	}
+	if err := onEvent(StreamEvent{Type: StreamText, Text: answer}); err != nil {
+		return "", err
+	}
+	return answer, nil
+}
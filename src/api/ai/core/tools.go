@@ -0,0 +1,274 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ToolMCPReferenda is the tool type for GovComms' referendum MCP server.
+const ToolMCPReferenda = "mcp_referenda"
+
+// ToolHandler runs a tool call with the model's arguments merged over the
+// tool's defaults and returns the result handed back to the model.
+type ToolHandler func(ctx context.Context, httpClient *http.Client, tool Tool, args map[string]any) (string, error)
+
+var (
+	toolHandlersMu sync.RWMutex
+	toolHandlers   = map[string]ToolHandler{}
+)
+
+// RegisterToolHandler makes tools of the given type callable by every
+// provider that runs the agent loop.
+func RegisterToolHandler(toolType string, handler ToolHandler) {
+	toolHandlersMu.Lock()
+	defer toolHandlersMu.Unlock()
+	toolHandlers[strings.ToLower(strings.TrimSpace(toolType))] = handler
+}
+
+func toolHandler(toolType string) (ToolHandler, bool) {
+	toolHandlersMu.RLock()
+	defer toolHandlersMu.RUnlock()
+	handler, ok := toolHandlers[strings.ToLower(strings.TrimSpace(toolType))]
+	return handler, ok
+}
+
+func init() {
+	RegisterToolHandler(ToolMCPReferenda, invokeMCP)
+}
+
+// invokeMCP fetches a referendum resource from the MCP server.
+func invokeMCP(ctx context.Context, httpClient *http.Client, tool Tool, args map[string]any) (string, error) {
+	desc := tool.MCP
+	if desc == nil || strings.TrimSpace(desc.BaseURL) == "" {
+		return "", fmt.Errorf("mcp descriptor missing")
+	}
+	network := normalizedArgValue(args["network"])
+	if network == "" {
+		return "", fmt.Errorf("network argument required")
+	}
+	refIDRaw, ok := args["refId"]
+	if !ok {
+		return "", fmt.Errorf("refId argument required")
+	}
+	refID, err := parseUint(refIDRaw)
+	if err != nil {
+		return "", fmt.Errorf("invalid refId: %w", err)
+	}
+	resource := strings.ToLower(normalizedArgValue(args["resource"]))
+	fileParam := normalizedArgValue(args["file"])
+	if resource == "attachments" && fileParam == "" {
+		return "", fmt.Errorf("file argument required for attachments")
+	}
+	base := strings.TrimRight(desc.BaseURL, "/")
+	endpoint := fmt.Sprintf("%s/v1/referenda/%s/%d", base, url.PathEscape(network), refID)
+	if resource != "" && resource != "metadata" {
+		endpoint += "/" + url.PathEscape(resource)
+	}
+	if resource == "attachments" {
+		query := url.Values{}
+		query.Set("file", fileParam)
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	if desc.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+desc.AuthToken)
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("mcp: status %d: %s", resp.StatusCode, string(body))
+	}
+	return string(body), nil
+}
+
+// decodeToolArguments parses a call's JSON arguments, cleaning up values
+// some models wrap in markup or send as "null".
+func decodeToolArguments(raw string) (map[string]any, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" || strings.EqualFold(trimmed, "null") {
+		return map[string]any{}, nil
+	}
+	var args map[string]any
+	if err := json.Unmarshal([]byte(trimmed), &args); err != nil {
+		return nil, err
+	}
+	return normalizeToolArguments(args), nil
+}
+
+func mergeArgs(args map[string]any, defaults map[string]any) map[string]any {
+	if args == nil {
+		args = map[string]any{}
+	}
+	for k, v := range defaults {
+		if _, exists := args[k]; !exists {
+			args[k] = v
+		}
+	}
+	return args
+}
+
+func copyArgs(src map[string]any) map[string]any {
+	if src == nil {
+		return nil
+	}
+	dst := make(map[string]any, len(src))
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+var weirdParamPattern = regexp.MustCompile(`parameter name="([^"]+)">([^<]+)`)
+
+// normalizeToolArguments trims values and unpacks parameters that some
+// models embed as `<parameter name="x">y` markup inside another value.
+func normalizeToolArguments(args map[string]any) map[string]any {
+	if len(args) == 0 {
+		return args
+	}
+	for key, val := range args {
+		strVal, ok := val.(string)
+		if !ok {
+			if val == nil {
+				delete(args, key)
+			}
+			continue
+		}
+		if !strings.Contains(strVal, "parameter name=") {
+			clean := normalizedString(strVal)
+			if clean == "" {
+				delete(args, key)
+			} else {
+				args[key] = clean
+			}
+			continue
+		}
+		base := normalizedString(strBefore(strVal, "<"))
+		if base != "" {
+			args[key] = base
+		} else {
+			delete(args, key)
+		}
+		for name, value := range extractWeirdParameters(strVal) {
+			if strings.TrimSpace(name) == "" {
+				continue
+			}
+			if value == "" {
+				delete(args, name)
+				continue
+			}
+			args[name] = value
+		}
+	}
+	return args
+}
+
+func extractWeirdParameters(input string) map[string]string {
+	matches := weirdParamPattern.FindAllStringSubmatch(input, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(matches))
+	for _, match := range matches {
+		if len(match) < 3 {
+			continue
+		}
+		name := strings.TrimSpace(match[1])
+		if name == "" {
+			continue
+		}
+		out[name] = normalizedString(html.UnescapeString(match[2]))
+	}
+	return out
+}
+
+func normalizedString(value string) string {
+	trimmed := strings.TrimSpace(value)
+	lowered := strings.ToLower(trimmed)
+	if trimmed == "" || trimmed == "<nil>" || lowered == "null" || lowered == "nil" {
+		return ""
+	}
+	return trimmed
+}
+
+func normalizedArgValue(val any) string {
+	if val == nil {
+		return ""
+	}
+	switch v := val.(type) {
+	case string:
+		return normalizedString(v)
+	default:
+		return normalizedString(fmt.Sprint(v))
+	}
+}
+
+func strBefore(input, sep string) string {
+	if idx := strings.Index(input, sep); idx >= 0 {
+		return input[:idx]
+	}
+	return input
+}
+
+func parseUint(value any) (uint64, error) {
+	switch v := value.(type) {
+	case float64:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	case uint32:
+		return uint64(v), nil
+	case uint:
+		return uint64(v), nil
+	case int:
+		return uint64(v), nil
+	case int64:
+		return uint64(v), nil
+	case string:
+		return strconv.ParseUint(strings.TrimSpace(v), 10, 64)
+	default:
+		return 0, fmt.Errorf("unsupported numeric type %T", value)
+	}
+}
+
+func truncatePayload(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return s[:limit] + "... (truncated)"
+}
+
+// toolErrorResult reports a failed call to the model as a JSON error.
+func toolErrorResult(msg string) string {
+	msg = strings.TrimSpace(msg)
+	if len(msg) > 200 {
+		msg = msg[:200]
+	}
+	out, _ := json.Marshal(map[string]string{"error": msg})
+	return string(out)
+}
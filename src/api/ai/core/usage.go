@@ -0,0 +1,124 @@
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// Features a model call is billed to in the usage ledger.
+const (
+	FeatureQuestion  = "question"
+	FeatureSummary   = "summary"
+	FeatureResearch  = "research"
+	FeatureReport    = "report"
+	FeatureConsensus = "consensus"
+	FeatureAgents    = "agents"
+)
+
+// Usage is the token count a provider reported for one model request.
+type Usage struct {
+	Provider     string
+	Model        string // model ID returned by the API, else the one requested
+	InputTokens  int    // prompt tokens, including cached ones
+	OutputTokens int    // completion tokens, including reasoning
+	CachedTokens int    // prompt tokens served from the provider's cache
+}
+
+// Empty reports whether the provider returned no counts.
+func (u Usage) Empty() bool {
+	return u.InputTokens == 0 && u.OutputTokens == 0 && u.CachedTokens == 0
+}
+
+// UsageScope says what model calls were made for.
+type UsageScope struct {
+	Feature   string
+	NetworkID uint8
+	RefID     uint32
+	Command   string // slash command the calls answer, if any
+	UserID    string // Discord user who asked, if any
+	GuildID   string // Discord guild asked in, if any
+}
+
+// UsageRecorder stores the usage of one model request.
+type UsageRecorder func(scope UsageScope, usage Usage)
+
+type usageScopeKey struct{}
+
+var (
+	usageMu       sync.RWMutex
+	usageRecorder UsageRecorder
+)
+
+// SetUsageRecorder makes every provider report its token usage to rec.
+func SetUsageRecorder(rec UsageRecorder) {
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	usageRecorder = rec
+}
+
+// WithUsageScope bills the model calls made with ctx to scope.
+func WithUsageScope(ctx context.Context, scope UsageScope) context.Context {
+	return context.WithValue(ctx, usageScopeKey{}, scope)
+}
+
+// WithUsageFeature bills the model calls made with ctx to feature, keeping
+// the referendum, command, user and guild of the current scope.
+func WithUsageFeature(ctx context.Context, feature string) context.Context {
+	scope := UsageScopeFrom(ctx)
+	scope.Feature = feature
+	return WithUsageScope(ctx, scope)
+}
+
+// UsageScopeFrom returns the scope set on ctx, if any.
+func UsageScopeFrom(ctx context.Context) UsageScope {
+	scope, _ := ctx.Value(usageScopeKey{}).(UsageScope)
+	return scope
+}
+
+// ReportUsage passes a request's usage to the recorder along with the scope
+// of ctx. Providers call it once per API request.
+func ReportUsage(ctx context.Context, usage Usage) {
+	if usage.Empty() {
+		return
+	}
+	usageMu.RLock()
+	rec := usageRecorder
+	usageMu.RUnlock()
+	if rec != nil {
+		rec(UsageScopeFrom(ctx), usage)
+	}
+}
+
+// FeatureClient bills the calls made through client to feature unless their
+// context already names one.
+func FeatureClient(client Client, feature string) Client {
+	if client == nil {
+		return nil
+	}
+	return &featureClient{Client: client, feature: feature}
+}
+
+type featureClient struct {
+	Client
+	feature string
+}
+
+func (c *featureClient) scoped(ctx context.Context) context.Context {
+	if UsageScopeFrom(ctx).Feature != "" {
+		return ctx
+	}
+	return WithUsageFeature(ctx, c.feature)
+}
+
+func (c *featureClient) AnswerQuestion(ctx context.Context, content string, question string, opts Options) (string, error) {
+	return c.Client.AnswerQuestion(c.scoped(ctx), content, question, opts)
+}
+
+func (c *featureClient) Respond(ctx context.Context, input string, tools []Tool, opts Options) (string, error) {
+	return c.Client.Respond(c.scoped(ctx), input, tools, opts)
+}
+
+// RespondStream implements StreamingClient.
+func (c *featureClient) RespondStream(ctx context.Context, input string, tools []Tool, opts Options, onEvent StreamHandler) (string, error) {
+	return RespondStream(c.scoped(ctx), c.Client, input, tools, opts, onEvent)
+}
@@ -0,0 +1,76 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/stake-plus/govcomms/src/api/ai/core"
+	"github.com/stake-plus/govcomms/src/api/webclient"
+)
+
+// streamGenerateContent runs one turn through streamGenerateContent,
+// reporting text as it arrives and merging the chunks into one content. Each
+// chunk carries the usage so far, so the last one holds the totals.
+func (c *client) streamGenerateContent(ctx context.Context, model string, payload map[string]any, onEvent core.StreamHandler) (geminiContent, core.Usage, error) {
+	url := fmt.Sprintf("%s/%s:streamGenerateContent?alt=sse&key=%s", baseURL, normalizeModel(model), c.apiKey)
+	bodyBytes, _ := json.Marshal(payload)
+
+	resp, err := webclient.OpenStream(ctx, c.httpClient, retryAttempts, retryBackoff, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(bodyBytes))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "text/event-stream")
+		return req, nil
+	})
+	if err != nil {
+		return geminiContent{}, core.Usage{}, fmt.Errorf("gemini chat error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	content := geminiContent{Role: "model"}
+	received := false
+	var usage core.Usage
+	err = webclient.ReadSSE(resp.Body, func(_, data string) error {
+		var chunk generateContentResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("invalid stream chunk: %w", err)
+		}
+		if chunk.UsageMetadata != nil {
+			usage = chunk.UsageMetadata.toCore(chunk.ModelVersion, model)
+		}
+		if len(chunk.Candidates) == 0 {
+			return nil
+		}
+		received = true
+		for _, part := range chunk.Candidates[0].Content.Parts {
+			switch {
+			case part.FunctionCall != nil:
+				content.Parts = append(content.Parts, part)
+			case part.Text != "":
+				// Text arrives split across chunks; keep it as one part so
+				// FirstText doesn't insert line breaks mid-sentence
+				if n := len(content.Parts); n > 0 && content.Parts[n-1].FunctionCall == nil && content.Parts[n-1].FunctionResponse == nil {
+					content.Parts[n-1].Text += part.Text
+				} else {
+					content.Parts = append(content.Parts, geminiPart{Text: part.Text})
+				}
+				if err := onEvent(core.StreamEvent{Type: core.StreamText, Text: part.Text}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return geminiContent{}, core.Usage{}, fmt.Errorf("gemini chat error: %w", err)
+	}
+	if !received {
+		return geminiContent{}, core.Usage{}, fmt.Errorf("gemini: streamGenerateContent returned no candidates")
+	}
+	return content, usage, nil
+}
@@ -0,0 +1,360 @@
+// Package openaichat is the client shared by the providers that speak the
+// OpenAI Chat Completions API. Each provider only supplies its Config.
+package openaichat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/stake-plus/govcomms/src/api/ai/core"
+	"github.com/stake-plus/govcomms/src/api/webclient"
+)
+
+const (
+	retryBackoff      = 2 * time.Second
+	maxToolIterations = 20
+)
+
+// Config describes one Chat Completions provider.
+type Config struct {
+	// Provider is the provider key reported with token usage and used to
+	// prefix errors.
+	Provider string
+	Endpoint string
+	// APIKey is sent as a bearer token when set.
+	APIKey string
+	// MaxTokensParam names the request's output limit, max_completion_tokens
+	// on OpenAI and max_tokens on most other servers.
+	MaxTokensParam string
+	// Sampling holds extra parameters sent with every request, such as top_p.
+	Sampling map[string]any
+	// DisableTools stops function tools such as MCP being offered.
+	DisableTools bool
+	// WebSearchTool is the provider's native search tool, offered when the
+	// caller asked for web search.
+	WebSearchTool map[string]any
+	// WebSearchAlone offers WebSearchTool only on turns without function
+	// tools, for servers that reject mixed tool types.
+	WebSearchAlone bool
+	RequestTimeout time.Duration
+	RetryAttempts  int
+	Defaults       core.Options
+}
+
+type client struct {
+	cfg        Config
+	httpClient *http.Client
+}
+
+// New returns a client for the provider described by cfg.
+func New(cfg Config) core.Client {
+	return &client{cfg: cfg, httpClient: webclient.NewDefault(cfg.RequestTimeout)}
+}
+
+func (c *client) AnswerQuestion(ctx context.Context, content string, question string, opts core.Options) (string, error) {
+	merged := c.merge(opts)
+	var messages []chatMessagePayload
+	if strings.TrimSpace(merged.SystemPrompt) != "" {
+		messages = append(messages, chatMessagePayload{Role: "system", Content: merged.SystemPrompt})
+	}
+	messages = append(messages, chatMessagePayload{
+		Role:    "user",
+		Content: fmt.Sprintf("Proposal Content:\n%s\n\nQuestion: %s\n\nProvide a direct, concise answer.", content, question),
+	})
+	msg, usage, err := c.completeChat(ctx, c.requestBody(merged, messages), nil)
+	if err != nil {
+		return "", err
+	}
+	core.ReportUsage(ctx, usage)
+	return msg.Content, nil
+}
+
+func (c *client) Respond(ctx context.Context, input string, tools []core.Tool, opts core.Options) (string, error) {
+	merged := c.merge(opts)
+	return c.respond(ctx, input, tools, merged, nil)
+}
+
+// RespondStream implements core.StreamingClient.
+func (c *client) RespondStream(ctx context.Context, input string, tools []core.Tool, opts core.Options, onEvent core.StreamHandler) (string, error) {
+	merged := c.merge(opts)
+	return c.respond(ctx, input, tools, merged, onEvent)
+}
+
+func (c *client) merge(opts core.Options) core.Options {
+	out := c.cfg.Defaults
+	if opts.Model != "" {
+		out.Model = opts.Model
+	}
+	if opts.Temperature != 0 {
+		out.Temperature = opts.Temperature
+	}
+	if opts.MaxCompletionTokens != 0 {
+		out.MaxCompletionTokens = opts.MaxCompletionTokens
+	}
+	if opts.SystemPrompt != "" {
+		out.SystemPrompt = opts.SystemPrompt
+	}
+	if opts.EnableWebSearch {
+		out.EnableWebSearch = true
+	}
+	return out
+}
+
+// requestBody builds a chat completion request with the provider's sampling
+// parameters.
+func (c *client) requestBody(opts core.Options, messages []chatMessagePayload) map[string]any {
+	reqBody := map[string]any{
+		"model":       opts.Model,
+		"messages":    messages,
+		"temperature": opts.Temperature,
+	}
+	for key, val := range c.cfg.Sampling {
+		reqBody[key] = val
+	}
+	if opts.MaxCompletionTokens > 0 {
+		reqBody[c.cfg.MaxTokensParam] = opts.MaxCompletionTokens
+	}
+	return reqBody
+}
+
+// respond runs the shared agent loop over Chat Completions.
+func (c *client) respond(ctx context.Context, input string, tools []core.Tool, opts core.Options, onEvent core.StreamHandler) (string, error) {
+	if c.cfg.DisableTools {
+		tools = nil
+	}
+	cfg := core.AgentConfig{
+		Name:          c.cfg.Provider,
+		HTTPClient:    c.httpClient,
+		MaxIterations: maxToolIterations,
+	}
+	return core.RunAgent(ctx, cfg, input, tools, opts, onEvent, func(ctx context.Context, req core.TurnRequest) (core.Turn, error) {
+		reqBody := c.requestBody(opts, buildChatMessages(req))
+		if toolDefs := c.toolsPayload(req); len(toolDefs) > 0 {
+			reqBody["tools"] = toolDefs
+			reqBody["tool_choice"] = buildChatToolChoice(req.RequiredFunction)
+		}
+
+		msg, usage, err := c.completeChat(ctx, reqBody, req.OnEvent)
+		if err != nil {
+			return core.Turn{}, err
+		}
+		turn := chatTurn(msg)
+		turn.Usage = usage
+		return turn, nil
+	})
+}
+
+func truncatePayload(b []byte, limit int) string {
+	if len(b) <= limit {
+		return string(b)
+	}
+	return string(b[:limit]) + "... (truncated)"
+}
+
+func (c *client) newRequest(ctx context.Context, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.Endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Most local servers run without authentication
+	if c.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	}
+	return req, nil
+}
+
+// completeChat runs one chat completion, streaming it to onEvent when set,
+// and returns the reply with its token usage.
+func (c *client) completeChat(ctx context.Context, reqBody map[string]any, onEvent core.StreamHandler) (chatMessage, core.Usage, error) {
+	if onEvent != nil {
+		return c.streamChat(ctx, reqBody, onEvent)
+	}
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	_, body, err := webclient.DoWithRetry(ctx, c.cfg.RetryAttempts, retryBackoff, func() (int, []byte, error) {
+		req, err := c.newRequest(ctx, bodyBytes)
+		if err != nil {
+			return 0, nil, err
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return 0, nil, err
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return resp.StatusCode, nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, b, fmt.Errorf("status %d: %s", resp.StatusCode, truncatePayload(b, 512))
+		}
+		return resp.StatusCode, b, nil
+	})
+	if err != nil {
+		return chatMessage{}, core.Usage{}, fmt.Errorf("%s API error: %w", c.cfg.Provider, err)
+	}
+
+	var resp chatCompletionResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return chatMessage{}, core.Usage{}, err
+	}
+	if len(resp.Choices) == 0 {
+		return chatMessage{}, core.Usage{}, fmt.Errorf("%s: chat completion returned no choices", c.cfg.Provider)
+	}
+	model, _ := reqBody["model"].(string)
+	return resp.Choices[0].Message, resp.Usage.toCore(c.cfg.Provider, resp.Model, model), nil
+}
+
+func buildChatMessages(req core.TurnRequest) []chatMessagePayload {
+	messages := make([]chatMessagePayload, 0, len(req.Turns)+1)
+	if strings.TrimSpace(req.SystemPrompt) != "" {
+		messages = append(messages, chatMessagePayload{Role: "system", Content: req.SystemPrompt})
+	}
+	for _, turn := range req.Turns {
+		switch turn.Role {
+		case core.RoleAssistant:
+			payload := chatMessagePayload{Role: "assistant", Content: turn.Text}
+			for _, call := range turn.ToolCalls {
+				toolCall := chatToolCall{ID: call.ID, Type: "function"}
+				toolCall.Function.Name = call.Name
+				toolCall.Function.Arguments = call.Arguments
+				payload.ToolCalls = append(payload.ToolCalls, toolCall)
+			}
+			messages = append(messages, payload)
+		case core.RoleTool:
+			messages = append(messages, chatMessagePayload{
+				Role:       "tool",
+				ToolCallID: turn.ToolCallID,
+				Name:       turn.ToolName,
+				Content:    turn.Text,
+			})
+		default:
+			messages = append(messages, chatMessagePayload{Role: "user", Content: turn.Text})
+		}
+	}
+	return messages
+}
+
+// toolsPayload lists the function tools offered this turn, with the
+// provider's native search tool when the caller asked for web search.
+func (c *client) toolsPayload(req core.TurnRequest) []map[string]any {
+	out := buildChatToolsPayload(req.Functions)
+	if req.WebSearch && c.cfg.WebSearchTool != nil && (!c.cfg.WebSearchAlone || len(out) == 0) {
+		out = append([]map[string]any{c.cfg.WebSearchTool}, out...)
+	}
+	return out
+}
+
+func buildChatToolsPayload(tools []core.Tool) []map[string]any {
+	out := make([]map[string]any, 0, len(tools))
+	for _, t := range tools {
+		funcDef := map[string]any{
+			"name":        t.Name,
+			"description": t.Description,
+		}
+		if t.Parameters != nil {
+			funcDef["parameters"] = t.Parameters
+		}
+		out = append(out, map[string]any{
+			"type":     "function",
+			"function": funcDef,
+		})
+	}
+	return out
+}
+
+func buildChatToolChoice(forced string) any {
+	if strings.TrimSpace(forced) == "" {
+		return "auto"
+	}
+	return map[string]any{
+		"type": "function",
+		"function": map[string]any{
+			"name": forced,
+		},
+	}
+}
+
+func chatTurn(msg chatMessage) core.Turn {
+	turn := core.Turn{Role: core.RoleAssistant, Text: msg.Content}
+	for i, call := range msg.ToolCalls {
+		id := call.ID
+		if id == "" {
+			// Some servers leave tool call IDs out
+			id = fmt.Sprintf("call_%d", i)
+		}
+		turn.ToolCalls = append(turn.ToolCalls, core.ToolCall{
+			ID:        id,
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		})
+	}
+	return turn
+}
+
+type chatCompletionResponse struct {
+	Model   string     `json:"model"`
+	Usage   *chatUsage `json:"usage"`
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+type chatUsage struct {
+	PromptTokens        int `json:"prompt_tokens"`
+	CompletionTokens    int `json:"completion_tokens"`
+	PromptTokensDetails *struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
+}
+
+// toCore converts the reported usage, preferring the model ID the server
+// returned over the one requested.
+func (u *chatUsage) toCore(provider, model, requested string) core.Usage {
+	if u == nil {
+		return core.Usage{}
+	}
+	if model == "" {
+		model = requested
+	}
+	usage := core.Usage{
+		Provider:     provider,
+		Model:        model,
+		InputTokens:  u.PromptTokens,
+		OutputTokens: u.CompletionTokens,
+	}
+	if u.PromptTokensDetails != nil {
+		usage.CachedTokens = u.PromptTokensDetails.CachedTokens
+	}
+	return usage
+}
+
+type chatMessage struct {
+	Role      string         `json:"role"`
+	Content   string         `json:"content"`
+	ToolCalls []chatToolCall `json:"tool_calls"`
+}
+
+type chatToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type chatMessagePayload struct {
+	Role       string         `json:"role"`
+	Content    string         `json:"content,omitempty"`
+	ToolCalls  []chatToolCall `json:"tool_calls,omitempty"`
+	Name       string         `json:"name,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
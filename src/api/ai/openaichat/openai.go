@@ -0,0 +1,64 @@
+package openaichat
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stake-plus/govcomms/src/api/ai/core"
+)
+
+const (
+	openAIEndpoint       = "https://api.openai.com/v1/chat/completions"
+	openAIMaxTokens      = 8192
+	openAITemperature    = 1.0
+	openAITopP           = 0.9
+	openAIRequestTimeout = 240 * time.Second
+	openAIRetryAttempts  = 3
+	minTopP              = 0.01
+	maxTopP              = 1.0
+	penaltyMin           = -2.0
+	penaltyMax           = 2.0
+)
+
+// NewOpenAI returns a client for an OpenAI model, with defaultModel used
+// when none is configured. Sampling is tuned through the Extra keys
+// "<provider>.top_p", "<provider>.frequency_penalty" and
+// "<provider>.presence_penalty".
+func NewOpenAI(cfg core.FactoryConfig, provider, defaultModel string) (core.Client, error) {
+	if cfg.OpenAIKey == "" {
+		return nil, fmt.Errorf("%s: OpenAI API key not configured", provider)
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = defaultModel
+	}
+	temperature := cfg.Temperature
+	if temperature == 0 {
+		temperature = openAITemperature
+	}
+	maxTokens := cfg.MaxCompletionTokens
+	if maxTokens == 0 {
+		maxTokens = openAIMaxTokens
+	}
+
+	return New(Config{
+		Provider:       provider,
+		Endpoint:       openAIEndpoint,
+		APIKey:         cfg.OpenAIKey,
+		MaxTokensParam: "max_completion_tokens",
+		Sampling: map[string]any{
+			"top_p":             core.ClampFloat(core.ExtraFloat(cfg.Extra, provider+".top_p", openAITopP), minTopP, maxTopP),
+			"frequency_penalty": core.ClampFloat(core.ExtraFloat(cfg.Extra, provider+".frequency_penalty", 0), penaltyMin, penaltyMax),
+			"presence_penalty":  core.ClampFloat(core.ExtraFloat(cfg.Extra, provider+".presence_penalty", 0), penaltyMin, penaltyMax),
+		},
+		RequestTimeout: openAIRequestTimeout,
+		RetryAttempts:  openAIRetryAttempts,
+		Defaults: core.Options{
+			Model:               model,
+			Temperature:         temperature,
+			MaxCompletionTokens: maxTokens,
+			SystemPrompt:        cfg.SystemPrompt,
+		},
+	}), nil
+}
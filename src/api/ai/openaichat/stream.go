@@ -0,0 +1,101 @@
+package openaichat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/stake-plus/govcomms/src/api/ai/core"
+	"github.com/stake-plus/govcomms/src/api/webclient"
+)
+
+type chatCompletionChunk struct {
+	Model   string     `json:"model"`
+	Usage   *chatUsage `json:"usage"`
+	Choices []struct {
+		Index int `json:"index"`
+		Delta struct {
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				Index    int    `json:"index"`
+				ID       string `json:"id"`
+				Type     string `json:"type"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+// streamChat runs one chat completion with stream enabled, reporting content
+// deltas as they arrive and assembling tool calls from their fragments.
+// Servers that support include_usage send the token counts in a final chunk.
+func (c *client) streamChat(ctx context.Context, reqBody map[string]any, onEvent core.StreamHandler) (chatMessage, core.Usage, error) {
+	reqBody["stream"] = true
+	reqBody["stream_options"] = map[string]any{"include_usage": true}
+	bodyBytes, _ := json.Marshal(reqBody)
+	resp, err := webclient.OpenStream(ctx, c.httpClient, c.cfg.RetryAttempts, retryBackoff, func() (*http.Request, error) {
+		req, err := c.newRequest(ctx, bodyBytes)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "text/event-stream")
+		return req, nil
+	})
+	if err != nil {
+		return chatMessage{}, core.Usage{}, fmt.Errorf("%s chat stream error: %w", c.cfg.Provider, err)
+	}
+	defer resp.Body.Close()
+
+	var content strings.Builder
+	var calls []chatToolCall
+	var usage core.Usage
+	err = webclient.ReadSSE(resp.Body, func(_, data string) error {
+		var chunk chatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("%s: invalid stream chunk: %w", c.cfg.Provider, err)
+		}
+		if chunk.Usage != nil {
+			model, _ := reqBody["model"].(string)
+			usage = chunk.Usage.toCore(c.cfg.Provider, chunk.Model, model)
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Index != 0 {
+				continue
+			}
+			if delta := choice.Delta.Content; delta != "" {
+				content.WriteString(delta)
+				if err := onEvent(core.StreamEvent{Type: core.StreamText, Text: delta}); err != nil {
+					return err
+				}
+			}
+			for _, part := range choice.Delta.ToolCalls {
+				if part.Index < 0 {
+					continue
+				}
+				for len(calls) <= part.Index {
+					calls = append(calls, chatToolCall{Type: "function"})
+				}
+				call := &calls[part.Index]
+				if part.ID != "" {
+					call.ID = part.ID
+				}
+				if part.Type != "" {
+					call.Type = part.Type
+				}
+				call.Function.Name += part.Function.Name
+				call.Function.Arguments += part.Function.Arguments
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return chatMessage{}, core.Usage{}, fmt.Errorf("%s chat stream error: %w", c.cfg.Provider, err)
+	}
+
+	return chatMessage{Role: "assistant", Content: content.String(), ToolCalls: calls}, usage, nil
+}
@@ -0,0 +1,90 @@
+package openaicompat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stake-plus/govcomms/src/api/ai/core"
+	"github.com/stake-plus/govcomms/src/api/ai/openaichat"
+)
+
+const (
+	providerKey     = "openaicompat"
+	chatCompletions = "/chat/completions"
+	maxTokensLimit  = 4096
+	temperature     = 0.7
+	requestTimeout  = 600 * time.Second
+	retryAttempts   = 2
+)
+
+// extraToolsKey turns function calling off for servers or models that
+// reject the tools parameter.
+const extraToolsKey = "openaicompat.tools"
+
+func init() {
+	core.RegisterProvider(providerKey, newClient)
+}
+
+// newClient returns a client for any server exposing the OpenAI Chat
+// Completions API, such as vLLM, llama.cpp server, Ollama or LM Studio.
+// Self-hosted servers have no native web search, so only function tools such
+// as MCP are offered.
+func newClient(cfg core.FactoryConfig) (core.Client, error) {
+	endpoint := chatCompletionsEndpoint(cfg.CompatBaseURL)
+	if endpoint == "" {
+		return nil, fmt.Errorf("openaicompat: base URL not configured")
+	}
+	modelName := valueOrDefault(cfg.Model, cfg.CompatModel)
+	if modelName == "" {
+		return nil, fmt.Errorf("openaicompat: model not configured")
+	}
+
+	return openaichat.New(openaichat.Config{
+		Provider:       providerKey,
+		Endpoint:       endpoint,
+		APIKey:         strings.TrimSpace(cfg.CompatKey),
+		MaxTokensParam: "max_tokens",
+		DisableTools:   !core.ExtraBool(cfg.Extra, extraToolsKey, true),
+		RequestTimeout: requestTimeout,
+		RetryAttempts:  retryAttempts,
+		Defaults: core.Options{
+			Model:               modelName,
+			Temperature:         orFloat(cfg.Temperature, temperature),
+			MaxCompletionTokens: orInt(cfg.MaxCompletionTokens, maxTokensLimit),
+			SystemPrompt:        cfg.SystemPrompt,
+		},
+	}), nil
+}
+
+// chatCompletionsEndpoint accepts a base URL such as
+// http://localhost:11434/v1 or the full chat completions URL.
+func chatCompletionsEndpoint(baseURL string) string {
+	base := strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if base == "" {
+		return ""
+	}
+	if strings.HasSuffix(base, chatCompletions) {
+		return base
+	}
+	return base + chatCompletions
+}
+
+func valueOrDefault(val, def string) string {
+	if val = strings.TrimSpace(val); val != "" {
+		return val
+	}
+	return strings.TrimSpace(def)
+}
+func orInt(v, d int) int {
+	if v != 0 {
+		return v
+	}
+	return d
+}
+func orFloat(v, d float64) float64 {
+	if v != 0 {
+		return v
+	}
+	return d
+}
@@ -8,6 +8,7 @@ import (
 	_ "github.com/stake-plus/govcomms/src/api/ai/gpt51"
 	_ "github.com/stake-plus/govcomms/src/api/ai/grok4"
 	_ "github.com/stake-plus/govcomms/src/api/ai/haiku45"
+	_ "github.com/stake-plus/govcomms/src/api/ai/openaicompat"
 	_ "github.com/stake-plus/govcomms/src/api/ai/opus41"
 	_ "github.com/stake-plus/govcomms/src/api/ai/sonnet45"
 )
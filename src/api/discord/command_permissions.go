@@ -0,0 +1,266 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+	"gorm.io/gorm"
+)
+
+// Effects of a command permission rule
+const (
+	PermissionAllow = "allow"
+	PermissionDeny  = "deny"
+)
+
+// Subjects a command permission rule applies to
+const (
+	PermissionSubjectRole      = "role"
+	PermissionSubjectUser      = "user"
+	PermissionSubjectChannel   = "channel"    // a channel, or the parent channel of a thread
+	PermissionSubjectDaoMember = "dao_member" // Discord users linked to one of the guild's DAO member addresses
+	PermissionSubjectEveryone  = "everyone"
+)
+
+// PermissionAnyCommand is the command name of rules that apply to every command
+const PermissionAnyCommand = "*"
+
+// permissionReloadInterval is how long loaded rules are used before the
+// command_permissions table is read again
+const permissionReloadInterval = time.Minute
+
+// CommandPermission allows or denies a slash command to a Discord role, user
+// or channel, to linked DAO members or to everyone
+type CommandPermission struct {
+	ID        uint64  `gorm:"primaryKey;autoIncrement"`
+	Command   string  `gorm:"size:32;index"` // slash command name, or * for all
+	Effect    string  `gorm:"size:8"`        // allow or deny
+	Subject   string  `gorm:"size:16"`       // role, user, channel, dao_member or everyone
+	SubjectID string  `gorm:"size:64"`       // Discord ID; empty for dao_member and everyone
+	Active    bool    `gorm:"default:true"`
+	Note      *string `gorm:"size:255"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// PermissionRequest describes who invokes a command and where
+type PermissionRequest struct {
+	Command   string
+	GuildID   string
+	UserID    string
+	RoleIDs   []string
+	ChannelID string
+	ParentID  string // parent channel when invoked in a thread
+	// DefaultRoleID is the module's configured role. It is required unless
+	// allow rules for roles, users, DAO members or everyone name who may use
+	// the command instead.
+	DefaultRoleID string
+}
+
+// PermissionDecision is the outcome of evaluating a PermissionRequest
+type PermissionDecision struct {
+	Allowed bool
+	Reason  string // why the call was denied, shown to the user
+}
+
+// PermissionManager evaluates command permission rules, reloading them from
+// the database at most a minute after they change
+type PermissionManager struct {
+	db       *gorm.DB
+	rules    map[string][]CommandPermission
+	loadedAt time.Time
+	mu       sync.RWMutex
+}
+
+// NewPermissionManager creates a permission manager and loads the rules
+func NewPermissionManager(db *gorm.DB) (*PermissionManager, error) {
+	m := &PermissionManager{db: db}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reload reads the active rules from the database
+func (m *PermissionManager) Reload() error {
+	var rows []CommandPermission
+	if err := m.db.Where("active = ?", true).Order("id").Find(&rows).Error; err != nil {
+		return err
+	}
+
+	rules := make(map[string][]CommandPermission)
+	for _, row := range rows {
+		command := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(row.Command), "/"))
+		rules[command] = append(rules[command], row)
+	}
+
+	m.mu.Lock()
+	m.rules = rules
+	m.loadedAt = time.Now()
+	m.mu.Unlock()
+	return nil
+}
+
+// Rules returns the rules that apply to a command, including those for all
+// commands
+func (m *PermissionManager) Rules(command string) []CommandPermission {
+	m.mu.RLock()
+	stale := time.Since(m.loadedAt) > permissionReloadInterval
+	m.mu.RUnlock()
+	if stale {
+		if err := m.Reload(); err != nil {
+			log.Printf("permissions: reload failed, keeping previous rules: %v", err)
+			m.mu.Lock()
+			m.loadedAt = time.Now()
+			m.mu.Unlock()
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	command = strings.ToLower(command)
+	rules := append([]CommandPermission(nil), m.rules[command]...)
+	return append(rules, m.rules[PermissionAnyCommand]...)
+}
+
+// IsDaoMember reports whether a Discord user is linked to one of a guild's DAO
+// member addresses
+func (m *PermissionManager) IsDaoMember(guildID, discordID string) bool {
+	if discordID == "" {
+		return false
+	}
+	var count int64
+	if err := m.db.Model(&sharedgov.DaoMember{}).Where("guild_id = ? AND discord = ?", guildID, discordID).Count(&count).Error; err != nil {
+		log.Printf("permissions: DAO member lookup for %s failed: %v", discordID, err)
+		return false
+	}
+	return count > 0
+}
+
+// Evaluate applies the command's rules: any matching deny wins, channel
+// allow rules limit where the command works, and allow rules for roles,
+// users, DAO members or everyone decide who may use it. Without such rules
+// the request's default role is required.
+func (m *PermissionManager) Evaluate(req PermissionRequest) PermissionDecision {
+	rules := m.Rules(req.Command)
+
+	daoMember := -1 // looked up once, on first use
+	matches := func(rule CommandPermission) bool {
+		switch rule.Subject {
+		case PermissionSubjectEveryone:
+			return true
+		case PermissionSubjectUser:
+			return rule.SubjectID == req.UserID
+		case PermissionSubjectRole:
+			return containsRole(req.RoleIDs, rule.SubjectID)
+		case PermissionSubjectChannel:
+			return rule.SubjectID == req.ChannelID || (req.ParentID != "" && rule.SubjectID == req.ParentID)
+		case PermissionSubjectDaoMember:
+			if daoMember < 0 {
+				daoMember = 0
+				if m.IsDaoMember(req.GuildID, req.UserID) {
+					daoMember = 1
+				}
+			}
+			return daoMember == 1
+		}
+		return false
+	}
+
+	for _, rule := range rules {
+		if rule.Effect == PermissionDeny && matches(rule) {
+			return PermissionDecision{Reason: deniedReason(req.Command, rule.Subject)}
+		}
+	}
+
+	var channelRules, identityRules []CommandPermission
+	for _, rule := range rules {
+		if rule.Effect != PermissionAllow {
+			continue
+		}
+		if rule.Subject == PermissionSubjectChannel {
+			channelRules = append(channelRules, rule)
+		} else {
+			identityRules = append(identityRules, rule)
+		}
+	}
+
+	if len(channelRules) > 0 && !anyRuleMatches(channelRules, matches) {
+		return PermissionDecision{Reason: fmt.Sprintf("/%s can't be used in this channel.", req.Command)}
+	}
+
+	if len(identityRules) > 0 {
+		if !anyRuleMatches(identityRules, matches) {
+			return PermissionDecision{Reason: restrictedReason(req.Command, identityRules)}
+		}
+		return PermissionDecision{Allowed: true}
+	}
+
+	if req.DefaultRoleID != "" && !containsRole(req.RoleIDs, req.DefaultRoleID) {
+		return PermissionDecision{Reason: fmt.Sprintf("/%s requires a role you don't have.", req.Command)}
+	}
+
+	return PermissionDecision{Allowed: true}
+}
+
+func anyRuleMatches(rules []CommandPermission, matches func(CommandPermission) bool) bool {
+	for _, rule := range rules {
+		if matches(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+func deniedReason(command, subject string) string {
+	switch subject {
+	case PermissionSubjectUser:
+		return fmt.Sprintf("You are not allowed to use /%s.", command)
+	case PermissionSubjectRole:
+		return fmt.Sprintf("One of your roles is not allowed to use /%s.", command)
+	case PermissionSubjectChannel:
+		return fmt.Sprintf("/%s is disabled in this channel.", command)
+	case PermissionSubjectDaoMember:
+		return fmt.Sprintf("/%s is not available to DAO members.", command)
+	default:
+		return fmt.Sprintf("/%s is disabled.", command)
+	}
+}
+
+// restrictedReason names who a command is limited to
+func restrictedReason(command string, rules []CommandPermission) string {
+	var who []string
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if seen[rule.Subject] {
+			continue
+		}
+		seen[rule.Subject] = true
+		switch rule.Subject {
+		case PermissionSubjectDaoMember:
+			who = append(who, "DAO members")
+		case PermissionSubjectRole:
+			who = append(who, "members with an allowed role")
+		case PermissionSubjectUser:
+			who = append(who, "selected users")
+		}
+	}
+
+	if len(who) == 0 {
+		return fmt.Sprintf("/%s is restricted.", command)
+	}
+	return fmt.Sprintf("/%s is limited to %s.", command, strings.Join(who, " and "))
+}
+
+func containsRole(roles []string, roleID string) bool {
+	for _, role := range roles {
+		if role == roleID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,214 @@
+package discord
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testGuild      = "100000000000000001"
+	testOtherGuild = "100000000000000002"
+	testUser       = "200000000000000001"
+	testFeedback   = "300000000000000001" // the module's default role
+	testOtherRole  = "300000000000000002"
+	testQARole     = "300000000000000003"
+	testChannel    = "400000000000000001"
+	testThread     = "400000000000000002"
+)
+
+// newTestPermissionManager returns a manager holding rules, whose DAO member
+// lookups find the guild/user pairs in members
+func newTestPermissionManager(t *testing.T, rules []CommandPermission, members map[string]bool) *PermissionManager {
+	t.Helper()
+	db := openStubDB(t, func(query string, args []driver.Value) (stubResult, error) {
+		if !strings.Contains(query, "FROM `dao_members`") || len(args) != 2 {
+			return stubResult{}, fmt.Errorf("unexpected query %q", query)
+		}
+		count := int64(0)
+		if members[fmt.Sprintf("%v/%v", args[0], args[1])] {
+			count = 1
+		}
+		return stubResult{columns: []string{"count(*)"}, rows: [][]driver.Value{{count}}}, nil
+	})
+
+	byCommand := make(map[string][]CommandPermission)
+	for _, rule := range rules {
+		byCommand[rule.Command] = append(byCommand[rule.Command], rule)
+	}
+	return &PermissionManager{db: db, rules: byCommand, loadedAt: time.Now()}
+}
+
+func TestPermissionManagerEvaluate(t *testing.T) {
+	allowDao := CommandPermission{Command: "feedback", Effect: PermissionAllow, Subject: PermissionSubjectDaoMember}
+	allowEveryone := CommandPermission{Command: "feedback", Effect: PermissionAllow, Subject: PermissionSubjectEveryone}
+	allowOtherRole := CommandPermission{Command: "feedback", Effect: PermissionAllow, Subject: PermissionSubjectRole, SubjectID: testOtherRole}
+	allowChannel := CommandPermission{Command: "feedback", Effect: PermissionAllow, Subject: PermissionSubjectChannel, SubjectID: testChannel}
+	denyUser := CommandPermission{Command: "feedback", Effect: PermissionDeny, Subject: PermissionSubjectUser, SubjectID: testUser}
+	denyAllEverywhere := CommandPermission{Command: PermissionAnyCommand, Effect: PermissionDeny, Subject: PermissionSubjectChannel, SubjectID: testChannel}
+
+	member := map[string]bool{testGuild + "/" + testUser: true}
+
+	tests := []struct {
+		name        string
+		rules       []CommandPermission
+		members     map[string]bool
+		guildID     string
+		roles       []string
+		channelID   string
+		parentID    string
+		defaultRole string
+		allowed     bool
+		reason      string
+	}{
+		{
+			name:        "no rules, default role held",
+			roles:       []string{testFeedback},
+			defaultRole: testFeedback,
+			allowed:     true,
+		},
+		{
+			name:        "no rules, default role missing",
+			defaultRole: testFeedback,
+			reason:      "requires a role",
+		},
+		{
+			name:    "no rules, no default role",
+			allowed: true,
+		},
+		{
+			name:        "DAO member with the default role",
+			rules:       []CommandPermission{allowDao},
+			members:     member,
+			roles:       []string{testFeedback},
+			defaultRole: testFeedback,
+			allowed:     true,
+		},
+		{
+			name:        "DAO member without the default role",
+			rules:       []CommandPermission{allowDao},
+			members:     member,
+			defaultRole: testFeedback,
+			allowed:     true,
+		},
+		{
+			name:        "default role but not a DAO member",
+			rules:       []CommandPermission{allowDao},
+			roles:       []string{testFeedback},
+			defaultRole: testFeedback,
+			reason:      "limited to DAO members.",
+		},
+		{
+			name:        "DAO member of another guild",
+			rules:       []CommandPermission{allowDao},
+			members:     member,
+			guildID:     testOtherGuild,
+			roles:       []string{testFeedback},
+			defaultRole: testFeedback,
+			reason:      "limited to DAO members.",
+		},
+		{
+			name:        "everyone rule replaces the default role",
+			rules:       []CommandPermission{allowEveryone},
+			defaultRole: testFeedback,
+			allowed:     true,
+		},
+		{
+			name:    "everyone rule without a default role",
+			rules:   []CommandPermission{allowEveryone},
+			allowed: true,
+		},
+		{
+			name:        "role rule replaces the default role",
+			rules:       []CommandPermission{allowOtherRole},
+			roles:       []string{testFeedback},
+			defaultRole: testFeedback,
+			reason:      "members with an allowed role",
+		},
+		{
+			name:        "allowed role without the default role",
+			rules:       []CommandPermission{allowOtherRole},
+			roles:       []string{testOtherRole},
+			defaultRole: testFeedback,
+			allowed:     true,
+		},
+		{
+			name:        "either identity rule matches",
+			rules:       []CommandPermission{allowOtherRole, allowDao},
+			members:     member,
+			roles:       []string{testFeedback},
+			defaultRole: testFeedback,
+			allowed:     true,
+		},
+		{
+			name:    "deny beats allow",
+			rules:   []CommandPermission{allowEveryone, denyUser},
+			reason:  "You are not allowed",
+			allowed: false,
+		},
+		{
+			name:      "channel rule outside the channel",
+			rules:     []CommandPermission{allowChannel},
+			channelID: "499999999999999999",
+			reason:    "can't be used in this channel",
+		},
+		{
+			name:      "channel rule in a thread of the channel",
+			rules:     []CommandPermission{allowChannel},
+			channelID: testThread,
+			parentID:  testChannel,
+			allowed:   true,
+		},
+		{
+			name:      "deny for every command in a channel",
+			rules:     []CommandPermission{allowEveryone, denyAllEverywhere},
+			channelID: testChannel,
+			reason:    "disabled in this channel",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestPermissionManager(t, tt.rules, tt.members)
+			guildID := tt.guildID
+			if guildID == "" {
+				guildID = testGuild
+			}
+			got := m.Evaluate(PermissionRequest{
+				Command:       "feedback",
+				GuildID:       guildID,
+				UserID:        testUser,
+				RoleIDs:       tt.roles,
+				ChannelID:     tt.channelID,
+				ParentID:      tt.parentID,
+				DefaultRoleID: tt.defaultRole,
+			})
+			if got.Allowed != tt.allowed {
+				t.Fatalf("Allowed = %v (%q), want %v", got.Allowed, got.Reason, tt.allowed)
+			}
+			if !strings.Contains(got.Reason, tt.reason) {
+				t.Fatalf("Reason = %q, want it to contain %q", got.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+// The seeded everyone rule opens /question to members without the QA role,
+// while other commands keep requiring their default role
+func TestEveryoneRuleOpensQuestion(t *testing.T) {
+	m := newTestPermissionManager(t, []CommandPermission{
+		{Command: "question", Effect: PermissionAllow, Subject: PermissionSubjectEveryone},
+	}, nil)
+
+	question := m.Evaluate(PermissionRequest{Command: "question", GuildID: testGuild, UserID: testUser, DefaultRoleID: testQARole})
+	if !question.Allowed {
+		t.Errorf("/question without the QA role denied: %q", question.Reason)
+	}
+
+	summary := m.Evaluate(PermissionRequest{Command: "summary", GuildID: testGuild, UserID: testUser, DefaultRoleID: testQARole})
+	if summary.Allowed || !strings.Contains(summary.Reason, "requires a role") {
+		t.Errorf("/summary without the QA role = %+v, want the default role required", summary)
+	}
+}
@@ -0,0 +1,407 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+	"gorm.io/gorm"
+)
+
+// Scopes a command quota counts usage over
+const (
+	QuotaScopeUser   = "user"   // each user's own usage; SubjectID names one user or is empty for everyone
+	QuotaScopeRole   = "role"   // each user's own usage, for holders of the SubjectID role
+	QuotaScopeGuild  = "guild"  // all usage in the invoking guild
+	QuotaScopeGlobal = "global" // all usage
+)
+
+// QuotaAnyCommand is the command name of quotas shared by every AI-backed
+// command
+const QuotaAnyCommand = "*"
+
+// quotaReloadInterval is how long loaded rules are used before the
+// command_quotas table is read again
+const quotaReloadInterval = time.Minute
+
+// defaultTokenEstimate is assumed for metered commands missing from
+// commandTokenEstimates
+const defaultTokenEstimate = 10000
+
+// commandTokenEstimates is the rough number of AI tokens one invocation of a
+// command consumes, prompt and completion together. A call is refused when
+// its estimate would take the tokens recorded in the window over a limit.
+// /refresh runs the claim and team research as well as the summary.
+var commandTokenEstimates = map[string]int{
+	"question": 8000,
+	"summary":  15000,
+	"refresh":  80000,
+	"report":   30000,
+}
+
+// EstimatedCommandTokens returns the tokens one invocation of a command is
+// expected to consume
+func EstimatedCommandTokens(command string) int {
+	if tokens, ok := commandTokenEstimates[strings.ToLower(command)]; ok {
+		return tokens
+	}
+	return defaultTokenEstimate
+}
+
+// CommandQuota limits how often an AI-backed command may be used within a
+// sliding window and how many AI tokens it may consume, as recorded in the
+// ai_token_usages ledger. Zero limits are not enforced.
+type CommandQuota struct {
+	ID              uint64  `gorm:"primaryKey;autoIncrement"`
+	Command         string  `gorm:"size:32;index"` // slash command name, or * for all metered commands
+	Scope           string  `gorm:"size:16"`       // user, role, guild or global
+	SubjectID       string  `gorm:"size:64"`       // Discord user or role ID for user and role scopes
+	WindowMinutes   uint32  // length of the sliding window
+	MaxRequests     uint32  // invocations allowed within the window
+	MaxTokens       uint64  // AI tokens allowed within the window
+	CooldownSeconds uint32  // minimum time between invocations
+	Active          bool    `gorm:"default:true"`
+	Note            *string `gorm:"size:255"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// AIUsage is a ledger entry for one accepted invocation of an AI-backed command
+type AIUsage struct {
+	ID              uint64 `gorm:"primaryKey;autoIncrement"`
+	Command         string `gorm:"size:32;index:idx_ai_usage_command"`
+	UserID          string `gorm:"size:64;index:idx_ai_usage_user"`
+	GuildID         string `gorm:"size:64;index:idx_ai_usage_guild"`
+	ChannelID       string `gorm:"size:64"`
+	EstimatedTokens uint32
+	CreatedAt       time.Time `gorm:"index"`
+}
+
+// QuotaRequest describes who invokes a metered command and where
+type QuotaRequest struct {
+	Command   string
+	UserID    string
+	RoleIDs   []string
+	GuildID   string
+	ChannelID string
+}
+
+// QuotaDecision is the outcome of checking a QuotaRequest
+type QuotaDecision struct {
+	Allowed bool
+	Reason  string    // why the call was refused, shown to the user
+	RetryAt time.Time // when the call would next be accepted, if known
+}
+
+// QuotaManager enforces command quotas against the usage ledger, reloading
+// the rules from the database at most a minute after they change
+type QuotaManager struct {
+	db       *gorm.DB
+	rules    map[string][]CommandQuota
+	loadedAt time.Time
+	mu       sync.RWMutex
+	reserve  sync.Mutex // serialises check-and-record so bursts can't overrun a limit
+}
+
+// NewQuotaManager creates a quota manager and loads the rules
+func NewQuotaManager(db *gorm.DB) (*QuotaManager, error) {
+	m := &QuotaManager{db: db}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reload reads the active rules from the database
+func (m *QuotaManager) Reload() error {
+	var rows []CommandQuota
+	if err := m.db.Where("active = ?", true).Order("id").Find(&rows).Error; err != nil {
+		return err
+	}
+
+	rules := make(map[string][]CommandQuota)
+	for _, row := range rows {
+		command := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(row.Command), "/"))
+		rules[command] = append(rules[command], row)
+	}
+
+	m.mu.Lock()
+	m.rules = rules
+	m.loadedAt = time.Now()
+	m.mu.Unlock()
+	return nil
+}
+
+// Rules returns the quotas that apply to a command, including those for all
+// metered commands
+func (m *QuotaManager) Rules(command string) []CommandQuota {
+	m.mu.RLock()
+	stale := time.Since(m.loadedAt) > quotaReloadInterval
+	m.mu.RUnlock()
+	if stale {
+		if err := m.Reload(); err != nil {
+			log.Printf("quotas: reload failed, keeping previous rules: %v", err)
+			m.mu.Lock()
+			m.loadedAt = time.Now()
+			m.mu.Unlock()
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	command = strings.ToLower(command)
+	rules := append([]CommandQuota(nil), m.rules[command]...)
+	if command != QuotaAnyCommand {
+		rules = append(rules, m.rules[QuotaAnyCommand]...)
+	}
+	return rules
+}
+
+// Reserve checks the quotas that apply to a request and, when they all have
+// room, records the invocation in the usage ledger. Token limits count the
+// tokens the model requests actually used. Per-user limits come from the
+// most specific rules: a user's own rules, else the rules of their roles (the
+// most generous role wins), else the rules for everyone. Guild and global
+// limits always apply. Ledger failures are logged and let the call through
+// rather than block commands.
+func (m *QuotaManager) Reserve(req QuotaRequest) QuotaDecision {
+	req.Command = strings.ToLower(req.Command)
+	tokens := EstimatedCommandTokens(req.Command)
+
+	m.reserve.Lock()
+	defer m.reserve.Unlock()
+
+	now := time.Now()
+	for _, check := range m.applicableGroups(req) {
+		decision, ok := m.checkGroups(check, req, tokens, now)
+		if !ok {
+			return decision
+		}
+	}
+
+	usage := AIUsage{
+		Command:         req.Command,
+		UserID:          req.UserID,
+		GuildID:         req.GuildID,
+		ChannelID:       req.ChannelID,
+		EstimatedTokens: uint32(tokens),
+		CreatedAt:       now,
+	}
+	if err := m.db.Create(&usage).Error; err != nil {
+		log.Printf("quotas: failed to record /%s usage for %s: %v", req.Command, req.UserID, err)
+	}
+	return QuotaDecision{Allowed: true}
+}
+
+// applicableGroups returns the checks to enforce. Each check is a list of
+// alternative rule groups: it passes when every rule of any one group has
+// room.
+func (m *QuotaManager) applicableGroups(req QuotaRequest) [][][]CommandQuota {
+	var ownRules, everyoneRules, sharedRules []CommandQuota
+	roleRules := make(map[string][]CommandQuota)
+	var roleOrder []string
+
+	for _, rule := range m.Rules(req.Command) {
+		switch rule.Scope {
+		case QuotaScopeUser:
+			if rule.SubjectID == "" {
+				everyoneRules = append(everyoneRules, rule)
+			} else if rule.SubjectID == req.UserID {
+				ownRules = append(ownRules, rule)
+			}
+		case QuotaScopeRole:
+			if containsRole(req.RoleIDs, rule.SubjectID) {
+				if _, seen := roleRules[rule.SubjectID]; !seen {
+					roleOrder = append(roleOrder, rule.SubjectID)
+				}
+				roleRules[rule.SubjectID] = append(roleRules[rule.SubjectID], rule)
+			}
+		case QuotaScopeGuild, QuotaScopeGlobal:
+			sharedRules = append(sharedRules, rule)
+		}
+	}
+
+	var checks [][][]CommandQuota
+	switch {
+	case len(ownRules) > 0:
+		checks = append(checks, [][]CommandQuota{ownRules})
+	case len(roleOrder) > 0:
+		var groups [][]CommandQuota
+		for _, role := range roleOrder {
+			groups = append(groups, roleRules[role])
+		}
+		checks = append(checks, groups)
+	case len(everyoneRules) > 0:
+		checks = append(checks, [][]CommandQuota{everyoneRules})
+	}
+	if len(sharedRules) > 0 {
+		checks = append(checks, [][]CommandQuota{sharedRules})
+	}
+	return checks
+}
+
+// checkGroups passes when any group has room for the request; otherwise it
+// reports the limit that frees up soonest
+func (m *QuotaManager) checkGroups(groups [][]CommandQuota, req QuotaRequest, tokens int, now time.Time) (QuotaDecision, bool) {
+	var best QuotaDecision
+	for _, group := range groups {
+		decision, ok := m.checkGroup(group, req, tokens, now)
+		if ok {
+			return QuotaDecision{Allowed: true}, true
+		}
+		if best.Reason == "" || (!decision.RetryAt.IsZero() && (best.RetryAt.IsZero() || decision.RetryAt.Before(best.RetryAt))) {
+			best = decision
+		}
+	}
+	return best, false
+}
+
+func (m *QuotaManager) checkGroup(group []CommandQuota, req QuotaRequest, tokens int, now time.Time) (QuotaDecision, bool) {
+	for _, rule := range group {
+		if decision, ok := m.checkRule(rule, req, tokens, now); !ok {
+			return decision, false
+		}
+	}
+	return QuotaDecision{Allowed: true}, true
+}
+
+// checkRule counts the ledger entries a rule covers and compares them with
+// its limits. Invocations and cooldowns come from ai_usages, tokens from
+// ai_token_usages.
+func (m *QuotaManager) checkRule(rule CommandQuota, req QuotaRequest, tokens int, now time.Time) (QuotaDecision, bool) {
+	scoped := func(ledger interface{}) *gorm.DB {
+		query := m.db.Model(ledger)
+		if rule.Command != QuotaAnyCommand {
+			query = query.Where("command = ?", req.Command)
+		}
+		switch rule.Scope {
+		case QuotaScopeUser, QuotaScopeRole:
+			query = query.Where("user_id = ?", req.UserID)
+		case QuotaScopeGuild:
+			query = query.Where("guild_id = ?", req.GuildID)
+		}
+		return query
+	}
+	subject := quotaSubject(rule, req.Command)
+
+	if rule.CooldownSeconds > 0 {
+		cooldown := time.Duration(rule.CooldownSeconds) * time.Second
+		var last AIUsage
+		err := scoped(&AIUsage{}).Where("created_at > ?", now.Add(-cooldown)).Order("created_at DESC").Limit(1).Take(&last).Error
+		switch {
+		case err == nil:
+			retry := last.CreatedAt.Add(cooldown)
+			return QuotaDecision{
+				Reason:  fmt.Sprintf("%s is cooling down. Try again in %s.", subject, formatQuotaWait(retry.Sub(now))),
+				RetryAt: retry,
+			}, false
+		case err != gorm.ErrRecordNotFound:
+			log.Printf("quotas: cooldown lookup for rule %d failed: %v", rule.ID, err)
+		}
+	}
+
+	if rule.WindowMinutes == 0 || (rule.MaxRequests == 0 && rule.MaxTokens == 0) {
+		return QuotaDecision{Allowed: true}, true
+	}
+
+	window := time.Duration(rule.WindowMinutes) * time.Minute
+	since := now.Add(-window)
+	var reason string
+	var ledger interface{}
+	if rule.MaxRequests > 0 {
+		var requests int64
+		if err := scoped(&AIUsage{}).Where("created_at > ?", since).Count(&requests).Error; err != nil {
+			log.Printf("quotas: usage lookup for rule %d failed: %v", rule.ID, err)
+			return QuotaDecision{Allowed: true}, true
+		}
+		if requests+1 > int64(rule.MaxRequests) {
+			reason = fmt.Sprintf("%s is limited to %d uses per %s.", subject, rule.MaxRequests, formatQuotaWindow(window))
+			ledger = &AIUsage{}
+		}
+	}
+	if reason == "" && rule.MaxTokens > 0 {
+		var used int64
+		if err := scoped(&sharedgov.AITokenUsage{}).Where("created_at > ?", since).
+			Select("COALESCE(SUM(input_tokens + output_tokens), 0)").
+			Scan(&used).Error; err != nil {
+			log.Printf("quotas: token lookup for rule %d failed: %v", rule.ID, err)
+			return QuotaDecision{Allowed: true}, true
+		}
+		if used+int64(tokens) > int64(rule.MaxTokens) {
+			reason = fmt.Sprintf("%s has used its AI budget for the last %s.", subject, formatQuotaWindow(window))
+			ledger = &sharedgov.AITokenUsage{}
+		}
+	}
+	if reason == "" {
+		return QuotaDecision{Allowed: true}, true
+	}
+
+	// The oldest entry in the window is the next to expire
+	decision := QuotaDecision{Reason: reason}
+	var oldest struct{ CreatedAt time.Time }
+	if err := scoped(ledger).Select("created_at").Where("created_at > ?", since).Order("created_at ASC").Limit(1).Take(&oldest).Error; err == nil {
+		decision.RetryAt = oldest.CreatedAt.Add(window)
+		decision.Reason += fmt.Sprintf(" Try again in %s.", formatQuotaWait(decision.RetryAt.Sub(now)))
+	}
+	return decision, false
+}
+
+// quotaSubject names whose usage a rule limits, for the refusal message
+func quotaSubject(rule CommandQuota, command string) string {
+	what := "/" + command
+	if rule.Command == QuotaAnyCommand {
+		what = "AI commands"
+	}
+	switch rule.Scope {
+	case QuotaScopeGuild:
+		return "This server's use of " + what
+	case QuotaScopeGlobal:
+		return "Use of " + what
+	default:
+		return "Your use of " + what
+	}
+}
+
+func formatQuotaWindow(window time.Duration) string {
+	switch {
+	case window%(24*time.Hour) == 0:
+		if days := int(window / (24 * time.Hour)); days != 1 {
+			return fmt.Sprintf("%d days", days)
+		}
+		return "day"
+	case window%time.Hour == 0:
+		if hours := int(window / time.Hour); hours != 1 {
+			return fmt.Sprintf("%d hours", hours)
+		}
+		return "hour"
+	default:
+		if minutes := int(window / time.Minute); minutes != 1 {
+			return fmt.Sprintf("%d minutes", minutes)
+		}
+		return "minute"
+	}
+}
+
+func formatQuotaWait(wait time.Duration) string {
+	if wait < time.Minute {
+		seconds := int(wait.Round(time.Second) / time.Second)
+		if seconds < 1 {
+			seconds = 1
+		}
+		return fmt.Sprintf("%ds", seconds)
+	}
+	wait = wait.Round(time.Minute)
+	hours := int(wait / time.Hour)
+	minutes := int((wait % time.Hour) / time.Minute)
+	switch {
+	case hours >= 24:
+		return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
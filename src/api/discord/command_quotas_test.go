@@ -0,0 +1,234 @@
+package discord
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ledgerEntry is a row of ai_usages, or of ai_token_usages when tokens is set
+type ledgerEntry struct {
+	command string
+	userID  string
+	guildID string
+	tokens  int64
+	age     time.Duration
+}
+
+var stubCondition = regexp.MustCompile("`?(\\w+)`? (=|>) \\?")
+
+// stubLedger answers the quota manager's queries from entries, recording the
+// invocations it reserves
+type stubLedger struct {
+	now      time.Time
+	entries  []ledgerEntry
+	reserved int
+}
+
+func (l *stubLedger) answer(query string, args []driver.Value) (stubResult, error) {
+	if strings.HasPrefix(query, "INSERT INTO `ai_usages`") {
+		l.reserved++
+		return stubResult{affected: 1}, nil
+	}
+
+	tokenLedger := strings.Contains(query, "FROM `ai_token_usages`")
+	if !tokenLedger && !strings.Contains(query, "FROM `ai_usages`") {
+		return stubResult{}, fmt.Errorf("unexpected query %q", query)
+	}
+	if strings.HasSuffix(query, "LIMIT ?") {
+		args = args[:len(args)-1]
+	}
+	conditions := stubCondition.FindAllStringSubmatch(query, -1)
+	if len(conditions) != len(args) {
+		return stubResult{}, fmt.Errorf("query %q has %d conditions for %d args", query, len(conditions), len(args))
+	}
+
+	var matched []ledgerEntry
+	for _, entry := range l.entries {
+		if (entry.tokens > 0) != tokenLedger {
+			continue
+		}
+		match := true
+		for i, cond := range conditions {
+			switch cond[1] {
+			case "command":
+				match = match && entry.command == args[i]
+			case "user_id":
+				match = match && entry.userID == args[i]
+			case "guild_id":
+				match = match && entry.guildID == args[i]
+			case "created_at":
+				match = match && l.now.Add(-entry.age).After(args[i].(time.Time))
+			default:
+				return stubResult{}, fmt.Errorf("unexpected condition %q", cond[0])
+			}
+		}
+		if match {
+			matched = append(matched, entry)
+		}
+	}
+
+	switch {
+	case strings.Contains(query, "count(*)"):
+		return stubResult{columns: []string{"count(*)"}, rows: [][]driver.Value{{int64(len(matched))}}}, nil
+	case strings.Contains(query, "SUM("):
+		var sum int64
+		for _, entry := range matched {
+			sum += entry.tokens
+		}
+		return stubResult{columns: []string{"total"}, rows: [][]driver.Value{{sum}}}, nil
+	}
+
+	// The newest or oldest entry, by created_at
+	if len(matched) == 0 {
+		return stubResult{columns: []string{"created_at"}}, nil
+	}
+	pick := matched[0]
+	for _, entry := range matched[1:] {
+		if (entry.age < pick.age) == strings.Contains(query, "DESC") {
+			pick = entry
+		}
+	}
+	return stubResult{columns: []string{"created_at"}, rows: [][]driver.Value{{l.now.Add(-pick.age)}}}, nil
+}
+
+func TestQuotaManagerReserve(t *testing.T) {
+	userRule := func(subjectID string, maxRequests uint32) CommandQuota {
+		return CommandQuota{Command: "question", Scope: QuotaScopeUser, SubjectID: subjectID, WindowMinutes: 60, MaxRequests: maxRequests}
+	}
+	roleRule := CommandQuota{Command: "question", Scope: QuotaScopeRole, SubjectID: testOtherRole, WindowMinutes: 60, MaxRequests: 5}
+	guildTokens := CommandQuota{Command: QuotaAnyCommand, Scope: QuotaScopeGuild, WindowMinutes: 60, MaxTokens: 50000}
+	cooldown := CommandQuota{Command: "question", Scope: QuotaScopeUser, CooldownSeconds: 300}
+
+	used := func(n int, user string) []ledgerEntry {
+		var entries []ledgerEntry
+		for i := 0; i < n; i++ {
+			entries = append(entries, ledgerEntry{command: "question", userID: user, guildID: testGuild, age: time.Duration(10+i) * time.Minute})
+		}
+		return entries
+	}
+	const otherUser = "200000000000000002"
+
+	tests := []struct {
+		name    string
+		rules   []CommandQuota
+		entries []ledgerEntry
+		roles   []string
+		allowed bool
+		reason  string
+	}{
+		{
+			name:    "no rules",
+			allowed: true,
+		},
+		{
+			name:    "under the everyone limit",
+			rules:   []CommandQuota{userRule("", 2)},
+			entries: used(1, testUser),
+			allowed: true,
+		},
+		{
+			name:    "at the everyone limit",
+			rules:   []CommandQuota{userRule("", 2)},
+			entries: used(2, testUser),
+			reason:  "limited to 2 uses per hour. Try again in 49m.",
+		},
+		{
+			name:    "other users' uses don't count",
+			rules:   []CommandQuota{userRule("", 2)},
+			entries: used(2, otherUser),
+			allowed: true,
+		},
+		{
+			name:    "old uses have left the window",
+			rules:   []CommandQuota{userRule("", 1)},
+			entries: []ledgerEntry{{command: "question", userID: testUser, age: 2 * time.Hour}},
+			allowed: true,
+		},
+		{
+			name:    "own rule beats the everyone rule",
+			rules:   []CommandQuota{userRule("", 2), userRule(testUser, 5)},
+			entries: used(3, testUser),
+			allowed: true,
+		},
+		{
+			name:    "role rule beats the everyone rule",
+			rules:   []CommandQuota{userRule("", 2), roleRule},
+			entries: used(3, testUser),
+			roles:   []string{testOtherRole},
+			allowed: true,
+		},
+		{
+			name:    "role rule needs the role",
+			rules:   []CommandQuota{userRule("", 2), roleRule},
+			entries: used(3, testUser),
+			reason:  "limited to 2 uses per hour",
+		},
+		{
+			name:    "cooling down",
+			rules:   []CommandQuota{cooldown},
+			entries: []ledgerEntry{{command: "question", userID: testUser, age: time.Minute}},
+			reason:  "cooling down. Try again in 4m.",
+		},
+		{
+			name:    "cooled down",
+			rules:   []CommandQuota{cooldown},
+			entries: []ledgerEntry{{command: "question", userID: testUser, age: 6 * time.Minute}},
+			allowed: true,
+		},
+		{
+			name:  "guild token budget counts recorded tokens",
+			rules: []CommandQuota{guildTokens},
+			entries: []ledgerEntry{
+				{command: "refresh", userID: otherUser, guildID: testGuild, tokens: 45000, age: 30 * time.Minute},
+			},
+			reason: "This server's use of AI commands has used its AI budget for the last hour. Try again in 30m.",
+		},
+		{
+			name:  "guild token budget with room",
+			rules: []CommandQuota{guildTokens},
+			entries: []ledgerEntry{
+				{command: "refresh", userID: otherUser, guildID: testGuild, tokens: 30000, age: 30 * time.Minute},
+			},
+			allowed: true,
+		},
+		{
+			name:  "another guild's tokens don't count",
+			rules: []CommandQuota{guildTokens},
+			entries: []ledgerEntry{
+				{command: "refresh", userID: otherUser, guildID: testOtherGuild, tokens: 40000, age: 30 * time.Minute},
+			},
+			allowed: true,
+		},
+		{
+			name:    "guild limits apply alongside user limits",
+			rules:   []CommandQuota{userRule(testUser, 5), guildTokens},
+			entries: []ledgerEntry{{command: "question", userID: testUser, guildID: testGuild, tokens: 45000, age: time.Minute}},
+			reason:  "used its AI budget",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger := &stubLedger{now: time.Now(), entries: tt.entries}
+			m := &QuotaManager{db: openStubDB(t, ledger.answer), rules: map[string][]CommandQuota{}, loadedAt: time.Now()}
+			for _, rule := range tt.rules {
+				m.rules[rule.Command] = append(m.rules[rule.Command], rule)
+			}
+
+			got := m.Reserve(QuotaRequest{Command: "question", UserID: testUser, RoleIDs: tt.roles, GuildID: testGuild, ChannelID: testChannel})
+			if got.Allowed != tt.allowed {
+				t.Fatalf("Allowed = %v (%q), want %v", got.Allowed, got.Reason, tt.allowed)
+			}
+			if !strings.Contains(got.Reason, tt.reason) {
+				t.Fatalf("Reason = %q, want it to contain %q", got.Reason, tt.reason)
+			}
+			if want := map[bool]int{true: 1, false: 0}[tt.allowed]; ledger.reserved != want {
+				t.Fatalf("reserved %d invocations, want %d", ledger.reserved, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// CheckCommandPermission evaluates the command permission rules for a slash
+// command interaction. defaultRoleID is the module's configured role, which
+// is required unless allow rules name who may use the command. Without a
+// permission manager only the default role is checked.
+func CheckCommandPermission(s *discordgo.Session, permissions *PermissionManager, guildID string, i *discordgo.InteractionCreate, defaultRoleID string) PermissionDecision {
+	return CheckActionPermission(s, permissions, guildID, i, i.ApplicationCommandData().Name, defaultRoleID)
+}
+
+// CheckActionPermission evaluates the permission rules stored under an
+// action name, such as a button, for any kind of interaction
+func CheckActionPermission(s *discordgo.Session, permissions *PermissionManager, guildID string, i *discordgo.InteractionCreate, command, defaultRoleID string) PermissionDecision {
+	userID, roles := interactionInvoker(i)
+	return checkPermission(s, permissions, guildID, userID, roles, i.ChannelID, command, defaultRoleID)
+}
+
+// CheckMessagePermission evaluates the permission rules stored under an
+// action name for the author of a guild message
+func CheckMessagePermission(s *discordgo.Session, permissions *PermissionManager, guildID string, m *discordgo.Message, command, defaultRoleID string) PermissionDecision {
+	var userID string
+	var roles []string
+	if m.Author != nil {
+		userID = m.Author.ID
+	}
+	if m.Member != nil {
+		roles = m.Member.Roles
+	}
+	return checkPermission(s, permissions, guildID, userID, roles, m.ChannelID, command, defaultRoleID)
+}
+
+func checkPermission(s *discordgo.Session, permissions *PermissionManager, guildID, userID string, roles []string, channelID, command, defaultRoleID string) PermissionDecision {
+	if permissions == nil {
+		if defaultRoleID != "" && (userID == "" || !HasRole(s, guildID, userID, defaultRoleID)) {
+			return PermissionDecision{Reason: "You don't have permission to use this command."}
+		}
+		return PermissionDecision{Allowed: true}
+	}
+
+	req := PermissionRequest{
+		Command:       command,
+		GuildID:       guildID,
+		UserID:        userID,
+		RoleIDs:       roles,
+		ChannelID:     channelID,
+		DefaultRoleID: defaultRoleID,
+	}
+	if channel := lookupChannel(s, channelID); channel != nil && channel.IsThread() {
+		req.ParentID = channel.ParentID
+	}
+	return permissions.Evaluate(req)
+}
+
+// CheckCommandQuota reserves a use of an AI-backed command against the
+// configured quotas. Without a quota manager every call is allowed.
+func CheckCommandQuota(quotas *QuotaManager, i *discordgo.InteractionCreate) QuotaDecision {
+	if quotas == nil {
+		return QuotaDecision{Allowed: true}
+	}
+
+	userID, roles := interactionInvoker(i)
+	return quotas.Reserve(QuotaRequest{
+		Command:   i.ApplicationCommandData().Name,
+		UserID:    userID,
+		RoleIDs:   roles,
+		GuildID:   i.GuildID,
+		ChannelID: i.ChannelID,
+	})
+}
+
+// RespondPermissionDenied tells the user why a command was refused
+func RespondPermissionDenied(s *discordgo.Session, interaction *discordgo.Interaction, title, reason string) error {
+	return InteractionRespondNoEmbed(s, interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: FormatStyledBlock(title, reason),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func interactionInvoker(i *discordgo.InteractionCreate) (string, []string) {
+	switch {
+	case i.Member != nil && i.Member.User != nil:
+		return i.Member.User.ID, i.Member.Roles
+	case i.User != nil:
+		return i.User.ID, nil
+	}
+	return "", nil
+}
+
+func lookupChannel(s *discordgo.Session, channelID string) *discordgo.Channel {
+	if channelID == "" {
+		return nil
+	}
+	if s.State != nil {
+		if channel, err := s.State.Channel(channelID); err == nil {
+			return channel
+		}
+	}
+	channel, err := s.Channel(channelID)
+	if err != nil {
+		return nil
+	}
+	return channel
+}
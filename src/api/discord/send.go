@@ -43,6 +43,16 @@ func SendComplexMessageNoEmbed(s *discordgo.Session, channelID string, msg *disc
 	return s.ChannelMessageSendComplex(channelID, msg)
 }
 
+// ForumThreadStartNoEmbed opens a forum post whose first message is sanitized.
+func ForumThreadStartNoEmbed(s *discordgo.Session, channelID string, thread *discordgo.ThreadStart, msg *discordgo.MessageSend) (*discordgo.Channel, error) {
+	if thread == nil || msg == nil {
+		return nil, errors.New("discord: forum thread payload cannot be nil")
+	}
+
+	sanitizeMessageSend(msg)
+	return s.ForumThreadStartComplex(channelID, thread, msg)
+}
+
 // InteractionRespondNoEmbed wraps InteractionRespond ensuring the data content is sanitized.
 func InteractionRespondNoEmbed(s *discordgo.Session, interaction *discordgo.Interaction, resp *discordgo.InteractionResponse) error {
 	sanitizeInteractionResponse(resp)
@@ -10,12 +10,16 @@ import (
 )
 
 const (
-	CommandQuestion = "question"
-	CommandRefresh  = "refresh"
-	CommandContext  = "context"
-	CommandSummary  = "summary"
-	CommandFeedback = "feedback"
-	CommandReport   = "report"
+	CommandQuestion         = "question"
+	CommandRefresh          = "refresh"
+	CommandContext          = "context"
+	CommandSummary          = "summary"
+	CommandFeedback         = "feedback"
+	CommandFeedbackEdit     = "feedback-edit"
+	CommandFeedbackWithdraw = "feedback-withdraw"
+	CommandReport           = "report"
+	CommandVote             = "vote"
+	CommandLinkWallet       = "link-wallet"
 )
 
 var commandDefinitions = map[string]*discordgo.ApplicationCommand{
@@ -59,6 +63,95 @@ var commandDefinitions = map[string]*discordgo.ApplicationCommand{
 			},
 		},
 	},
+	CommandFeedbackEdit: {
+		Name:        CommandFeedbackEdit,
+		Description: "Edit the feedback posted for this referendum",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "message",
+				Description: "The new feedback message (10-5000 characters)",
+				Required:    true,
+			},
+		},
+	},
+	CommandFeedbackWithdraw: {
+		Name:        CommandFeedbackWithdraw,
+		Description: "Withdraw the feedback posted for this referendum from Polkassembly",
+	},
+	CommandVote: {
+		Name:        CommandVote,
+		Description: "Cast or change your DAO vote on this referendum",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "choice",
+				Description: "Your vote",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Aye", Value: "aye"},
+					{Name: "Nay", Value: "nay"},
+					{Name: "Abstain", Value: "abstain"},
+				},
+			},
+		},
+	},
+	CommandLinkWallet: {
+		Name:        CommandLinkWallet,
+		Description: "Prove you control an address and join the DAO with it",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "start",
+				Description: "Get the message to sign with your address",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "address",
+						Description: "SS58 address to link",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "verify",
+				Description: "Submit the signature of the link message",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "address",
+						Description: "SS58 address being linked",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "signature",
+						Description: "Hex signature (sr25519, ed25519 or ecdsa)",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "List the addresses linked to your account",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "revoke",
+				Description: "Unlink an address from your account",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "address",
+						Description: "SS58 address to unlink",
+						Required:    true,
+					},
+				},
+			},
+		},
+	},
 }
 
 var defaultCommandOrder = []string{
@@ -68,6 +161,9 @@ var defaultCommandOrder = []string{
 	CommandSummary,
 	CommandReport,
 	CommandFeedback,
+	CommandFeedbackEdit,
+	CommandVote,
+	CommandLinkWallet,
 }
 
 // RegisterSlashCommands registers the requested slash commands for a guild.
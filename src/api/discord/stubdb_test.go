@@ -0,0 +1,99 @@
+package discord
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// stubResult is what a stub database answers to one statement
+type stubResult struct {
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+}
+
+// stubAnswer answers a statement from its SQL and arguments
+type stubAnswer func(query string, args []driver.Value) (stubResult, error)
+
+// openStubDB returns a MySQL-dialect gorm connection whose statements are
+// answered by answer instead of a server
+func openStubDB(t *testing.T, answer stubAnswer) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sql.OpenDB(stubConnector{answer}),
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open stub database: %v", err)
+	}
+	return db
+}
+
+type stubConnector struct{ answer stubAnswer }
+
+func (c stubConnector) Connect(context.Context) (driver.Conn, error) { return stubConn(c), nil }
+func (c stubConnector) Driver() driver.Driver                        { return nil }
+
+type stubConn struct{ answer stubAnswer }
+
+func (c stubConn) Prepare(query string) (driver.Stmt, error) {
+	return stubStmt{answer: c.answer, query: query}, nil
+}
+func (c stubConn) Close() error              { return nil }
+func (c stubConn) Begin() (driver.Tx, error) { return c, nil }
+func (c stubConn) Commit() error             { return nil }
+func (c stubConn) Rollback() error           { return nil }
+
+type stubStmt struct {
+	answer stubAnswer
+	query  string
+}
+
+func (s stubStmt) Close() error  { return nil }
+func (s stubStmt) NumInput() int { return -1 }
+
+func (s stubStmt) Exec(args []driver.Value) (driver.Result, error) {
+	result, err := s.answer(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return stubExecResult(result.affected), nil
+}
+
+// stubExecResult reports no insert ID, so gorm leaves primary keys unset
+type stubExecResult int64
+
+func (r stubExecResult) LastInsertId() (int64, error) { return 0, nil }
+func (r stubExecResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+func (s stubStmt) Query(args []driver.Value) (driver.Rows, error) {
+	result, err := s.answer(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &stubRows{columns: result.columns, rows: result.rows}, nil
+}
+
+type stubRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *stubRows) Columns() []string { return r.columns }
+func (r *stubRows) Close() error      { return nil }
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
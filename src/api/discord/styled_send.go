@@ -78,14 +78,33 @@ func RespondStyledHeaderMessage(s *discordgo.Session, interaction *discordgo.Int
 	}, nil
 }
 
+// InteractionHeader returns a handle for the message an interaction was
+// already answered with, so later updates edit that reply.
+func InteractionHeader(s *discordgo.Session, interaction *discordgo.Interaction) (*HeaderHandle, error) {
+	handle := &HeaderHandle{
+		channelID:      interaction.ChannelID,
+		interaction:    interaction,
+		viaInteraction: true,
+	}
+	// The message ID is only needed once the interaction token expires, but
+	// it can no longer be looked up then
+	msg, err := s.InteractionResponse(interaction)
+	if err != nil {
+		return handle, err
+	}
+	handle.messageID = msg.ID
+	return handle, nil
+}
+
 // Update refreshes the header message with new content and components.
 func (h *HeaderHandle) Update(s *discordgo.Session, title, body string) error {
 	if h == nil {
 		return nil
 	}
+	return h.edit(s, BuildStyledMessage(title, body))
+}
 
-	payload := BuildStyledMessage(title, body)
-
+func (h *HeaderHandle) edit(s *discordgo.Session, payload StyledMessage) error {
 	if h.viaInteraction && h.interaction != nil {
 		edit := &discordgo.WebhookEdit{
 			Content: &payload.Content,
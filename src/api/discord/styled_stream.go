@@ -0,0 +1,132 @@
+package discord
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DefaultStreamInterval spaces out the edits of a streamed reply so that a
+// long answer stays well inside Discord's rate limits.
+const DefaultStreamInterval = 1500 * time.Millisecond
+
+// StreamingReply shows a response that grows while it is generated. The
+// first payload goes into the header message and the rest into follow-up
+// messages that are added, edited or removed as the text changes.
+type StreamingReply struct {
+	session  *discordgo.Session
+	header   *HeaderHandle
+	interval time.Duration
+
+	mu        sync.Mutex
+	lastEdit  time.Time
+	rendered  []string
+	followUps []*discordgo.Message
+}
+
+// Stream returns a StreamingReply that edits the header at most once per
+// interval until it is flushed.
+func (h *HeaderHandle) Stream(s *discordgo.Session, interval time.Duration) *StreamingReply {
+	if interval <= 0 {
+		interval = DefaultStreamInterval
+	}
+	return &StreamingReply{
+		session:  s,
+		header:   h,
+		interval: interval,
+	}
+}
+
+// Update shows the payloads unless the previous edit was less than the
+// interval ago, in which case it does nothing; the caller passes the full
+// text again with the next update.
+func (r *StreamingReply) Update(payloads []StyledMessage) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.lastEdit) < r.interval {
+		return nil
+	}
+	return r.render(payloads, false)
+}
+
+// Flush shows the final payloads, including their components, regardless of
+// the interval.
+func (r *StreamingReply) Flush(payloads []StyledMessage) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.render(payloads, true)
+}
+
+func (r *StreamingReply) render(payloads []StyledMessage, final bool) error {
+	if len(payloads) == 0 || r.header == nil {
+		return nil
+	}
+	defer func() { r.lastEdit = time.Now() }()
+
+	rendered := make([]string, len(payloads))
+	for idx, payload := range payloads {
+		rendered[idx] = payload.Content
+		// Link buttons change every time a partial URL completes, so drafts
+		// leave them out
+		if !final {
+			payload.Components = nil
+		}
+		unchanged := idx < len(r.rendered) && r.rendered[idx] == payload.Content && !final
+
+		if idx == 0 {
+			if unchanged {
+				continue
+			}
+			if err := r.header.edit(r.session, payload); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if follow := idx - 1; follow < len(r.followUps) {
+			if unchanged {
+				continue
+			}
+			edit := &discordgo.MessageEdit{
+				ID:      r.followUps[follow].ID,
+				Channel: r.followUps[follow].ChannelID,
+				Content: &payload.Content,
+			}
+			components := payload.Components
+			if components == nil {
+				components = []discordgo.MessageComponent{}
+			}
+			edit.Components = &components
+			if _, err := EditMessageComplexNoEmbed(r.session, edit); err != nil {
+				return err
+			}
+			continue
+		}
+
+		sent, err := dispatchStyledMessages(r.session, r.header.channelID, []StyledMessage{payload})
+		if err != nil {
+			return err
+		}
+		r.followUps = append(r.followUps, sent...)
+	}
+
+	// The text can shrink when a draft is discarded, leaving follow-ups with
+	// nothing to show
+	for len(r.followUps) > len(payloads)-1 {
+		last := r.followUps[len(r.followUps)-1]
+		if err := r.session.ChannelMessageDelete(last.ChannelID, last.ID); err != nil {
+			return err
+		}
+		r.followUps = r.followUps[:len(r.followUps)-1]
+	}
+
+	r.rendered = rendered
+	return nil
+}
@@ -1,10 +1,6 @@
 package polkassembly
 
-import (
-	"log"
-
-	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
-)
+import "log"
 
 // ServiceConfig describes configuration for the Polkassembly service.
 type ServiceConfig struct {
@@ -12,7 +8,7 @@ type ServiceConfig struct {
 	Logger   *log.Logger
 }
 
-// Service manages Polkassembly clients for multiple networks.
+// Service manages Polkassembly clients for multiple guilds and networks.
 // Now uses the reference API implementation via ServiceWrapper
 type Service struct {
 	wrapper *ServiceWrapper
@@ -21,9 +17,9 @@ type Service struct {
 }
 
 // NewService creates a new service using the reference API implementation
-func NewService(cfg ServiceConfig, networks map[uint8]*sharedgov.Network) (*Service, error) {
+func NewService(cfg ServiceConfig, accounts []Account) (*Service, error) {
 	// Use the wrapper that implements the reference API
-	wrapper, err := NewServiceWrapper(cfg, networks)
+	wrapper, err := NewServiceWrapper(cfg, accounts)
 	if err != nil {
 		return nil, err
 	}
@@ -40,7 +36,22 @@ func (s *Service) ListComments(network string, postID int) ([]Comment, error) {
 	return s.wrapper.ListComments(network, postID)
 }
 
-// PostFirstMessage posts the first feedback message to Polkassembly and returns the comment ID.
-func (s *Service) PostFirstMessage(network string, refID int, message, link string) (string, error) {
-	return s.wrapper.PostFirstMessage(network, refID, message, link)
+// PostFirstMessage posts a guild's first feedback message to Polkassembly and returns the comment ID.
+func (s *Service) PostFirstMessage(guildID, network string, refID int, message, link string) (string, error) {
+	return s.wrapper.PostFirstMessage(guildID, network, refID, message, link)
+}
+
+// ReplyToComment posts a guild's reply nested under another comment and returns the comment ID.
+func (s *Service) ReplyToComment(guildID, network string, refID int, parentCommentID, message string) (string, error) {
+	return s.wrapper.ReplyToComment(guildID, network, refID, parentCommentID, message)
+}
+
+// EditComment replaces the text of a guild's feedback comment.
+func (s *Service) EditComment(guildID, network string, refID int, commentID, message string) error {
+	return s.wrapper.EditComment(guildID, network, refID, commentID, message)
+}
+
+// DeleteComment removes a guild's feedback comment.
+func (s *Service) DeleteComment(guildID, network string, refID int, commentID string) error {
+	return s.wrapper.DeleteComment(guildID, network, refID, commentID)
 }
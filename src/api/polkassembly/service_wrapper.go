@@ -3,13 +3,13 @@ package polkassembly
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	polkassemblyapi "github.com/polkadot-go/polkassembly-api"
 	shareddata "github.com/stake-plus/govcomms/src/data/mysql"
-	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
 )
 
 // Comment represents a comment returned by the Polkassembly API.
@@ -41,43 +41,62 @@ func (c Comment) ParsedCreatedAt() time.Time {
 	return time.Time{}
 }
 
+// Account is the Polkassembly login a guild posts feedback with on a network.
+// An empty Intro or Outro falls back to the polkassembly_intro and
+// polkassembly_outro settings.
+type Account struct {
+	GuildID string
+	Network string
+	Seed    string
+	Intro   string
+	Outro   string
+}
+
 // ServiceWrapper wraps the reference polkassembly-api library
 type ServiceWrapper struct {
-	mu      sync.Mutex
-	clients map[string]*polkassemblyapi.Client
-	logger  *log.Logger
+	mu       sync.Mutex
+	clients  map[string]*polkassemblyapi.Client // by account key
+	accounts map[string]Account
+	logger   *log.Logger
+}
+
+func accountKey(guildID, network string) string {
+	return guildID + "/" + strings.ToLower(network)
 }
 
 // NewServiceWrapper creates a new service using the reference API implementation
-func NewServiceWrapper(cfg ServiceConfig, networks map[uint8]*sharedgov.Network) (*ServiceWrapper, error) {
+func NewServiceWrapper(cfg ServiceConfig, accounts []Account) (*ServiceWrapper, error) {
 	if cfg.Logger == nil {
 		cfg.Logger = log.Default()
 	}
 
 	wrapper := &ServiceWrapper{
-		clients: make(map[string]*polkassemblyapi.Client),
-		logger:  cfg.Logger,
+		clients:  make(map[string]*polkassemblyapi.Client),
+		accounts: make(map[string]Account),
+		logger:   cfg.Logger,
 	}
 
-	for _, net := range networks {
-		seed := strings.TrimSpace(net.PolkassemblySeed)
+	for _, account := range accounts {
+		seed := strings.TrimSpace(account.Seed)
 		if seed == "" {
 			continue
 		}
 
-		key := strings.ToLower(net.Name)
+		network := strings.ToLower(account.Network)
 		client := polkassemblyapi.NewClient(polkassemblyapi.Config{
-			Network: key,
+			Network: network,
 		})
 
 		// Authenticate with seed
-		if err := client.AuthenticateWithSeed(key, seed); err != nil {
-			wrapper.logger.Printf("polkassembly: unable to authenticate for %s: %v", key, err)
+		if err := client.AuthenticateWithSeed(network, seed); err != nil {
+			wrapper.logger.Printf("polkassembly: unable to authenticate for %s in guild %s: %v", network, account.GuildID, err)
 			continue
 		}
 
+		key := accountKey(account.GuildID, network)
 		wrapper.clients[key] = client
-		wrapper.logger.Printf("polkassembly: configured client for %s", key)
+		wrapper.accounts[key] = account
+		wrapper.logger.Printf("polkassembly: configured client for %s in guild %s", network, account.GuildID)
 	}
 
 	if len(wrapper.clients) == 0 {
@@ -87,15 +106,31 @@ func NewServiceWrapper(cfg ServiceConfig, networks map[uint8]*sharedgov.Network)
 	return wrapper, nil
 }
 
-// ListComments retrieves all comments for a referendum post.
-// Uses the polkassembly-api library to fetch comments and flattens nested replies.
-func (s *ServiceWrapper) ListComments(network string, postID int) ([]Comment, error) {
-	key := strings.ToLower(network)
+// readClient returns a client for reading a network's posts; any guild's
+// login will do
+func (s *ServiceWrapper) readClient(network string) (*polkassemblyapi.Client, bool) {
+	network = strings.ToLower(network)
 
 	s.mu.Lock()
-	client, ok := s.clients[key]
-	s.mu.Unlock()
+	defer s.mu.Unlock()
 
+	keys := make([]string, 0, len(s.clients))
+	for key := range s.clients {
+		if strings.HasSuffix(key, "/"+network) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, false
+	}
+	sort.Strings(keys)
+	return s.clients[keys[0]], true
+}
+
+// ListComments retrieves all comments for a referendum post.
+// Uses the polkassembly-api library to fetch comments and flattens nested replies.
+func (s *ServiceWrapper) ListComments(network string, postID int) ([]Comment, error) {
+	client, ok := s.readClient(network)
 	if !ok {
 		return nil, fmt.Errorf("polkassembly: no client configured for network %s", network)
 	}
@@ -161,22 +196,26 @@ func (s *ServiceWrapper) ListComments(network string, postID int) ([]Comment, er
 	return result, nil
 }
 
-// PostFirstMessage posts the first feedback message to Polkassembly and returns the comment ID.
-func (s *ServiceWrapper) PostFirstMessage(network string, refID int, message, link string) (string, error) {
-	key := strings.ToLower(network)
-
+// account returns the client and account a guild posts with on a network
+func (s *ServiceWrapper) account(guildID, network string) (*polkassemblyapi.Client, Account, bool) {
 	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := accountKey(guildID, network)
 	client, ok := s.clients[key]
-	s.mu.Unlock()
+	return client, s.accounts[key], ok
+}
 
-	if !ok {
-		s.logger.Printf("polkassembly: no client configured for network %s", network)
-		return "", fmt.Errorf("polkassembly: no client configured for network %s", network)
+// formatFeedback quotes a feedback message between the account's intro and
+// outro, else those from database settings
+func formatFeedback(account Account, message string) string {
+	intro := account.Intro
+	if intro == "" {
+		intro = shareddata.GetSetting("polkassembly_intro")
+	}
+	outro := account.Outro
+	if outro == "" {
+		outro = shareddata.GetSetting("polkassembly_outro")
 	}
-
-	// Get intro and outro from database settings
-	intro := shareddata.GetSetting("polkassembly_intro")
-	outro := shareddata.GetSetting("polkassembly_outro")
 
 	// Format the feedback message in a blockquote to make it stand out
 	quotedMessage := fmt.Sprintf("> %s", strings.ReplaceAll(message, "\n", "\n> "))
@@ -190,7 +229,20 @@ func (s *ServiceWrapper) PostFirstMessage(network string, refID int, message, li
 	if outro != "" {
 		parts = append(parts, outro)
 	}
-	content := strings.Join(parts, "\n\n")
+	return strings.Join(parts, "\n\n")
+}
+
+// PostFirstMessage posts a guild's first feedback message to Polkassembly and returns the comment ID.
+func (s *ServiceWrapper) PostFirstMessage(guildID, network string, refID int, message, link string) (string, error) {
+	key := strings.ToLower(network)
+
+	client, account, ok := s.account(guildID, network)
+	if !ok {
+		s.logger.Printf("polkassembly: no client configured for network %s in guild %s", network, guildID)
+		return "", fmt.Errorf("polkassembly: no client configured for network %s", network)
+	}
+
+	content := formatFeedback(account, message)
 
 	s.logger.Printf("polkassembly: PostFirstMessage called for %s ref #%d", key, refID)
 
@@ -211,3 +263,57 @@ func (s *ServiceWrapper) PostFirstMessage(network string, refID int, message, li
 	s.logger.Printf("polkassembly: posted comment %s for %s ref #%d", comment.ID, key, refID)
 	return comment.ID, nil
 }
+
+// ReplyToComment posts a guild's reply nested under another comment and returns the comment ID.
+func (s *ServiceWrapper) ReplyToComment(guildID, network string, refID int, parentCommentID, message string) (string, error) {
+	client, _, ok := s.account(guildID, network)
+	if !ok {
+		return "", fmt.Errorf("polkassembly: no client configured for network %s", network)
+	}
+
+	comment, err := client.AddComment("ReferendumV2", refID, polkassemblyapi.AddCommentRequest{
+		Content:         message,
+		ParentCommentID: &parentCommentID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("polkassembly: reply to comment %s failed for %s ref %d: %w", parentCommentID, network, refID, err)
+	}
+	if comment == nil || comment.ID == "" {
+		return "", fmt.Errorf("polkassembly: reply succeeded but no comment ID returned for %s ref #%d", strings.ToLower(network), refID)
+	}
+
+	s.logger.Printf("polkassembly: posted reply %s to comment %s for %s ref #%d", comment.ID, parentCommentID, strings.ToLower(network), refID)
+	return comment.ID, nil
+}
+
+// EditComment replaces the text of a guild's feedback comment, keeping its intro and outro.
+func (s *ServiceWrapper) EditComment(guildID, network string, refID int, commentID, message string) error {
+	client, account, ok := s.account(guildID, network)
+	if !ok {
+		return fmt.Errorf("polkassembly: no client configured for network %s", network)
+	}
+
+	if _, err := client.EditComment("ReferendumV2", refID, commentID, polkassemblyapi.AddCommentRequest{
+		Content: formatFeedback(account, message),
+	}); err != nil {
+		return fmt.Errorf("polkassembly: edit comment %s failed for %s ref %d: %w", commentID, network, refID, err)
+	}
+
+	s.logger.Printf("polkassembly: edited comment %s for %s ref #%d", commentID, strings.ToLower(network), refID)
+	return nil
+}
+
+// DeleteComment removes a guild's feedback comment.
+func (s *ServiceWrapper) DeleteComment(guildID, network string, refID int, commentID string) error {
+	client, _, ok := s.account(guildID, network)
+	if !ok {
+		return fmt.Errorf("polkassembly: no client configured for network %s", network)
+	}
+
+	if err := client.DeleteComment("ReferendumV2", refID, commentID); err != nil {
+		return fmt.Errorf("polkassembly: delete comment %s failed for %s ref %d: %w", commentID, network, refID, err)
+	}
+
+	s.logger.Printf("polkassembly: deleted comment %s for %s ref #%d", commentID, strings.ToLower(network), refID)
+	return nil
+}
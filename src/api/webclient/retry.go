@@ -2,9 +2,35 @@ package webclient
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
+// StatusError is returned when the last attempt failed with a transient
+// status, so callers can tell an overloaded or failing vendor from a bad
+// request.
+type StatusError struct {
+	Status int
+	Err    error
+}
+
+func (e *StatusError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("status %d", e.Status)
+}
+
+func (e *StatusError) Unwrap() error { return e.Err }
+
+// Transient reports whether err came from a rate limit (429) or server
+// error (5xx) after retries ran out.
+func Transient(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && (statusErr.Status == 429 || statusErr.Status >= 500)
+}
+
 type AttemptFunc func() (status int, body []byte, err error)
 
 // DoWithRetry retries the attempt function on transient errors (429/5xx) or non-nil errors.
@@ -22,6 +48,9 @@ func DoWithRetry(ctx context.Context, attempts int, initialDelay time.Duration,
 			return status, body, nil
 		}
 		if i == attempts-1 {
+			if err != nil && (status == 429 || status >= 500) {
+				err = &StatusError{Status: status, Err: err}
+			}
 			return status, body, err
 		}
 		t := time.NewTimer(delay)
@@ -0,0 +1,102 @@
+package webclient
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestFunc builds a fresh request for each attempt.
+type RequestFunc func() (*http.Request, error)
+
+// OpenStream sends a request whose response body is read incrementally. It
+// retries like DoWithRetry until a 200 response arrives; the caller must
+// close the returned body.
+func OpenStream(ctx context.Context, client *http.Client, attempts int, initialDelay time.Duration, newRequest RequestFunc) (*http.Response, error) {
+	var resp *http.Response
+	status, body, err := DoWithRetry(ctx, attempts, initialDelay, func() (int, []byte, error) {
+		req, err := newRequest()
+		if err != nil {
+			return 0, nil, err
+		}
+		r, err := client.Do(req)
+		if err != nil {
+			return 0, nil, err
+		}
+		if r.StatusCode != http.StatusOK {
+			defer r.Body.Close()
+			b, _ := io.ReadAll(io.LimitReader(r.Body, 4096))
+			return r.StatusCode, b, nil
+		}
+		resp = r
+		return r.StatusCode, nil, nil
+	})
+	if err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, err
+	}
+	if resp == nil {
+		err := fmt.Errorf("status %d: %s", status, string(body))
+		if status == http.StatusTooManyRequests || status >= 500 {
+			return nil, &StatusError{Status: status, Err: err}
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ReadSSE reads server-sent events from r and calls fn with each event's
+// name and data. It stops at the end of the stream, on a [DONE] data line
+// or when fn returns an error.
+func ReadSSE(r io.Reader, fn func(event, data string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var event string
+	var data []string
+	dispatch := func() error {
+		if len(data) == 0 {
+			event = ""
+			return nil
+		}
+		payload := strings.Join(data, "\n")
+		name := event
+		event, data = "", nil
+		if payload == "[DONE]" {
+			return io.EOF
+		}
+		return fn(name, payload)
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if err := dispatch(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+		case strings.HasPrefix(line, ":"):
+			// comment or keep-alive
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if err := dispatch(); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
@@ -1,11 +1,13 @@
 package cache
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
 	"gorm.io/gorm"
 )
 
@@ -125,6 +127,173 @@ func FormatQAContext(qas []QAHistory) string {
 	return builder.String()
 }
 
+// GetProposalCallByNetworkName returns the decoded call tree stored for a referendum.
+func (cs *ContextStore) GetProposalCallByNetworkName(network string, refID uint32) (json.RawMessage, error) {
+	return cs.refJSONColumn(network, refID, "decoded_call")
+}
+
+// GetPassProjectionByNetworkName returns the indexer's latest evaluation of a
+// referendum's tally against its track curves.
+func (cs *ContextStore) GetPassProjectionByNetworkName(network string, refID uint32) (json.RawMessage, error) {
+	return cs.refJSONColumn(network, refID, "pass_projection")
+}
+
+// refJSONColumn reads a JSON column from the refs row of a referendum.
+func (cs *ContextStore) refJSONColumn(network string, refID uint32, column string) (json.RawMessage, error) {
+	networkID, err := cs.lookupNetworkID(network)
+	if err != nil {
+		return nil, err
+	}
+
+	var row struct {
+		Value *string `gorm:"column:value"`
+	}
+	err = cs.db.
+		Table("refs").
+		Select(column+" AS value").
+		Where("network_id = ? AND ref_id = ?", networkID, refID).
+		First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if row.Value == nil || strings.TrimSpace(*row.Value) == "" {
+		return nil, nil
+	}
+
+	return json.RawMessage(*row.Value), nil
+}
+
+// SyncProposalCall copies the stored call tree into the cache entry, returning it.
+func (cs *ContextStore) SyncProposalCall(manager *Manager, network string, refID uint32) (json.RawMessage, error) {
+	proposalCall, err := cs.GetProposalCallByNetworkName(network, refID)
+	if err != nil || len(proposalCall) == 0 {
+		return nil, err
+	}
+	if manager != nil {
+		if err := manager.UpdateProposalCall(network, refID, proposalCall); err != nil {
+			return proposalCall, err
+		}
+	}
+	return proposalCall, nil
+}
+
+// LifecycleEvent is a referendum lifecycle transition recorded by the indexer.
+type LifecycleEvent struct {
+	Event       string     `json:"event"`
+	OldStatus   string     `json:"oldStatus,omitempty"`
+	NewStatus   string     `json:"newStatus"`
+	BlockNumber uint64     `json:"blockNumber"`
+	BlockHash   *string    `json:"blockHash,omitempty"`
+	Ayes        *string    `json:"ayes,omitempty"`
+	Nays        *string    `json:"nays,omitempty"`
+	Support     *string    `json:"support,omitempty"`
+	OccurredAt  *time.Time `json:"occurredAt,omitempty"`
+}
+
+// GetLifecycleByNetworkName returns the recorded lifecycle transitions of a
+// referendum in chain order.
+func (cs *ContextStore) GetLifecycleByNetworkName(network string, refID uint32) ([]LifecycleEvent, error) {
+	networkID, err := cs.lookupNetworkID(network)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []LifecycleEvent
+	err = cs.db.
+		Table("ref_events").
+		Select("event, old_status, new_status, block_number, block_hash, ayes, nays, support, occurred_at").
+		Where("network_id = ? AND ref_id = ?", networkID, refID).
+		Order("block_number ASC, id ASC").
+		Scan(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ReferendumAccount is an account attached to a referendum with the on-chain
+// identity the indexer resolved for it.
+type ReferendumAccount struct {
+	Role          string            `json:"role"`
+	Address       string            `json:"address"`
+	Resolved      bool              `json:"resolved"`
+	HasIdentity   bool              `json:"hasIdentity"`
+	Display       string            `json:"display,omitempty"`
+	Fields        map[string]string `json:"fields,omitempty"`
+	Judgement     string            `json:"judgement,omitempty"`
+	Judgements    json.RawMessage   `json:"judgements,omitempty"`
+	Verified      bool              `json:"verified"`
+	ParentAddress *string           `json:"parentAddress,omitempty"`
+	ParentDisplay *string           `json:"parentDisplay,omitempty"`
+	SubName       *string           `json:"subName,omitempty"`
+	ResolvedAt    *time.Time        `json:"resolvedAt,omitempty"`
+}
+
+// GetAccountsByNetworkName returns the submitter, depositors and beneficiaries
+// of a referendum with their cached on-chain identities.
+func (cs *ContextStore) GetAccountsByNetworkName(network string, refID uint32) ([]ReferendumAccount, error) {
+	networkID, err := cs.lookupNetworkID(network)
+	if err != nil {
+		return nil, err
+	}
+
+	var ref sharedgov.Ref
+	err = cs.db.Where("network_id = ? AND ref_id = ?", networkID, refID).First(&ref).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var beneficiaries []string
+	if ref.DecodedCall != nil {
+		var call struct {
+			Beneficiaries []string `json:"beneficiaries"`
+		}
+		if json.Unmarshal([]byte(*ref.DecodedCall), &call) == nil {
+			beneficiaries = call.Beneficiaries
+		}
+	}
+	var spends []sharedgov.RefSpend
+	if err := cs.db.Where("ref_db_id = ?", ref.ID).Find(&spends).Error; err != nil {
+		return nil, err
+	}
+	beneficiaries = append(beneficiaries, sharedgov.SpendBeneficiaries(spends)...)
+
+	accounts := sharedgov.RefAccounts(&ref, beneficiaries)
+	identities, err := sharedgov.LoadIdentities(cs.db, networkID, sharedgov.Addresses(accounts))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ReferendumAccount, 0, len(accounts))
+	for _, account := range accounts {
+		entry := ReferendumAccount{Role: account.Role, Address: account.Address}
+		if identity, ok := identities[account.Address]; ok {
+			resolvedAt := identity.ResolvedAt
+			entry.Resolved = true
+			entry.HasIdentity = identity.HasIdentity
+			entry.Display = identity.Name()
+			entry.Fields = identity.FieldMap()
+			entry.Judgement = identity.Judgement
+			entry.Verified = identity.Verified
+			entry.ParentAddress = identity.ParentAddress
+			entry.ParentDisplay = identity.ParentDisplay
+			entry.SubName = identity.SubName
+			entry.ResolvedAt = &resolvedAt
+			if identity.Judgements != nil {
+				entry.Judgements = json.RawMessage(*identity.Judgements)
+			}
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
 func (cs *ContextStore) lookupNetworkID(network string) (uint8, error) {
 	if cs == nil || cs.db == nil {
 		return 0, fmt.Errorf("context store not initialized")
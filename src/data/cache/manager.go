@@ -58,6 +58,8 @@ type Entry struct {
 	Claims       *ClaimsData  `json:"claims,omitempty"`
 	TeamMembers  *TeamsData   `json:"teamMembers,omitempty"`
 	Summary      *SummaryData `json:"summary,omitempty"`
+	// ProposalCall is the decoded on-chain call tree (polkadot.ProposalCall JSON)
+	ProposalCall json.RawMessage `json:"proposalCall,omitempty"`
 
 	baseDir string
 }
@@ -251,6 +253,7 @@ func (m *Manager) loadEntryUnlocked(network string, refID uint32) (*Entry, error
 		Claims:       stored.Claims,
 		TeamMembers:  stored.TeamMembers,
 		Summary:      stored.Summary,
+		ProposalCall: stored.ProposalCall,
 		baseDir:      paths.BaseDir,
 	}
 
@@ -301,6 +304,22 @@ func (m *Manager) UpdateSummary(network string, refID uint32, summary *SummaryDa
 	return saveMetadata(paths, entry)
 }
 
+// UpdateProposalCall updates the cache entry with the decoded on-chain call.
+func (m *Manager) UpdateProposalCall(network string, refID uint32, proposalCall json.RawMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, err := m.loadEntryUnlocked(network, refID)
+	if err != nil {
+		return fmt.Errorf("load entry: %w", err)
+	}
+
+	entry.ProposalCall = proposalCall
+
+	paths := m.cachePaths(network, refID)
+	return saveMetadata(paths, entry)
+}
+
 func (m *Manager) cachePaths(network string, refID uint32) cachePaths {
 	networkSegment := sanitizeSegment(network)
 	refSegment := fmt.Sprintf("%d", refID)
@@ -351,6 +370,7 @@ func saveMetadata(paths cachePaths, entry *Entry) error {
 		Claims:       entry.Claims,
 		TeamMembers:  entry.TeamMembers,
 		Summary:      entry.Summary,
+		ProposalCall: entry.ProposalCall,
 	}
 
 	data, err := json.MarshalIndent(record, "", "  ")
@@ -375,6 +395,8 @@ type metadataRecord struct {
 	Claims      *ClaimsData  `json:"claims,omitempty"`
 	TeamMembers *TeamsData   `json:"teamMembers,omitempty"`
 	Summary     *SummaryData `json:"summary,omitempty"`
+	// On-chain data
+	ProposalCall json.RawMessage `json:"proposalCall,omitempty"`
 }
 
 // SummaryData stores the generated referendum summary
@@ -0,0 +1,27 @@
+package config
+
+import (
+	shareddata "github.com/stake-plus/govcomms/src/data/mysql"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
+	"gorm.io/gorm"
+)
+
+// LegacyGuild describes the single guild configured through settings, which
+// is served while the guilds table has no active rows
+func LegacyGuild(db *gorm.DB) sharedgov.Guild {
+	base := LoadBase(db)
+	guild := sharedgov.Guild{
+		GuildID:                 base.GuildID,
+		QARoleID:                GetSetting("qa_role_id", "QA_ROLE_ID", ""),
+		FeedbackRoleID:          GetSetting("feedback_role_id", "FEEDBACK_ROLE_ID", ""),
+		FeedbackReviewChannelID: GetSetting("feedback_review_channel_id", "FEEDBACK_REVIEW_CHANNEL_ID", ""),
+		Active:                  true,
+	}
+	if intro := shareddata.GetSetting("polkassembly_intro"); intro != "" {
+		guild.PolkassemblyIntro = &intro
+	}
+	if outro := shareddata.GetSetting("polkassembly_outro"); outro != "" {
+		guild.PolkassemblyOutro = &outro
+	}
+	return guild
+}
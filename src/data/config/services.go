@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -17,7 +18,12 @@ type AIConfig struct {
 	GeminiKey      string
 	DeepSeekKey    string
 	GrokKey        string
+	CompatBaseURL  string
+	CompatKey      string
+	CompatModel    string
+	CompatTools    bool
 	AIProvider     string
+	AIFallbacks    []string
 	AISystemPrompt string
 	AIModel        string
 	AIEnableWeb    bool
@@ -43,7 +49,12 @@ func LoadAIConfig(db *gorm.DB) AIConfig {
 	geminiKey := GetSetting("gemini_api_key", "GEMINI_API_KEY", "")
 	deepSeekKey := GetSetting("deepseek_api_key", "DEEPSEEK_API_KEY", "")
 	grokKey := GetSetting("grok_api_key", "GROK_API_KEY", "")
+	compatBaseURL := GetSetting("openai_compat_base_url", "OPENAI_COMPAT_BASE_URL", "")
+	compatKey := GetSetting("openai_compat_api_key", "OPENAI_COMPAT_API_KEY", "")
+	compatModel := GetSetting("openai_compat_model", "OPENAI_COMPAT_MODEL", "")
+	compatTools := getBoolSetting("openai_compat_tools", "OPENAI_COMPAT_TOOLS", true)
 	aiProvider := GetSetting("ai_provider", "AI_PROVIDER", "gpt51")
+	aiFallbacks := parseCSV(GetSetting("ai_fallback_providers", "AI_FALLBACK_PROVIDERS", ""))
 
 	aiSystemPrompt := GetSetting("ai_system_prompt", "AI_SYSTEM_PROMPT",
 		`You are a helpful assistant that analyzes Polkadot/Kusama governance proposals and answers questions about them. 
@@ -52,6 +63,10 @@ Focus on facts from the proposal and avoid speculation.
 If information is not available in the provided content, clearly state that.`)
 
 	aiModel := GetSetting("ai_model", "AI_MODEL", "")
+	if aiModel == "" && strings.EqualFold(strings.TrimSpace(aiProvider), "openaicompat") {
+		// Self-hosted models have no baked-in default to report
+		aiModel = compatModel
+	}
 	aiEnableWeb := shareddata.GetSetting("ai_enable_web_search") == "1"
 	aiEnableDeep := shareddata.GetSetting("ai_enable_deep_search") == "1"
 
@@ -61,7 +76,12 @@ If information is not available in the provided content, clearly state that.`)
 		GeminiKey:      geminiKey,
 		DeepSeekKey:    deepSeekKey,
 		GrokKey:        grokKey,
+		CompatBaseURL:  compatBaseURL,
+		CompatKey:      compatKey,
+		CompatModel:    compatModel,
+		CompatTools:    compatTools,
 		AIProvider:     aiProvider,
+		AIFallbacks:    aiFallbacks,
 		AISystemPrompt: aiSystemPrompt,
 		AIModel:        aiModel,
 		AIEnableWeb:    aiEnableWeb,
@@ -150,6 +170,9 @@ func defaultConsensusParticipants(cfg AIConfig) []string {
 	if cfg.ClaudeKey != "" {
 		add("sonnet45")
 	}
+	if cfg.CompatBaseURL != "" {
+		add("openaicompat")
+	}
 	return participants
 }
 
@@ -175,6 +198,7 @@ func uniqueStrings(values []string) []string {
 func (cfg AIConfig) FactoryConfig() aicore.FactoryConfig {
 	return aicore.FactoryConfig{
 		Provider:            cfg.AIProvider,
+		Fallbacks:           cfg.AIFallbacks,
 		SystemPrompt:        cfg.AISystemPrompt,
 		Model:               cfg.AIModel,
 		OpenAIKey:           cfg.OpenAIKey,
@@ -182,6 +206,9 @@ func (cfg AIConfig) FactoryConfig() aicore.FactoryConfig {
 		GeminiKey:           cfg.GeminiKey,
 		DeepSeekKey:         cfg.DeepSeekKey,
 		GrokKey:             cfg.GrokKey,
+		CompatBaseURL:       cfg.CompatBaseURL,
+		CompatKey:           cfg.CompatKey,
+		CompatModel:         cfg.CompatModel,
 		MaxCompletionTokens: 0,
 		Extra:               cfg.extraSettings(),
 	}
@@ -195,6 +222,9 @@ func (cfg AIConfig) extraSettings() map[string]string {
 	if cfg.AIEnableDeep {
 		extra["enable_deep_search"] = "1"
 	}
+	if !cfg.CompatTools {
+		extra["openaicompat.tools"] = "0"
+	}
 	if len(cfg.Consensus.Researchers) > 0 {
 		extra["consensus_researchers"] = strings.Join(cfg.Consensus.Researchers, ",")
 	}
@@ -278,6 +308,11 @@ type FeedbackConfig struct {
 	IndexerWorkers         int
 	IndexerIntervalMinutes int
 	PolkassemblyEndpoint   string
+	DaoVoteCutoffHours     int
+	DaoQuorumPercent       int
+	AutoCreateThreads      bool
+	AutoThreadMaxAgeHours  int
+	ReminderOffsetHours    []int
 	Enabled                bool
 }
 
@@ -308,6 +343,36 @@ func LoadFeedbackConfig(db *gorm.DB) FeedbackConfig {
 			intervalMinutes = i
 		}
 	}
+
+	// DAO voting closes this many hours before the on-chain decision end
+	cutoffHours := 24
+	if cutoffStr := shareddata.GetSetting("dao_vote_cutoff_hours"); cutoffStr != "" {
+		if h, err := strconv.Atoi(cutoffStr); err == nil && h >= 0 {
+			cutoffHours = h
+		}
+	}
+
+	// Share of registered members that must vote for a DAO decision to stand
+	quorumPercent := 50
+	if quorumStr := shareddata.GetSetting("dao_quorum_percent"); quorumStr != "" {
+		if q, err := strconv.Atoi(quorumStr); err == nil && q >= 0 && q <= 100 {
+			quorumPercent = q
+		}
+	}
+
+	// Forum posts are opened automatically only when enabled, and then only
+	// for referenda submitted within this many hours, so a fresh index does
+	// not flood the channel
+	autoCreateThreads := getBoolSetting("auto_create_threads", "AUTO_CREATE_THREADS", false)
+	maxAgeHours := 48
+	if ageStr := shareddata.GetSetting("auto_thread_max_age_hours"); ageStr != "" {
+		if h, err := strconv.Atoi(ageStr); err == nil && h > 0 {
+			maxAgeHours = h
+		}
+	}
+
+	// Hours before the decision end at which referendum threads are reminded
+	reminderHours := parseHourOffsets(GetSetting("decision_reminder_hours", "DECISION_REMINDER_HOURS", "72,24,6"))
 	enabled := getBoolSetting("enable_feedback", "ENABLE_FEEDBACK", true)
 
 	return FeedbackConfig{
@@ -316,6 +381,11 @@ func LoadFeedbackConfig(db *gorm.DB) FeedbackConfig {
 		IndexerWorkers:         workers,
 		IndexerIntervalMinutes: intervalMinutes,
 		PolkassemblyEndpoint:   polkassemblyEndpoint,
+		DaoVoteCutoffHours:     cutoffHours,
+		DaoQuorumPercent:       quorumPercent,
+		AutoCreateThreads:      autoCreateThreads,
+		AutoThreadMaxAgeHours:  maxAgeHours,
+		ReminderOffsetHours:    reminderHours,
 		Enabled:                enabled,
 	}
 }
@@ -370,3 +440,19 @@ func LoadReportsConfig(db *gorm.DB) ReportsConfig {
 		Enabled:       enabled,
 	}
 }
+
+// parseHourOffsets parses a list of positive hour offsets, largest first
+func parseHourOffsets(raw string) []int {
+	var hours []int
+	seen := make(map[int]bool)
+	for _, field := range parseCSV(raw) {
+		h, err := strconv.Atoi(field)
+		if err != nil || h <= 0 || seen[h] {
+			continue
+		}
+		seen[h] = true
+		hours = append(hours, h)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(hours)))
+	return hours
+}
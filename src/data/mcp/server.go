@@ -153,6 +153,12 @@ func (s *Server) handleReferenda(w http.ResponseWriter, r *http.Request) {
 	case "history":
 		s.logf("mcp: history network=%s ref=%d", network, refID)
 		s.handleHistory(w, network, uint32(refID))
+	case "timeline":
+		s.logf("mcp: timeline network=%s ref=%d", network, refID)
+		s.handleTimeline(w, network, uint32(refID))
+	case "accounts":
+		s.logf("mcp: accounts network=%s ref=%d", network, refID)
+		s.handleAccounts(w, network, uint32(refID))
 	default:
 		http.NotFound(w, r)
 	}
@@ -168,11 +174,28 @@ func (s *Server) handleMetadata(w http.ResponseWriter, network string, refID uin
 		http.Error(w, fmt.Sprintf("cache load failed: %v", err), http.StatusInternalServerError)
 		return
 	}
+	proposalCall := entry.ProposalCall
+	if len(proposalCall) == 0 && s.contextStore != nil {
+		synced, err := s.contextStore.SyncProposalCall(s.cache, network, refID)
+		if err != nil {
+			s.logf("mcp: proposal call sync failed network=%s ref=%d: %v", network, refID, err)
+		}
+		proposalCall = synced
+	}
+	var passProjection json.RawMessage
+	if s.contextStore != nil {
+		passProjection, err = s.contextStore.GetPassProjectionByNetworkName(network, refID)
+		if err != nil {
+			s.logf("mcp: pass projection lookup failed network=%s ref=%d: %v", network, refID, err)
+		}
+	}
 	payload := ReferendumPayload{
-		Network:     entry.Network,
-		RefID:       entry.RefID,
-		Attachments: entry.Attachments,
-		RefreshedAt: entry.RefreshedAt,
+		Network:        entry.Network,
+		RefID:          entry.RefID,
+		Attachments:    entry.Attachments,
+		ProposalCall:   proposalCall,
+		PassProjection: passProjection,
+		RefreshedAt:    entry.RefreshedAt,
 	}
 	writeJSON(w, http.StatusOK, payload)
 }
@@ -298,6 +321,42 @@ func (s *Server) handleHistory(w http.ResponseWriter, network string, refID uint
 	writeJSON(w, http.StatusOK, payload)
 }
 
+func (s *Server) handleTimeline(w http.ResponseWriter, network string, refID uint32) {
+	if s.contextStore == nil {
+		http.Error(w, "timeline unavailable", http.StatusNotImplemented)
+		return
+	}
+
+	events, err := s.contextStore.GetLifecycleByNetworkName(network, refID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("timeline retrieval failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, TimelinePayload{
+		Network: strings.TrimSpace(network),
+		RefID:   refID,
+		Events:  events,
+	})
+}
+
+func (s *Server) handleAccounts(w http.ResponseWriter, network string, refID uint32) {
+	if s.contextStore == nil {
+		http.Error(w, "accounts unavailable", http.StatusNotImplemented)
+		return
+	}
+
+	accounts, err := s.contextStore.GetAccountsByNetworkName(network, refID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("accounts retrieval failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, AccountsPayload{
+		Network:  strings.TrimSpace(network),
+		RefID:    refID,
+		Accounts: accounts,
+	})
+}
+
 func equalAttachmentFile(candidate, requested string) bool {
 	if strings.EqualFold(candidate, requested) {
 		return true
@@ -321,7 +380,12 @@ type ReferendumPayload struct {
 	RefID       uint32             `json:"refId"`
 	Content     string             `json:"content,omitempty"`
 	Attachments []cache.Attachment `json:"attachments,omitempty"`
-	RefreshedAt time.Time          `json:"refreshedAt"`
+	// ProposalCall is the decoded on-chain call that executes on enactment.
+	ProposalCall json.RawMessage `json:"proposalCall,omitempty"`
+	// PassProjection compares the tally with the track's approval and
+	// support curves and projects when the referendum would pass.
+	PassProjection json.RawMessage `json:"passProjection,omitempty"`
+	RefreshedAt    time.Time       `json:"refreshedAt"`
 }
 
 // HistoryPayload structures the Q&A history response.
@@ -341,6 +405,20 @@ type HistoryEntry struct {
 	AskedAt  time.Time `json:"askedAt"`
 }
 
+// TimelinePayload structures the referendum lifecycle response.
+type TimelinePayload struct {
+	Network string                 `json:"network"`
+	RefID   uint32                 `json:"refId"`
+	Events  []cache.LifecycleEvent `json:"events"`
+}
+
+// AccountsPayload structures the referendum accounts response.
+type AccountsPayload struct {
+	Network  string                    `json:"network"`
+	RefID    uint32                    `json:"refId"`
+	Accounts []cache.ReferendumAccount `json:"accounts"`
+}
+
 func writeJSON(w http.ResponseWriter, status int, payload any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
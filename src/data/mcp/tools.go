@@ -18,15 +18,15 @@ func NewReferendaTool(baseURL, authToken string) *aicore.Tool {
 	base = strings.TrimRight(base, "/")
 
 	return &aicore.Tool{
-		Type:        "mcp_referenda",
+		Type:        aicore.ToolMCPReferenda,
 		Name:        "fetch_referendum_data",
-		Description: "Fetch cached Polkadot/Kusama referendum data from GovComms. Provide `network` (e.g. polkadot), `refId` (integer), optional `resource` (metadata|content|attachments|history), and optional `file` name when retrieving attachment bytes.",
+		Description: "Fetch cached Polkadot/Kusama referendum data from GovComms. Provide `network` (e.g. polkadot), `refId` (integer), optional `resource` (metadata|content|attachments|history|timeline|accounts), and optional `file` name when retrieving attachment bytes.",
 		Parameters: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
 				"network": map[string]any{
 					"type":        "string",
-					"description": "Network slug such as polkadot, kusama, collectives.",
+					"description": "Network slug such as polkadot, kusama, collectives (Fellowship) or ambassador.",
 				},
 				"refId": map[string]any{
 					"type":        "integer",
@@ -34,8 +34,8 @@ func NewReferendaTool(baseURL, authToken string) *aicore.Tool {
 				},
 				"resource": map[string]any{
 					"type":        "string",
-					"description": "Optional data segment to fetch (metadata, content, attachments, history). Defaults to metadata.",
-					"enum":        []string{"metadata", "content", "attachments", "history"},
+					"description": "Optional data segment to fetch (metadata, content, attachments, history, timeline, accounts). Defaults to metadata, which includes the decoded on-chain call (proposalCall) that executes on enactment and, for ongoing referenda, passProjection (current approval/support against the track curves and when it would pass if the tally holds). timeline lists on-chain lifecycle transitions (submitted, decision, confirming, outcome) with block, time and tally. accounts lists the submitter, depositors and beneficiaries with their on-chain identity (display name, twitter, github, web, email, registrar judgement, parent identity).",
+					"enum":        []string{"metadata", "content", "attachments", "history", "timeline", "accounts"},
 				},
 				"file": map[string]any{
 					"type":        "string",
@@ -12,11 +12,13 @@ import (
 
 	"github.com/stake-plus/govcomms/src/actions"
 	"github.com/stake-plus/govcomms/src/agents"
+	aicore "github.com/stake-plus/govcomms/src/api/ai/core"
 	_ "github.com/stake-plus/govcomms/src/api/ai/providers"
 	cachepkg "github.com/stake-plus/govcomms/src/data/cache"
 	sharedconfig "github.com/stake-plus/govcomms/src/data/config"
 	shareddata "github.com/stake-plus/govcomms/src/data/mysql"
 	"github.com/stake-plus/govcomms/src/data/mcp"
+	sharedgov "github.com/stake-plus/govcomms/src/polkadot-go/governance"
 	"gorm.io/gorm"
 )
 
@@ -35,6 +37,24 @@ func main() {
 		log.Printf("settings load failed: %v", err)
 	}
 
+	// Every provider reports its token usage to the cost ledger
+	ledger := sharedgov.NewUsageLedger(db)
+	aicore.SetUsageRecorder(func(scope aicore.UsageScope, usage aicore.Usage) {
+		ledger.Record(sharedgov.AITokenUsage{
+			Provider:     usage.Provider,
+			Model:        usage.Model,
+			Feature:      scope.Feature,
+			NetworkID:    scope.NetworkID,
+			RefID:        scope.RefID,
+			Command:      scope.Command,
+			UserID:       scope.UserID,
+			GuildID:      scope.GuildID,
+			InputTokens:  uint32(usage.InputTokens),
+			OutputTokens: uint32(usage.OutputTokens),
+			CachedTokens: uint32(usage.CachedTokens),
+		})
+	})
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -0,0 +1,780 @@
+package polkadot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+)
+
+// maxCallDepth bounds recursion through nested calls and type definitions
+const maxCallDepth = 48
+
+// DecodedCall is a runtime call resolved against the chain metadata
+type DecodedCall struct {
+	Pallet      string       `json:"pallet"`
+	PalletIndex uint8        `json:"pallet_index"`
+	Call        string       `json:"call"`
+	CallIndex   uint8        `json:"call_index"`
+	Args        []DecodedArg `json:"args,omitempty"`
+}
+
+// DecodedArg is a single named call argument
+type DecodedArg struct {
+	Name     string      `json:"name"`
+	TypeName string      `json:"type,omitempty"`
+	Value    interface{} `json:"value"`
+	Display  string      `json:"display,omitempty"`
+}
+
+// UnmarshalJSON restores nested calls as *DecodedCall so persisted call trees
+// can be walked the same way as freshly decoded ones
+func (a *DecodedArg) UnmarshalJSON(data []byte) error {
+	type argAlias DecodedArg
+	var raw struct {
+		argAlias
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*a = DecodedArg(raw.argAlias)
+	if len(raw.Value) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw.Value))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	restored, err := restoreCalls(value)
+	if err != nil {
+		return err
+	}
+	a.Value = restored
+	return nil
+}
+
+func restoreCalls(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		_, hasPallet := val["pallet"]
+		_, hasCall := val["call"]
+		_, hasIndex := val["call_index"]
+		if hasPallet && hasCall && hasIndex {
+			encoded, err := json.Marshal(val)
+			if err != nil {
+				return nil, err
+			}
+			var call DecodedCall
+			if err := json.Unmarshal(encoded, &call); err != nil {
+				return nil, err
+			}
+			return &call, nil
+		}
+		for key, inner := range val {
+			restored, err := restoreCalls(inner)
+			if err != nil {
+				return nil, err
+			}
+			val[key] = restored
+		}
+		return val, nil
+	case []interface{}:
+		for i, inner := range val {
+			restored, err := restoreCalls(inner)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = restored
+		}
+		return val, nil
+	}
+	return v, nil
+}
+
+// AccountValue is an SS58 address decoded from an AccountId32 argument
+type AccountValue string
+
+// Accounts returns every account referenced by the call and its nested calls
+func (dc *DecodedCall) Accounts() []string {
+	seen := make(map[string]bool)
+	var result []string
+	dc.walkValues(func(v interface{}) {
+		if acc, ok := v.(AccountValue); ok && !seen[string(acc)] {
+			seen[string(acc)] = true
+			result = append(result, string(acc))
+		}
+	})
+	return result
+}
+
+// NestedCalls returns the calls wrapped by this call (batch items, proxied calls, etc)
+func (dc *DecodedCall) NestedCalls() []*DecodedCall {
+	var result []*DecodedCall
+	for _, arg := range dc.Args {
+		collectCalls(arg.Value, &result)
+	}
+	return result
+}
+
+// Arg returns the argument with the given name
+func (dc *DecodedCall) Arg(name string) (DecodedArg, bool) {
+	for _, arg := range dc.Args {
+		if arg.Name == name {
+			return arg, true
+		}
+	}
+	return DecodedArg{}, false
+}
+
+// String returns the call in pallet.call form
+func (dc *DecodedCall) String() string {
+	return dc.Pallet + "." + dc.Call
+}
+
+func (dc *DecodedCall) walkValues(fn func(interface{})) {
+	for _, arg := range dc.Args {
+		walkValue(arg.Value, fn)
+	}
+}
+
+func walkValue(v interface{}, fn func(interface{})) {
+	fn(v)
+	switch val := v.(type) {
+	case *DecodedCall:
+		val.walkValues(fn)
+	case map[string]interface{}:
+		for _, inner := range val {
+			walkValue(inner, fn)
+		}
+	case []interface{}:
+		for _, inner := range val {
+			walkValue(inner, fn)
+		}
+	}
+}
+
+func collectCalls(v interface{}, out *[]*DecodedCall) {
+	switch val := v.(type) {
+	case *DecodedCall:
+		*out = append(*out, val)
+	case map[string]interface{}:
+		for _, inner := range val {
+			collectCalls(inner, out)
+		}
+	case []interface{}:
+		for _, inner := range val {
+			collectCalls(inner, out)
+		}
+	}
+}
+
+// DecodeCall decodes SCALE-encoded call data using the runtime metadata.
+// Pallet and call indices are resolved from metadata, so the result stays
+// correct across runtimes and chains with different pallet layouts.
+func (c *Client) DecodeCall(data []byte) (*DecodedCall, error) {
+	metadata := c.runtimeMetadata()
+	if metadata == nil {
+		return nil, fmt.Errorf("metadata not loaded")
+	}
+	if metadata.Version < 14 {
+		return nil, fmt.Errorf("unsupported metadata version %d", metadata.Version)
+	}
+
+	d := newCallDecoder(&metadata.AsMetadataV14, c.accountIDToSS58, data)
+	call, err := d.decodeCall(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.remaining() > 0 {
+		return nil, fmt.Errorf("%d trailing bytes after %s", d.remaining(), call)
+	}
+	return call, nil
+}
+
+// callDecoder walks SCALE data guided by the metadata type registry
+type callDecoder struct {
+	meta          *types.MetadataV14
+	lookup        map[int64]*types.Si1Type
+	runtimeCalls  map[int64]bool
+	encodeAccount func(types.AccountID) string
+	data          []byte
+	pos           int
+}
+
+func newCallDecoder(meta *types.MetadataV14, encodeAccount func(types.AccountID) string, data []byte) *callDecoder {
+	d := &callDecoder{
+		meta:          meta,
+		lookup:        metadataLookup(meta),
+		encodeAccount: encodeAccount,
+		data:          data,
+	}
+	d.runtimeCalls = d.findRuntimeCallTypes()
+	return d
+}
+
+// metadataLookup indexes the metadata type registry by type ID
+func metadataLookup(meta *types.MetadataV14) map[int64]*types.Si1Type {
+	if meta.EfficientLookup != nil {
+		return meta.EfficientLookup
+	}
+	lookup := make(map[int64]*types.Si1Type, len(meta.Lookup.Types))
+	for i := range meta.Lookup.Types {
+		t := &meta.Lookup.Types[i]
+		lookup[t.ID.Int64()] = &t.Type
+	}
+	return lookup
+}
+
+// findRuntimeCallTypes identifies the outer call enum(s): variant types whose
+// every variant wraps a pallet call enum at that pallet's index
+func (d *callDecoder) findRuntimeCallTypes() map[int64]bool {
+	palletCalls := make(map[uint8]int64)
+	for _, p := range d.meta.Pallets {
+		if p.HasCalls {
+			palletCalls[uint8(p.Index)] = p.Calls.Type.Int64()
+		}
+	}
+
+	result := make(map[int64]bool)
+	if len(palletCalls) == 0 {
+		return result
+	}
+
+	for id, typ := range d.lookup {
+		if !typ.Def.IsVariant || len(typ.Def.Variant.Variants) == 0 {
+			continue
+		}
+		matches := true
+		for _, v := range typ.Def.Variant.Variants {
+			callType, ok := palletCalls[uint8(v.Index)]
+			if !ok || len(v.Fields) != 1 || v.Fields[0].Type.Int64() != callType {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			result[id] = true
+		}
+	}
+	return result
+}
+
+func (d *callDecoder) remaining() int {
+	return len(d.data) - d.pos
+}
+
+func (d *callDecoder) read(n int) ([]byte, error) {
+	if n < 0 || d.remaining() < n {
+		return nil, fmt.Errorf("insufficient data: need %d bytes at offset %d, have %d", n, d.pos, d.remaining())
+	}
+	out := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return out, nil
+}
+
+func (d *callDecoder) readByte() (byte, error) {
+	b, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// readCompact reads a SCALE compact integer of arbitrary size
+func (d *callDecoder) readCompact() (*big.Int, error) {
+	first, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch first & 0x03 {
+	case 0:
+		return big.NewInt(int64(first >> 2)), nil
+	case 1:
+		next, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+		return big.NewInt(int64(binary.LittleEndian.Uint16([]byte{first, next}) >> 2)), nil
+	case 2:
+		rest, err := d.read(3)
+		if err != nil {
+			return nil, err
+		}
+		return big.NewInt(int64(binary.LittleEndian.Uint32([]byte{first, rest[0], rest[1], rest[2]}) >> 2)), nil
+	default:
+		n := int(first>>2) + 4
+		raw, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		return leToBigInt(raw), nil
+	}
+}
+
+func (d *callDecoder) readLength() (int, error) {
+	n, err := d.readCompact()
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsInt64() || n.Int64() > int64(d.remaining()) {
+		return 0, fmt.Errorf("invalid length %s at offset %d", n.String(), d.pos)
+	}
+	return int(n.Int64()), nil
+}
+
+// decodeCall reads a pallet index, call index and the call's fields
+func (d *callDecoder) decodeCall(depth int) (*DecodedCall, error) {
+	if depth > maxCallDepth {
+		return nil, fmt.Errorf("call nesting too deep")
+	}
+
+	palletIndex, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	callIndex, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var pallet *types.PalletMetadataV14
+	for i := range d.meta.Pallets {
+		if uint8(d.meta.Pallets[i].Index) == palletIndex && d.meta.Pallets[i].HasCalls {
+			pallet = &d.meta.Pallets[i]
+			break
+		}
+	}
+	if pallet == nil {
+		return nil, fmt.Errorf("unknown pallet index %d", palletIndex)
+	}
+
+	callType, ok := d.lookup[pallet.Calls.Type.Int64()]
+	if !ok || !callType.Def.IsVariant {
+		return nil, fmt.Errorf("pallet %s has no call enum", pallet.Name)
+	}
+
+	var variant *types.Si1Variant
+	for i := range callType.Def.Variant.Variants {
+		if uint8(callType.Def.Variant.Variants[i].Index) == callIndex {
+			variant = &callType.Def.Variant.Variants[i]
+			break
+		}
+	}
+	if variant == nil {
+		return nil, fmt.Errorf("unknown call index %d in pallet %s", callIndex, pallet.Name)
+	}
+
+	call := &DecodedCall{
+		Pallet:      string(pallet.Name),
+		PalletIndex: palletIndex,
+		Call:        string(variant.Name),
+		CallIndex:   callIndex,
+		Args:        make([]DecodedArg, 0, len(variant.Fields)),
+	}
+
+	for i, field := range variant.Fields {
+		value, err := d.decodeValue(field.Type.Int64(), depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("%s arg %d: %w", call, i, err)
+		}
+		name := string(field.Name)
+		if !field.HasName {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		arg := DecodedArg{Name: name, Value: value}
+		if field.HasTypeName {
+			arg.TypeName = string(field.TypeName)
+		}
+		call.Args = append(call.Args, arg)
+	}
+
+	return call, nil
+}
+
+// decodeValue decodes a value of the given registry type
+func (d *callDecoder) decodeValue(typeID int64, depth int) (interface{}, error) {
+	if depth > maxCallDepth {
+		return nil, fmt.Errorf("type nesting too deep")
+	}
+
+	if d.runtimeCalls[typeID] {
+		return d.decodeCall(depth)
+	}
+
+	typ, ok := d.lookup[typeID]
+	if !ok {
+		return nil, fmt.Errorf("unknown type id %d", typeID)
+	}
+	def := typ.Def
+
+	switch {
+	case def.IsComposite:
+		if typePathEnds(typ, "AccountId32") {
+			raw, err := d.read(32)
+			if err != nil {
+				return nil, err
+			}
+			var accountID types.AccountID
+			copy(accountID[:], raw)
+			return AccountValue(d.encodeAccount(accountID)), nil
+		}
+		return d.decodeFields(def.Composite.Fields, depth)
+
+	case def.IsVariant:
+		return d.decodeVariant(typ, depth)
+
+	case def.IsSequence:
+		n, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		if d.isU8(def.Sequence.Type.Int64()) {
+			raw, err := d.read(n)
+			if err != nil {
+				return nil, err
+			}
+			return codec.HexEncodeToString(raw), nil
+		}
+		items := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			item, err := d.decodeValue(def.Sequence.Type.Int64(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+
+	case def.IsArray:
+		n := int(def.Array.Len)
+		if d.isU8(def.Array.Type.Int64()) {
+			raw, err := d.read(n)
+			if err != nil {
+				return nil, err
+			}
+			return codec.HexEncodeToString(raw), nil
+		}
+		items := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			item, err := d.decodeValue(def.Array.Type.Int64(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+
+	case def.IsTuple:
+		if len(def.Tuple) == 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, 0, len(def.Tuple))
+		for _, elem := range def.Tuple {
+			item, err := d.decodeValue(elem.Int64(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+
+	case def.IsPrimitive:
+		return d.decodePrimitive(def.Primitive.Si0TypeDefPrimitive)
+
+	case def.IsCompact:
+		n, err := d.readCompact()
+		if err != nil {
+			return nil, err
+		}
+		if d.isWide(def.Compact.Type.Int64()) {
+			return n.String(), nil
+		}
+		return bigIntValue(n), nil
+
+	case def.IsBitSequence:
+		bits, err := d.readCompact()
+		if err != nil {
+			return nil, err
+		}
+		if !bits.IsInt64() {
+			return nil, fmt.Errorf("invalid bit sequence length %s", bits.String())
+		}
+		raw, err := d.read(int((bits.Int64() + 7) / 8))
+		if err != nil {
+			return nil, err
+		}
+		return codec.HexEncodeToString(raw), nil
+	}
+
+	return nil, fmt.Errorf("unsupported type definition for type id %d", typeID)
+}
+
+// decodeFields decodes composite fields, unwrapping single-field newtypes
+func (d *callDecoder) decodeFields(fields []types.Si1Field, depth int) (interface{}, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	if len(fields) == 1 && !fields[0].HasName {
+		return d.decodeValue(fields[0].Type.Int64(), depth+1)
+	}
+
+	named := true
+	for _, f := range fields {
+		if !f.HasName {
+			named = false
+			break
+		}
+	}
+
+	if named {
+		out := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			value, err := d.decodeValue(f.Type.Int64(), depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", f.Name, err)
+			}
+			out[string(f.Name)] = value
+		}
+		return out, nil
+	}
+
+	out := make([]interface{}, 0, len(fields))
+	for _, f := range fields {
+		value, err := d.decodeValue(f.Type.Int64(), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, value)
+	}
+	return out, nil
+}
+
+// decodeVariant decodes an enum value as its variant name or {name: fields}
+func (d *callDecoder) decodeVariant(typ *types.Si1Type, depth int) (interface{}, error) {
+	index, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var variant *types.Si1Variant
+	for i := range typ.Def.Variant.Variants {
+		if uint8(typ.Def.Variant.Variants[i].Index) == index {
+			variant = &typ.Def.Variant.Variants[i]
+			break
+		}
+	}
+	if variant == nil {
+		return nil, fmt.Errorf("unknown variant index %d for %s", index, typePath(typ))
+	}
+
+	name := string(variant.Name)
+
+	// Option<T> collapses to nil or the inner value
+	if typePathEnds(typ, "Option") {
+		if name == "None" {
+			return nil, nil
+		}
+		return d.decodeFields(variant.Fields, depth)
+	}
+
+	if len(variant.Fields) == 0 {
+		return name, nil
+	}
+
+	// Bounded<Call> stores small calls inline as encoded bytes
+	if name == "Inline" && typePathEnds(typ, "Bounded") && len(variant.Fields) == 1 {
+		value, err := d.decodeValue(variant.Fields[0].Type.Int64(), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if encoded, ok := value.(string); ok {
+			if raw, err := DecodeHex(encoded); err == nil {
+				inner := &callDecoder{
+					meta:          d.meta,
+					lookup:        d.lookup,
+					runtimeCalls:  d.runtimeCalls,
+					encodeAccount: d.encodeAccount,
+					data:          raw,
+				}
+				if call, err := inner.decodeCall(depth + 1); err == nil && inner.remaining() == 0 {
+					return map[string]interface{}{name: call}, nil
+				}
+			}
+		}
+		return map[string]interface{}{name: value}, nil
+	}
+
+	value, err := d.decodeFields(variant.Fields, depth)
+	if err != nil {
+		return nil, fmt.Errorf("variant %s: %w", name, err)
+	}
+	return map[string]interface{}{name: value}, nil
+}
+
+func (d *callDecoder) decodePrimitive(p types.Si0TypeDefPrimitive) (interface{}, error) {
+	switch p {
+	case types.IsBool:
+		b, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+		return b != 0, nil
+	case types.IsChar:
+		raw, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return string(rune(binary.LittleEndian.Uint32(raw))), nil
+	case types.IsStr:
+		n, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		raw, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		return string(raw), nil
+	case types.IsU8:
+		b, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+		return uint64(b), nil
+	case types.IsU16:
+		raw, err := d.read(2)
+		if err != nil {
+			return nil, err
+		}
+		return uint64(binary.LittleEndian.Uint16(raw)), nil
+	case types.IsU32:
+		raw, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return uint64(binary.LittleEndian.Uint32(raw)), nil
+	case types.IsU64:
+		raw, err := d.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.Uint64(raw), nil
+	case types.IsU128:
+		raw, err := d.read(16)
+		if err != nil {
+			return nil, err
+		}
+		return leToBigInt(raw).String(), nil
+	case types.IsU256:
+		raw, err := d.read(32)
+		if err != nil {
+			return nil, err
+		}
+		return leToBigInt(raw).String(), nil
+	case types.IsI8:
+		b, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+		return int64(int8(b)), nil
+	case types.IsI16:
+		raw, err := d.read(2)
+		if err != nil {
+			return nil, err
+		}
+		return int64(int16(binary.LittleEndian.Uint16(raw))), nil
+	case types.IsI32:
+		raw, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return int64(int32(binary.LittleEndian.Uint32(raw))), nil
+	case types.IsI64:
+		raw, err := d.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return int64(binary.LittleEndian.Uint64(raw)), nil
+	case types.IsI128:
+		raw, err := d.read(16)
+		if err != nil {
+			return nil, err
+		}
+		return signedLEToBigInt(raw).String(), nil
+	case types.IsI256:
+		raw, err := d.read(32)
+		if err != nil {
+			return nil, err
+		}
+		return signedLEToBigInt(raw).String(), nil
+	}
+	return nil, fmt.Errorf("unsupported primitive %d", p)
+}
+
+func (d *callDecoder) isU8(typeID int64) bool {
+	typ, ok := d.lookup[typeID]
+	return ok && typ.Def.IsPrimitive && typ.Def.Primitive.Si0TypeDefPrimitive == types.IsU8
+}
+
+// isWide reports whether the type is a 128/256-bit integer, which is rendered
+// as a decimal string so balances keep a stable JSON type
+func (d *callDecoder) isWide(typeID int64) bool {
+	typ, ok := d.lookup[typeID]
+	if !ok || !typ.Def.IsPrimitive {
+		return false
+	}
+	switch typ.Def.Primitive.Si0TypeDefPrimitive {
+	case types.IsU128, types.IsU256, types.IsI128, types.IsI256:
+		return true
+	}
+	return false
+}
+
+func typePath(typ *types.Si1Type) string {
+	parts := make([]string, len(typ.Path))
+	for i, p := range typ.Path {
+		parts[i] = string(p)
+	}
+	return strings.Join(parts, "::")
+}
+
+func typePathEnds(typ *types.Si1Type, name string) bool {
+	return len(typ.Path) > 0 && string(typ.Path[len(typ.Path)-1]) == name
+}
+
+// leToBigInt converts little-endian bytes to an unsigned big.Int
+func leToBigInt(raw []byte) *big.Int {
+	reversed := make([]byte, len(raw))
+	for i := range raw {
+		reversed[i] = raw[len(raw)-1-i]
+	}
+	return new(big.Int).SetBytes(reversed)
+}
+
+func signedLEToBigInt(raw []byte) *big.Int {
+	n := leToBigInt(raw)
+	if len(raw) > 0 && raw[len(raw)-1]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(raw)*8)))
+	}
+	return n
+}
+
+// bigIntValue keeps small numbers numeric and renders large ones as strings
+func bigIntValue(n *big.Int) interface{} {
+	if n.IsUint64() {
+		return n.Uint64()
+	}
+	return n.String()
+}
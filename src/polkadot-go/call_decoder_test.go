@@ -0,0 +1,104 @@
+package polkadot
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+)
+
+// testMetadataClient returns a client holding the Substrate node runtime
+// metadata that ships with gsrpc, encoding accounts with the Polkadot prefix
+func testMetadataClient(t *testing.T) *Client {
+	t.Helper()
+	var meta types.Metadata
+	if err := codec.DecodeFromHex(types.MetadataV14Data, &meta); err != nil {
+		t.Fatalf("decode metadata: %v", err)
+	}
+	return &Client{metadata: &meta, ss58Cached: true}
+}
+
+// aliceAccount is the //Alice dev account ID
+const aliceAccount = "d43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"
+
+// SCALE-encoded calls against the gsrpc test metadata
+const (
+	// Treasury.propose_spend(value: 1,250.5 DOT, beneficiary: Id(//Alice))
+	treasurySpendCall = "12000b003a158c5f0b00d43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"
+	// Utility.batch_all([Treasury.propose_spend(...), Treasury.approve_proposal(7)])
+	batchedSpendCall = "01020812000b003a158c5f0b00d43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d12021c"
+)
+
+func TestDecodeCall(t *testing.T) {
+	client := testMetadataClient(t)
+
+	for name, encoded := range map[string]string{
+		"Treasury.propose_spend": treasurySpendCall,
+		"Utility.batch_all":      batchedSpendCall,
+	} {
+		call, err := client.DecodeCall(mustDecodeHex(t, encoded))
+		if err != nil {
+			t.Fatalf("DecodeCall(%s): %v", name, err)
+		}
+		if call.String() != name {
+			t.Fatalf("DecodeCall = %s, want %s", call, name)
+		}
+
+		summary := NewProposalCall("0x00", call, 10, "DOT", client.SpendBeneficiary)
+		if len(summary.Amounts) != 1 || summary.Amounts[0].Amount != "1,250.5 DOT" || summary.Amounts[0].Call != "Treasury.propose_spend" {
+			t.Fatalf("%s: Amounts = %+v, want 1,250.5 DOT from Treasury.propose_spend", name, summary.Amounts)
+		}
+		if len(summary.Beneficiaries) != 1 {
+			t.Fatalf("%s: Beneficiaries = %v, want one", name, summary.Beneficiaries)
+		}
+		if account, err := SS58ToAccountID(summary.Beneficiaries[0]); err != nil || hex.EncodeToString(account[:]) != aliceAccount {
+			t.Fatalf("%s: beneficiary %s is not //Alice (%v)", name, summary.Beneficiaries[0], err)
+		}
+	}
+}
+
+func TestDecodeCallRejectsMalformedData(t *testing.T) {
+	client := testMetadataClient(t)
+
+	// Call data and the error it should produce
+	for encoded, want := range map[string]string{
+		"12021c00":                             "1 trailing bytes after Treasury.approve_proposal",
+		"12000b003a158c5f0b00d43593c715fdd31c": "insufficient data",
+		"fe00":                                 "254",
+	} {
+		_, err := client.DecodeCall(mustDecodeHex(t, encoded))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("DecodeCall(%s) error = %v, want %q", encoded, err, want)
+		}
+	}
+}
+
+func TestPreimageAccountsNeedMetadata(t *testing.T) {
+	pd := NewPreimageDecoder(testMetadataClient(t))
+
+	addresses := make(map[string]bool)
+	if err := pd.decodeCallAndExtractAddresses(mustDecodeHex(t, batchedSpendCall), addresses); err != nil {
+		t.Fatalf("decodeCallAndExtractAddresses: %v", err)
+	}
+	if len(addresses) != 1 {
+		t.Fatalf("addresses = %v, want //Alice only", addresses)
+	}
+
+	// A call the metadata cannot decode is an error, not a guess from
+	// hard-coded pallet indices
+	addresses = make(map[string]bool)
+	if err := pd.decodeCallAndExtractAddresses(mustDecodeHex(t, "fe00"+aliceAccount), addresses); err == nil {
+		t.Fatalf("undecodable call gave addresses %v", addresses)
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("bad fixture %q: %v", s, err)
+	}
+	return data
+}
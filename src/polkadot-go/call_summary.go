@@ -0,0 +1,311 @@
+package polkadot
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProposalCall is the persisted, human-readable form of a referendum proposal
+type ProposalCall struct {
+	PreimageHash  string       `json:"preimageHash"`
+	Symbol        string       `json:"symbol,omitempty"`
+	Decimals      uint8        `json:"decimals"`
+	Call          *DecodedCall `json:"call"`
+	Amounts       []CallAmount `json:"amounts,omitempty"`
+	Beneficiaries []string     `json:"beneficiaries,omitempty"`
+	Accounts      []string     `json:"accounts,omitempty"`
+	DecodedAt     time.Time    `json:"decodedAt"`
+}
+
+// CallAmount is a balance argument converted to token units
+type CallAmount struct {
+	Call   string `json:"call"`
+	Arg    string `json:"arg"`
+	Raw    string `json:"raw"`
+	Amount string `json:"amount"`
+	Asset  string `json:"asset,omitempty"`
+}
+
+type assetInfo struct {
+	symbol   string
+	decimals uint8
+}
+
+// knownAssets maps Asset Hub asset ids used by treasury spends to their units
+var knownAssets = map[uint64]assetInfo{
+	1984: {symbol: "USDT", decimals: 6},
+	1337: {symbol: "USDC", decimals: 6},
+}
+
+// beneficiaryArgs are argument names that identify who receives funds
+var beneficiaryArgs = map[string]bool{
+	"beneficiary": true,
+	"dest":        true,
+	"to":          true,
+	"target":      true,
+	"who":         true,
+}
+
+// NewProposalCall annotates a decoded call with token amounts and beneficiaries.
+// beneficiary resolves a beneficiary argument to its SS58 address, as
+// Client.SpendBeneficiary does for accounts inside XCM locations.
+func NewProposalCall(preimageHash string, call *DecodedCall, decimals uint8, symbol string, beneficiary func(interface{}) string) *ProposalCall {
+	pc := &ProposalCall{
+		PreimageHash: preimageHash,
+		Symbol:       symbol,
+		Decimals:     decimals,
+		Call:         call,
+		DecodedAt:    time.Now().UTC(),
+	}
+	if call == nil {
+		return pc
+	}
+
+	seen := make(map[string]bool)
+	pc.annotate(call, seen, beneficiary)
+	pc.Accounts = call.Accounts()
+	for _, address := range pc.Beneficiaries {
+		if !containsString(pc.Accounts, address) {
+			pc.Accounts = append(pc.Accounts, address)
+		}
+	}
+	return pc
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseProposalCall decodes a persisted proposal call
+func ParseProposalCall(data []byte) (*ProposalCall, error) {
+	var pc ProposalCall
+	if err := json.Unmarshal(data, &pc); err != nil {
+		return nil, fmt.Errorf("parse proposal call: %w", err)
+	}
+	return &pc, nil
+}
+
+func (pc *ProposalCall) annotate(call *DecodedCall, seen map[string]bool, beneficiary func(interface{}) string) {
+	asset := assetInfo{symbol: pc.Symbol, decimals: pc.Decimals}
+	known := true
+	if kind, ok := call.Arg("asset_kind"); ok {
+		asset, known = resolveAsset(kind.Value, asset)
+	}
+
+	for i := range call.Args {
+		arg := &call.Args[i]
+
+		if strings.Contains(arg.TypeName, "Balance") {
+			if raw, ok := balanceString(arg.Value); ok {
+				amount := CallAmount{
+					Call:  call.String(),
+					Arg:   arg.Name,
+					Raw:   raw,
+					Asset: asset.symbol,
+				}
+				if known {
+					amount.Amount = FormatTokenAmount(raw, asset.decimals, asset.symbol)
+					arg.Display = amount.Amount
+				} else {
+					amount.Amount = raw
+				}
+				pc.Amounts = append(pc.Amounts, amount)
+			}
+		}
+
+		if beneficiaryArgs[arg.Name] {
+			addresses := []string{beneficiary(arg.Value)}
+			walkValue(arg.Value, func(v interface{}) {
+				if acc, ok := v.(AccountValue); ok {
+					addresses = append(addresses, string(acc))
+				}
+			})
+			for _, address := range addresses {
+				if address != "" && !seen[address] {
+					seen[address] = true
+					pc.Beneficiaries = append(pc.Beneficiaries, address)
+				}
+			}
+		}
+
+		var nested []*DecodedCall
+		collectCalls(arg.Value, &nested)
+		for _, inner := range nested {
+			pc.annotate(inner, seen, beneficiary)
+		}
+	}
+}
+
+// resolveAsset inspects a treasury asset_kind for a known Asset Hub asset id.
+// Locations without a GeneralIndex refer to the native token.
+func resolveAsset(kind interface{}, native assetInfo) (assetInfo, bool) {
+	var index *big.Int
+	walkValue(kind, func(v interface{}) {
+		if index != nil {
+			return
+		}
+		if m, ok := v.(map[string]interface{}); ok {
+			if raw, ok := m["GeneralIndex"]; ok {
+				if s, ok := balanceString(raw); ok {
+					index, _ = new(big.Int).SetString(s, 10)
+				}
+			}
+		}
+	})
+
+	if index == nil {
+		return native, true
+	}
+	if index.IsUint64() {
+		if info, ok := knownAssets[index.Uint64()]; ok {
+			return info, true
+		}
+	}
+	return assetInfo{symbol: fmt.Sprintf("asset #%s", index.String())}, false
+}
+
+// balanceString normalises a decoded integer value to a decimal string
+func balanceString(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		if _, ok := new(big.Int).SetString(val, 10); ok {
+			return val, true
+		}
+	case uint64:
+		return fmt.Sprintf("%d", val), true
+	case int64:
+		return fmt.Sprintf("%d", val), true
+	case json.Number:
+		if _, ok := new(big.Int).SetString(val.String(), 10); ok {
+			return val.String(), true
+		}
+	}
+	return "", false
+}
+
+// FormatTokenAmount converts a raw planck amount into token units, e.g. "1,250.5 DOT"
+func FormatTokenAmount(raw string, decimals uint8, symbol string) string {
+	value, ok := new(big.Int).SetString(raw, 10)
+	if !ok {
+		return raw
+	}
+
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, frac := new(big.Int).QuoRem(value, divisor, new(big.Int))
+
+	result := groupThousands(whole.String())
+	if frac.Sign() != 0 {
+		fracStr := frac.String()
+		fracStr = strings.Repeat("0", int(decimals)-len(fracStr)) + fracStr
+		fracStr = strings.TrimRight(fracStr, "0")
+		result += "." + fracStr
+	}
+
+	if symbol != "" {
+		result += " " + symbol
+	}
+	return result
+}
+
+func groupThousands(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+// Lines renders the call tree as indented text for reports and prompts
+func (pc *ProposalCall) Lines() []string {
+	if pc == nil || pc.Call == nil {
+		return nil
+	}
+	var lines []string
+	describeCall(pc.Call, 0, &lines)
+	return lines
+}
+
+func describeCall(call *DecodedCall, depth int, lines *[]string) {
+	indent := strings.Repeat("  ", depth)
+	*lines = append(*lines, fmt.Sprintf("%s%s", indent, call.String()))
+
+	for _, arg := range call.Args {
+		var nested []*DecodedCall
+		collectCalls(arg.Value, &nested)
+		if len(nested) > 0 {
+			*lines = append(*lines, fmt.Sprintf("%s  %s: %d call(s)", indent, arg.Name, len(nested)))
+			for _, inner := range nested {
+				describeCall(inner, depth+2, lines)
+			}
+			continue
+		}
+
+		display := arg.Display
+		if display == "" {
+			display = formatArgValue(arg.Value)
+		}
+		*lines = append(*lines, fmt.Sprintf("%s  %s: %s", indent, arg.Name, display))
+	}
+}
+
+// formatArgValue renders a decoded value compactly, truncating long output
+func formatArgValue(v interface{}) string {
+	const maxLen = 160
+
+	var out string
+	switch val := v.(type) {
+	case nil:
+		out = "none"
+	case string:
+		out = val
+	case AccountValue:
+		out = string(val)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			if val[k] == nil {
+				parts = append(parts, k)
+				continue
+			}
+			parts = append(parts, fmt.Sprintf("%s(%s)", k, formatArgValue(val[k])))
+		}
+		out = strings.Join(parts, ", ")
+	case []interface{}:
+		parts := make([]string, 0, len(val))
+		for _, inner := range val {
+			parts = append(parts, formatArgValue(inner))
+		}
+		out = "[" + strings.Join(parts, ", ") + "]"
+	default:
+		out = fmt.Sprintf("%v", val)
+	}
+
+	if runes := []rune(out); len(runes) > maxLen {
+		out = string(runes[:maxLen-3]) + "..."
+	}
+	return out
+}
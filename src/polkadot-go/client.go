@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
 	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
@@ -13,6 +14,9 @@ import (
 
 // Client is a Polkadot RPC client
 type Client struct {
+	// Connection to the active endpoint, swapped on failover while other
+	// goroutines use the client; read through rpc and runtimeMetadata
+	connMu   sync.RWMutex
 	api      *gsrpc.SubstrateAPI
 	metadata *types.Metadata
 
@@ -23,26 +27,57 @@ type Client struct {
 	// SS58 prefix cache
 	ss58Prefix uint16
 	ss58Cached bool
+
+	// Native token cache
+	tokenDecimals uint8
+	tokenSymbol   string
+	tokenCached   bool
+
+	// Referenda pallet instance and whether it uses ranked collective tallies
+	referendaPallet string
+	rankedTally     bool
+
+	// RPC endpoints and the index of the connected one
+	endpoints []*endpoint
+	active    int
+	retryAt   time.Time
+	backoff   time.Duration
 }
 
 // NewClient creates a new Polkadot client
 func NewClient(url string) (*Client, error) {
-	api, err := gsrpc.NewSubstrateAPI(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect: %w", err)
-	}
+	return NewClientWithEndpoints([]string{url})
+}
 
-	// Get metadata
-	meta, err := api.RPC.State.GetMetadataLatest()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get metadata: %w", err)
+// NewClientWithEndpoints creates a client over several RPC endpoints for the
+// same chain. It connects to the first reachable one and can fail over to the
+// others (see CheckHealth).
+func NewClientWithEndpoints(urls []string) (*Client, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no RPC endpoints configured")
 	}
 
 	client := &Client{
-		api:            api,
-		metadata:       meta,
 		constantsCache: make(map[string]interface{}),
 	}
+	for _, url := range urls {
+		client.endpoints = append(client.endpoints, &endpoint{status: EndpointStatus{URL: url}})
+	}
+
+	var lastErr error
+	for idx, ep := range client.endpoints {
+		api, meta, err := connect(ep.status.URL)
+		if err != nil {
+			ep.recordFailure(err)
+			lastErr = err
+			continue
+		}
+		client.use(idx, api, meta)
+		break
+	}
+	if client.rpc() == nil {
+		return nil, lastErr
+	}
 
 	// Pre-cache SS58 prefix
 	if prefix, err := client.GetSS58Prefix(); err == nil {
@@ -53,6 +88,25 @@ func NewClient(url string) (*Client, error) {
 	return client, nil
 }
 
+// connect opens an API connection and loads the runtime metadata
+func connect(url string) (*gsrpc.SubstrateAPI, *types.Metadata, error) {
+	api, err := withTimeout(connectTimeout, func() (*gsrpc.SubstrateAPI, error) {
+		return gsrpc.NewSubstrateAPI(url)
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect: %w", err)
+	}
+
+	// Get metadata
+	meta, err := api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		api.Client.Close()
+		return nil, nil, fmt.Errorf("failed to get metadata: %w", err)
+	}
+
+	return api, meta, nil
+}
+
 // GetCachedSS58Prefix returns the cached SS58 prefix
 func (c *Client) GetCachedSS58Prefix() uint16 {
 	if c.ss58Cached {
@@ -62,9 +116,74 @@ func (c *Client) GetCachedSS58Prefix() uint16 {
 	return 42
 }
 
+// GetTokenInfo returns the native token decimals and symbol from chain properties
+func (c *Client) GetTokenInfo() (uint8, string, error) {
+	if c.tokenCached {
+		return c.tokenDecimals, c.tokenSymbol, nil
+	}
+
+	props, err := c.rpc().RPC.System.Properties()
+	if err != nil {
+		return 0, "", fmt.Errorf("get chain properties: %w", err)
+	}
+
+	if props.IsTokenDecimals {
+		c.tokenDecimals = uint8(props.AsTokenDecimals)
+	}
+	if props.IsTokenSymbol {
+		c.tokenSymbol = string(props.AsTokenSymbol)
+	}
+	c.tokenCached = true
+
+	return c.tokenDecimals, c.tokenSymbol, nil
+}
+
+// DefaultBlockTime is the block time assumed when the chain does not expose one
+const DefaultBlockTime = 6 * time.Second
+
+// ExpectedBlockTime returns the chain's target block time, read from
+// Babe.ExpectedBlockTime on relay chains or twice Timestamp.MinimumPeriod on
+// Aura parachains, falling back to DefaultBlockTime
+func (c *Client) ExpectedBlockTime() time.Duration {
+	c.constantsMu.RLock()
+	cached, exists := c.constantsCache["block_time"]
+	c.constantsMu.RUnlock()
+	if exists {
+		return cached.(time.Duration)
+	}
+
+	blockTime := DefaultBlockTime
+	if data, err := c.getConstantValue("Babe", "ExpectedBlockTime"); err == nil && len(data) >= 8 && binary.LittleEndian.Uint64(data) > 0 {
+		blockTime = time.Duration(binary.LittleEndian.Uint64(data)) * time.Millisecond
+	} else if data, err := c.getConstantValue("Timestamp", "MinimumPeriod"); err == nil && len(data) >= 8 && binary.LittleEndian.Uint64(data) > 0 {
+		blockTime = 2 * time.Duration(binary.LittleEndian.Uint64(data)) * time.Millisecond
+	}
+
+	c.constantsMu.Lock()
+	c.constantsCache["block_time"] = blockTime
+	c.constantsMu.Unlock()
+	return blockTime
+}
+
+// rpc returns the API of the connected endpoint
+func (c *Client) rpc() *gsrpc.SubstrateAPI {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+	return c.api
+}
+
+// runtimeMetadata returns the metadata of the connected endpoint's runtime
+func (c *Client) runtimeMetadata() *types.Metadata {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+	return c.metadata
+}
+
 // Close closes the connection
 func (c *Client) Close() error {
-	// No explicit close needed for gsrpc
+	if api := c.rpc(); api != nil && api.Client != nil {
+		api.Client.Close()
+	}
 	return nil
 }
 
@@ -85,7 +204,7 @@ func (c *Client) GetStorage(key string, at *string) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		ok, err := c.api.RPC.State.GetStorage(storageKey, &raw, hash)
+		ok, err := c.rpc().RPC.State.GetStorage(storageKey, &raw, hash)
 		if err != nil {
 			return "", err
 		}
@@ -93,7 +212,7 @@ func (c *Client) GetStorage(key string, at *string) (string, error) {
 			return "", nil
 		}
 	} else {
-		ok, err := c.api.RPC.State.GetStorageLatest(storageKey, &raw)
+		ok, err := c.rpc().RPC.State.GetStorageLatest(storageKey, &raw)
 		if err != nil {
 			return "", err
 		}
@@ -124,13 +243,13 @@ func (c *Client) GetBlockHash(height *uint64) (string, error) {
 	var hash types.Hash
 
 	if height != nil {
-		h, err := c.api.RPC.Chain.GetBlockHash(*height)
+		h, err := c.rpc().RPC.Chain.GetBlockHash(*height)
 		if err != nil {
 			return "", err
 		}
 		hash = h
 	} else {
-		h, err := c.api.RPC.Chain.GetBlockHashLatest()
+		h, err := c.rpc().RPC.Chain.GetBlockHashLatest()
 		if err != nil {
 			return "", err
 		}
@@ -151,9 +270,9 @@ func (c *Client) GetHeader(hash *string) (*Header, error) {
 		if err != nil {
 			return nil, err
 		}
-		header, err = c.api.RPC.Chain.GetHeader(h)
+		header, err = c.rpc().RPC.Chain.GetHeader(h)
 	} else {
-		header, err = c.api.RPC.Chain.GetHeaderLatest()
+		header, err = c.rpc().RPC.Chain.GetHeaderLatest()
 	}
 
 	if err != nil {
@@ -168,6 +287,37 @@ func (c *Client) GetHeader(hash *string) (*Header, error) {
 	}, nil
 }
 
+// GetFinalizedHead returns the number and hash of the latest finalized block
+func (c *Client) GetFinalizedHead() (uint64, string, error) {
+	hash, err := c.rpc().RPC.Chain.GetFinalizedHead()
+	if err != nil {
+		return 0, "", err
+	}
+
+	header, err := c.rpc().RPC.Chain.GetHeader(hash)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return uint64(header.Number), codec.HexEncodeToString(hash[:]), nil
+}
+
+// GetBlockTimestamp returns the Timestamp.Now value set in a block
+func (c *Client) GetBlockTimestamp(blockHash string) (time.Time, error) {
+	value, err := c.GetStorage(StorageKey("Timestamp", "Now"), &blockHash)
+	if err != nil {
+		return time.Time{}, err
+	}
+	data, err := DecodeHex(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(data) < 8 {
+		return time.Time{}, fmt.Errorf("timestamp not set at %s", blockHash)
+	}
+	return time.UnixMilli(int64(binary.LittleEndian.Uint64(data))).UTC(), nil
+}
+
 // GetKeys gets all storage keys with a specific prefix
 func (c *Client) GetKeys(prefix string, at *string) ([]string, error) {
 	var keys []types.StorageKey
@@ -183,13 +333,13 @@ func (c *Client) GetKeys(prefix string, at *string) ([]string, error) {
 		if err != nil {
 			return nil, err
 		}
-		k, err := c.api.RPC.State.GetKeys(prefixBytes, hash)
+		k, err := c.rpc().RPC.State.GetKeys(prefixBytes, hash)
 		if err != nil {
 			return nil, err
 		}
 		keys = k
 	} else {
-		k, err := c.api.RPC.State.GetKeysLatest(prefixBytes)
+		k, err := c.rpc().RPC.State.GetKeysLatest(prefixBytes)
 		if err != nil {
 			return nil, err
 		}
@@ -213,7 +363,7 @@ func (c *Client) RPC(method string, params []interface{}) (json.RawMessage, erro
 // GetReferendumInfoAt gets referendum info at a specific block
 func (c *Client) GetReferendumInfoAt(refID uint32, blockHash string) (*ReferendumInfo, error) {
 	// Create storage key for ReferendumInfoFor
-	key := createReferendumStorageKey(refID)
+	key := createReferendumStorageKey(c.ReferendaPallet(), refID)
 
 	// Query storage at specific block
 	var raw types.StorageDataRaw
@@ -224,7 +374,7 @@ func (c *Client) GetReferendumInfoAt(refID uint32, blockHash string) (*Referendu
 	}
 
 	storageKey := types.NewStorageKey(key)
-	ok, err := c.api.RPC.State.GetStorage(storageKey, &raw, hash)
+	ok, err := c.rpc().RPC.State.GetStorage(storageKey, &raw, hash)
 	if err != nil {
 		return nil, fmt.Errorf("get storage at block: %w", err)
 	}
@@ -234,7 +384,7 @@ func (c *Client) GetReferendumInfoAt(refID uint32, blockHash string) (*Referendu
 	}
 
 	// Decode the referendum data
-	info, err := decodeReferendumInfo(raw, refID, c.accountIDToSS58)
+	info, err := decodeReferendumInfo(raw, refID, c.accountIDToSS58, c.originDecoder(), c.tallyDecoder())
 	if err != nil {
 		// Try legacy format
 		info, err = decodeLegacyReferendumInfo(raw, refID, c.accountIDToSS58)
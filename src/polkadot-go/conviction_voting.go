@@ -0,0 +1,387 @@
+package polkadot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/scale"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+)
+
+const (
+	keysPageSize     = 1000
+	storageBatchSize = 200
+)
+
+// Conviction vote types
+const (
+	VoteTypeStandard     = "Standard"
+	VoteTypeSplit        = "Split"
+	VoteTypeSplitAbstain = "SplitAbstain"
+)
+
+// ConvictionVote is a single account's vote on a referendum
+type ConvictionVote struct {
+	Account          string
+	Track            uint16
+	RefID            uint32
+	Type             string
+	Aye              bool
+	Conviction       uint8
+	Balance          string
+	AyeBalance       string
+	NayBalance       string
+	AbstainBalance   string
+	DelegatedVotes   string
+	DelegatedCapital string
+}
+
+// ConvictionDelegation is an account delegating its voting power on a track
+type ConvictionDelegation struct {
+	Account    string
+	Target     string
+	Track      uint16
+	Conviction uint8
+	Balance    string
+}
+
+// VotingSnapshot holds conviction voting state read at a single block
+type VotingSnapshot struct {
+	BlockHash   string
+	Votes       []ConvictionVote
+	Delegations []ConvictionDelegation
+	// ClassLocks maps account -> track -> locked balance
+	ClassLocks map[string]map[uint16]string
+}
+
+// GetKeysPaged returns up to count storage keys under prefix, starting after startKey
+func (c *Client) GetKeysPaged(prefix string, count uint32, startKey string, at *string) ([]string, error) {
+	var keys []string
+	var err error
+	if at != nil {
+		err = c.rpc().Client.Call(&keys, "state_getKeysPaged", prefix, count, startKey, *at)
+	} else {
+		err = c.rpc().Client.Call(&keys, "state_getKeysPaged", prefix, count, startKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// QueryStorageAt fetches many storage values at once, returning key -> hex value
+func (c *Client) QueryStorageAt(keys []string, at *string) (map[string]string, error) {
+	result := make(map[string]string, len(keys))
+
+	for start := 0; start < len(keys); start += storageBatchSize {
+		end := start + storageBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		storageKeys := make([]types.StorageKey, 0, end-start)
+		for _, key := range keys[start:end] {
+			keyBytes, err := DecodeHex(key)
+			if err != nil {
+				return nil, err
+			}
+			storageKeys = append(storageKeys, types.NewStorageKey(keyBytes))
+		}
+
+		var sets []types.StorageChangeSet
+		var err error
+		if at != nil {
+			var hash types.Hash
+			if err := codec.DecodeFromHex(*at, &hash); err != nil {
+				return nil, err
+			}
+			sets, err = c.rpc().RPC.State.QueryStorageAt(storageKeys, hash)
+		} else {
+			sets, err = c.rpc().RPC.State.QueryStorageAtLatest(storageKeys)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("query storage: %w", err)
+		}
+
+		for _, set := range sets {
+			for _, change := range set.Changes {
+				if !change.HasStorageData {
+					continue
+				}
+				result[codec.HexEncodeToString(change.StorageKey)] = codec.HexEncodeToString(change.StorageData)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// GetConvictionVoting reads ConvictionVoting.VotingFor for the given tracks,
+// keeping votes cast on refs, and ClassLocksFor for every account seen
+func (c *Client) GetConvictionVoting(tracks map[uint16]bool, refs map[uint32]bool) (*VotingSnapshot, error) {
+	blockHash, err := c.GetBlockHash(nil)
+	if err != nil {
+		return nil, fmt.Errorf("get block hash: %w", err)
+	}
+
+	prefix := StorageKey("ConvictionVoting", "VotingFor")
+	prefixLen := 32
+
+	var keys []string
+	startKey := prefix
+	for {
+		page, err := c.GetKeysPaged(prefix, keysPageSize, startKey, &blockHash)
+		if err != nil {
+			return nil, fmt.Errorf("list VotingFor keys: %w", err)
+		}
+		for _, key := range page {
+			raw, err := DecodeHex(key)
+			// prefix + twox64(account) + account + twox64(class) + class
+			if err != nil || len(raw) != prefixLen+8+32+8+2 {
+				continue
+			}
+			track := binary.LittleEndian.Uint16(raw[prefixLen+8+32+8:])
+			if tracks[track] {
+				keys = append(keys, key)
+			}
+		}
+		if len(page) < keysPageSize {
+			break
+		}
+		startKey = page[len(page)-1]
+	}
+
+	values, err := c.QueryStorageAt(keys, &blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &VotingSnapshot{
+		BlockHash:  blockHash,
+		ClassLocks: make(map[string]map[uint16]string),
+	}
+	accounts := make(map[string]types.AccountID)
+
+	for _, key := range keys {
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+		raw, _ := DecodeHex(key)
+		var accountID types.AccountID
+		copy(accountID[:], raw[prefixLen+8:prefixLen+8+32])
+		track := binary.LittleEndian.Uint16(raw[prefixLen+8+32+8:])
+		account := c.accountIDToSS58(accountID)
+
+		data, err := DecodeHex(value)
+		if err != nil {
+			continue
+		}
+		if err := c.decodeVoting(data, account, track, refs, snapshot); err != nil {
+			return nil, fmt.Errorf("decode voting for %s track %d: %w", account, track, err)
+		}
+		accounts[account] = accountID
+	}
+
+	if err := c.loadClassLocks(accounts, blockHash, snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// decodeVoting decodes a Voting enum (Casting or Delegating)
+func (c *Client) decodeVoting(data []byte, account string, track uint16, refs map[uint32]bool, snapshot *VotingSnapshot) error {
+	decoder := scale.NewDecoder(bytes.NewReader(data))
+
+	variant, err := decoder.ReadOneByte()
+	if err != nil {
+		return err
+	}
+
+	switch variant {
+	case 0: // Casting
+		var count types.UCompact
+		if err := decoder.Decode(&count); err != nil {
+			return fmt.Errorf("decode vote count: %w", err)
+		}
+
+		var votes []ConvictionVote
+		for i := int64(0); i < count.Int64(); i++ {
+			var poll types.U32
+			if err := decoder.Decode(&poll); err != nil {
+				return fmt.Errorf("decode poll index: %w", err)
+			}
+			vote, err := decodeAccountVote(decoder)
+			if err != nil {
+				return err
+			}
+			vote.Account = account
+			vote.Track = track
+			vote.RefID = uint32(poll)
+			votes = append(votes, vote)
+		}
+
+		// Delegations { votes, capital } received from delegators
+		var delegatedVotes, delegatedCapital types.U128
+		if err := decoder.Decode(&delegatedVotes); err != nil {
+			return fmt.Errorf("decode delegations: %w", err)
+		}
+		if err := decoder.Decode(&delegatedCapital); err != nil {
+			return fmt.Errorf("decode delegations: %w", err)
+		}
+
+		for _, vote := range votes {
+			if !refs[vote.RefID] {
+				continue
+			}
+			vote.DelegatedVotes = delegatedVotes.String()
+			vote.DelegatedCapital = delegatedCapital.String()
+			snapshot.Votes = append(snapshot.Votes, vote)
+		}
+
+	case 1: // Delegating
+		var balance types.U128
+		if err := decoder.Decode(&balance); err != nil {
+			return fmt.Errorf("decode delegation balance: %w", err)
+		}
+		var target types.AccountID
+		if err := decoder.Decode(&target); err != nil {
+			return fmt.Errorf("decode delegation target: %w", err)
+		}
+		conviction, err := decoder.ReadOneByte()
+		if err != nil {
+			return fmt.Errorf("decode delegation conviction: %w", err)
+		}
+
+		snapshot.Delegations = append(snapshot.Delegations, ConvictionDelegation{
+			Account:    account,
+			Target:     c.accountIDToSS58(target),
+			Track:      track,
+			Conviction: conviction,
+			Balance:    balance.String(),
+		})
+
+	default:
+		return fmt.Errorf("unknown voting variant %d", variant)
+	}
+
+	return nil
+}
+
+// decodeAccountVote decodes an AccountVote enum
+func decodeAccountVote(decoder *scale.Decoder) (ConvictionVote, error) {
+	var vote ConvictionVote
+
+	variant, err := decoder.ReadOneByte()
+	if err != nil {
+		return vote, err
+	}
+
+	switch variant {
+	case 0: // Standard { vote, balance }
+		packed, err := decoder.ReadOneByte()
+		if err != nil {
+			return vote, err
+		}
+		var balance types.U128
+		if err := decoder.Decode(&balance); err != nil {
+			return vote, fmt.Errorf("decode standard balance: %w", err)
+		}
+		vote.Type = VoteTypeStandard
+		vote.Aye = packed&0x80 != 0
+		vote.Conviction = packed & 0x7f
+		vote.Balance = balance.String()
+
+	case 1: // Split { aye, nay }
+		var aye, nay types.U128
+		if err := decoder.Decode(&aye); err != nil {
+			return vote, fmt.Errorf("decode split aye: %w", err)
+		}
+		if err := decoder.Decode(&nay); err != nil {
+			return vote, fmt.Errorf("decode split nay: %w", err)
+		}
+		vote.Type = VoteTypeSplit
+		vote.AyeBalance = aye.String()
+		vote.NayBalance = nay.String()
+
+	case 2: // SplitAbstain { aye, nay, abstain }
+		var aye, nay, abstain types.U128
+		if err := decoder.Decode(&aye); err != nil {
+			return vote, fmt.Errorf("decode split-abstain aye: %w", err)
+		}
+		if err := decoder.Decode(&nay); err != nil {
+			return vote, fmt.Errorf("decode split-abstain nay: %w", err)
+		}
+		if err := decoder.Decode(&abstain); err != nil {
+			return vote, fmt.Errorf("decode split-abstain abstain: %w", err)
+		}
+		vote.Type = VoteTypeSplitAbstain
+		vote.AyeBalance = aye.String()
+		vote.NayBalance = nay.String()
+		vote.AbstainBalance = abstain.String()
+
+	default:
+		return vote, fmt.Errorf("unknown account vote variant %d", variant)
+	}
+
+	return vote, nil
+}
+
+// loadClassLocks reads ConvictionVoting.ClassLocksFor for each account
+func (c *Client) loadClassLocks(accounts map[string]types.AccountID, blockHash string, snapshot *VotingSnapshot) error {
+	if len(accounts) == 0 {
+		return nil
+	}
+
+	prefix := append(Twox128([]byte("ConvictionVoting")), Twox128([]byte("ClassLocksFor"))...)
+	keyToAccount := make(map[string]string, len(accounts))
+	keys := make([]string, 0, len(accounts))
+	for address, accountID := range accounts {
+		key := append(append([]byte{}, prefix...), Twox64(accountID[:])...)
+		key = append(key, accountID[:]...)
+		hexKey := codec.HexEncodeToString(key)
+		keyToAccount[hexKey] = address
+		keys = append(keys, hexKey)
+	}
+
+	values, err := c.QueryStorageAt(keys, &blockHash)
+	if err != nil {
+		return fmt.Errorf("query class locks: %w", err)
+	}
+
+	for key, value := range values {
+		address, ok := keyToAccount[key]
+		if !ok {
+			continue
+		}
+		data, err := DecodeHex(value)
+		if err != nil {
+			continue
+		}
+
+		decoder := scale.NewDecoder(bytes.NewReader(data))
+		var count types.UCompact
+		if err := decoder.Decode(&count); err != nil {
+			continue
+		}
+
+		locks := make(map[uint16]string)
+		for i := int64(0); i < count.Int64(); i++ {
+			var class types.U16
+			var amount types.U128
+			if err := decoder.Decode(&class); err != nil {
+				break
+			}
+			if err := decoder.Decode(&amount); err != nil {
+				break
+			}
+			locks[uint16(class)] = amount.String()
+		}
+		snapshot.ClassLocks[address] = locks
+	}
+
+	return nil
+}
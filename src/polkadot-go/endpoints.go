@@ -0,0 +1,238 @@
+package polkadot
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	gsclient "github.com/centrifuge/go-substrate-rpc-client/v4/client"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+)
+
+const (
+	connectTimeout = 15 * time.Second
+	checkTimeout   = 10 * time.Second
+	// maxBlockLag is how far an endpoint's best block may trail the best
+	// endpoint before it is considered unhealthy
+	maxBlockLag = 5
+	minBackoff  = 5 * time.Second
+	maxBackoff  = 5 * time.Minute
+)
+
+// EndpointStatus is the last health check result for an RPC endpoint
+type EndpointStatus struct {
+	URL       string
+	Active    bool
+	Healthy   bool
+	Latency   time.Duration
+	BestBlock uint64
+	Lag       uint64
+	Failures  int // consecutive failed checks
+	LastError string
+	CheckedAt time.Time
+}
+
+type endpoint struct {
+	status EndpointStatus
+}
+
+func (e *endpoint) recordFailure(err error) {
+	e.status.Healthy = false
+	e.status.Failures++
+	e.status.LastError = err.Error()
+	e.status.CheckedAt = time.Now()
+}
+
+// withTimeout runs fn and gives up after timeout. The call is left to finish
+// in the background, which is acceptable for the rare hung connection.
+func withTimeout[T any](timeout time.Duration, fn func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := fn()
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-time.After(timeout):
+		var zero T
+		return zero, fmt.Errorf("timed out after %v", timeout)
+	}
+}
+
+// use makes the endpoint at idx the connected one
+func (c *Client) use(idx int, api *gsrpc.SubstrateAPI, meta *types.Metadata) {
+	rankedTally := c.detectRankedTally(meta)
+
+	c.connMu.Lock()
+	previous := c.api
+	c.api = api
+	c.metadata = meta
+	c.active = idx
+	c.rankedTally = rankedTally
+	c.connMu.Unlock()
+
+	if previous != nil && previous != api && previous.Client != nil {
+		previous.Client.Close()
+	}
+
+	// Metadata can differ across a runtime upgrade; drop derived values
+	c.constantsMu.Lock()
+	c.constantsCache = make(map[string]interface{})
+	c.constantsMu.Unlock()
+
+	for i, ep := range c.endpoints {
+		ep.status.Active = i == idx
+	}
+}
+
+// ActiveEndpoint returns the URL of the connected endpoint
+func (c *Client) ActiveEndpoint() string {
+	if len(c.endpoints) == 0 {
+		return ""
+	}
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+	return c.endpoints[c.active].status.URL
+}
+
+// Endpoints returns the last known status of every endpoint
+func (c *Client) Endpoints() []EndpointStatus {
+	statuses := make([]EndpointStatus, 0, len(c.endpoints))
+	for _, ep := range c.endpoints {
+		statuses = append(statuses, ep.status)
+	}
+	return statuses
+}
+
+// CheckHealth probes every endpoint for latency and best block, and fails
+// over when the connected endpoint is down or lagging. It returns true when
+// the client switched connections, which invalidates open subscriptions.
+// While no endpoint is reachable, checks back off exponentially.
+func (c *Client) CheckHealth() (bool, error) {
+	if time.Now().Before(c.retryAt) {
+		return false, nil
+	}
+
+	var best uint64
+	for idx, ep := range c.endpoints {
+		c.probe(idx, ep)
+		if ep.status.LastError == "" && ep.status.BestBlock > best {
+			best = ep.status.BestBlock
+		}
+	}
+	for _, ep := range c.endpoints {
+		if ep.status.LastError != "" {
+			continue
+		}
+		ep.status.Lag = best - ep.status.BestBlock
+		ep.status.Healthy = ep.status.Lag <= maxBlockLag
+	}
+
+	current := c.endpoints[c.active]
+	if current.status.Healthy {
+		c.backoff = 0
+		c.retryAt = time.Time{}
+		return false, nil
+	}
+
+	// Prefer healthy endpoints by lag, then latency. Only when the current
+	// connection is dead is any other endpoint (or a reconnect) worth trying.
+	var candidates, fallback []int
+	for idx, ep := range c.endpoints {
+		switch {
+		case idx == c.active:
+			if current.status.LastError != "" {
+				fallback = append(fallback, idx)
+			}
+		case ep.status.Healthy:
+			candidates = append(candidates, idx)
+		case current.status.LastError != "":
+			fallback = append(fallback, idx)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := c.endpoints[candidates[i]].status, c.endpoints[candidates[j]].status
+		if a.Lag != b.Lag {
+			return a.Lag < b.Lag
+		}
+		return a.Latency < b.Latency
+	})
+
+	for _, idx := range append(candidates, fallback...) {
+		ep := c.endpoints[idx]
+		api, meta, err := connect(ep.status.URL)
+		if err != nil {
+			ep.recordFailure(err)
+			continue
+		}
+		c.use(idx, api, meta)
+		c.backoff = 0
+		c.retryAt = time.Time{}
+		return true, nil
+	}
+
+	if current.status.LastError == "" {
+		// Lagging but alive, and nothing better available
+		return false, nil
+	}
+
+	if c.backoff == 0 {
+		c.backoff = minBackoff
+	} else if c.backoff < maxBackoff {
+		c.backoff *= 2
+		if c.backoff > maxBackoff {
+			c.backoff = maxBackoff
+		}
+	}
+	c.retryAt = time.Now().Add(c.backoff)
+	return false, fmt.Errorf("no reachable RPC endpoint, retrying in %v", c.backoff)
+}
+
+// probe measures an endpoint's best block and latency. The connected
+// endpoint is probed over the live connection so a dropped socket shows up.
+func (c *Client) probe(idx int, ep *endpoint) {
+	var header *types.Header
+	var latency time.Duration
+	var err error
+
+	if api := c.rpc(); idx == c.active && api != nil {
+		start := time.Now()
+		header, err = withTimeout(checkTimeout, api.RPC.Chain.GetHeaderLatest)
+		latency = time.Since(start)
+	} else {
+		var cl gsclient.Client
+		cl, err = withTimeout(connectTimeout, func() (gsclient.Client, error) {
+			return gsclient.Connect(ep.status.URL)
+		})
+		if err == nil {
+			start := time.Now()
+			header, err = withTimeout(checkTimeout, func() (*types.Header, error) {
+				var h types.Header
+				if err := cl.Call(&h, "chain_getHeader"); err != nil {
+					return nil, err
+				}
+				return &h, nil
+			})
+			latency = time.Since(start)
+			cl.Close()
+		}
+	}
+
+	if err != nil {
+		ep.recordFailure(err)
+		return
+	}
+
+	ep.status.BestBlock = uint64(header.Number)
+	ep.status.Latency = latency
+	ep.status.Failures = 0
+	ep.status.LastError = ""
+	ep.status.CheckedAt = time.Now()
+}
@@ -0,0 +1,194 @@
+package polkadot
+
+import (
+	"fmt"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+)
+
+// ReferendaEvent is a Referenda pallet event that touches a single referendum
+type ReferendaEvent struct {
+	Name  string
+	RefID uint32
+}
+
+// PalletEvent is a runtime event decoded against the metadata. Args holds
+// the named fields, nil for events without fields.
+type PalletEvent struct {
+	Pallet string
+	Name   string
+	Args   map[string]interface{}
+}
+
+// Uint returns a named integer field of the event
+func (e PalletEvent) Uint(name string) (uint64, bool) {
+	value, ok := e.Args[name].(uint64)
+	return value, ok
+}
+
+// FinalizedHead is a finalized block delivered by a head subscription
+type FinalizedHead struct {
+	Number uint64
+}
+
+// HeadSubscription streams finalized heads until Unsubscribe is called
+type HeadSubscription struct {
+	heads       chan FinalizedHead
+	errs        <-chan error
+	unsubscribe func()
+	quit        chan struct{}
+}
+
+// Heads returns the channel of finalized heads
+func (s *HeadSubscription) Heads() <-chan FinalizedHead {
+	return s.heads
+}
+
+// Err returns the channel that reports subscription failures
+func (s *HeadSubscription) Err() <-chan error {
+	return s.errs
+}
+
+// Unsubscribe stops the subscription
+func (s *HeadSubscription) Unsubscribe() {
+	select {
+	case <-s.quit:
+	default:
+		close(s.quit)
+		s.unsubscribe()
+	}
+}
+
+// SubscribeFinalizedHeads subscribes to finalized heads. Requires a websocket RPC.
+func (c *Client) SubscribeFinalizedHeads() (*HeadSubscription, error) {
+	sub, err := c.rpc().RPC.Chain.SubscribeFinalizedHeads()
+	if err != nil {
+		return nil, fmt.Errorf("subscribe finalized heads: %w", err)
+	}
+
+	s := &HeadSubscription{
+		heads:       make(chan FinalizedHead),
+		errs:        sub.Err(),
+		unsubscribe: sub.Unsubscribe,
+		quit:        make(chan struct{}),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-s.quit:
+				return
+			case header, ok := <-sub.Chan():
+				if !ok {
+					return
+				}
+				select {
+				case s.heads <- FinalizedHead{Number: uint64(header.Number)}:
+				case <-s.quit:
+					return
+				}
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+// GetEvents decodes System.Events at a block and returns the events of the
+// given pallets, in block order
+func (c *Client) GetEvents(blockHash string, pallets ...string) ([]PalletEvent, error) {
+	metadata := c.runtimeMetadata()
+	if metadata == nil || metadata.Version < 14 {
+		return nil, fmt.Errorf("events require metadata v14")
+	}
+	meta := &metadata.AsMetadataV14
+
+	typeID, err := eventsTypeID(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := c.GetStorage(StorageKey("System", "Events"), &blockHash)
+	if err != nil {
+		return nil, fmt.Errorf("get events: %w", err)
+	}
+	if value == "" || value == "0x" {
+		return nil, nil
+	}
+	data, err := DecodeHex(value)
+	if err != nil {
+		return nil, err
+	}
+
+	d := newCallDecoder(meta, c.accountIDToSS58, data)
+	decoded, err := d.decodeValue(typeID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("decode events: %w", err)
+	}
+
+	records, _ := decoded.([]interface{})
+	var events []PalletEvent
+	for _, record := range records {
+		fields, ok := record.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		outer, ok := fields["event"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, palletName := range pallets {
+			pallet, ok := outer[palletName].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for name, body := range pallet {
+				args, _ := body.(map[string]interface{})
+				events = append(events, PalletEvent{Pallet: palletName, Name: name, Args: args})
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// GetReferendaEvents decodes System.Events at a block and returns the events
+// of the client's referenda pallet that carry a referendum index
+func (c *Client) GetReferendaEvents(blockHash string) ([]ReferendaEvent, error) {
+	events, err := c.GetEvents(blockHash, c.ReferendaPallet())
+	if err != nil {
+		return nil, err
+	}
+	return c.ReferendaEvents(events), nil
+}
+
+// ReferendaEvents picks the events of the client's referenda pallet that
+// carry a referendum index
+func (c *Client) ReferendaEvents(events []PalletEvent) []ReferendaEvent {
+	palletName := c.ReferendaPallet()
+	var result []ReferendaEvent
+	for _, event := range events {
+		if event.Pallet != palletName {
+			continue
+		}
+		if index, ok := event.Uint("index"); ok {
+			result = append(result, ReferendaEvent{Name: event.Name, RefID: uint32(index)})
+		}
+	}
+	return result
+}
+
+// eventsTypeID finds the registry type of the System.Events storage value
+func eventsTypeID(meta *types.MetadataV14) (int64, error) {
+	for _, pallet := range meta.Pallets {
+		if !pallet.HasStorage || string(pallet.Storage.Prefix) != "System" {
+			continue
+		}
+		for _, item := range pallet.Storage.Items {
+			if string(item.Name) == "Events" && item.Type.IsPlainType {
+				return item.Type.AsPlainType.Int64(), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("System.Events not found in metadata")
+}
@@ -0,0 +1,205 @@
+package gov
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DAO vote choices as stored in DaoVote.Choice
+const (
+	DaoChoiceNay     int16 = -1
+	DaoChoiceAbstain int16 = 0
+	DaoChoiceAye     int16 = 1
+)
+
+// Outcomes recorded on a DaoDecision
+const (
+	DaoOutcomeAye      = "aye"
+	DaoOutcomeNay      = "nay"
+	DaoOutcomeAbstain  = "abstain"
+	DaoOutcomeNoQuorum = "no_quorum"
+)
+
+// ParseDaoChoice maps "aye", "nay" or "abstain" to its DaoChoice value
+func ParseDaoChoice(value string) (int16, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "aye":
+		return DaoChoiceAye, true
+	case "nay":
+		return DaoChoiceNay, true
+	case "abstain":
+		return DaoChoiceAbstain, true
+	}
+	return 0, false
+}
+
+// DaoChoiceName returns the name of a DaoChoice value
+func DaoChoiceName(choice int16) string {
+	switch choice {
+	case DaoChoiceAye:
+		return "aye"
+	case DaoChoiceNay:
+		return "nay"
+	default:
+		return "abstain"
+	}
+}
+
+// DaoDecision is a guild DAO's internal vote on a referendum. The row is
+// created with the first vote, or when voting closes on a referendum in a
+// guild thread nobody voted on, and carries the live tally message in the
+// guild's thread; Outcome is set once voting closes.
+type DaoDecision struct {
+	ID             uint64 `gorm:"primaryKey;autoIncrement"`
+	RefDBID        uint64 `gorm:"uniqueIndex:idx_dao_decision_ref"`
+	GuildID        string `gorm:"size:64;uniqueIndex:idx_dao_decision_ref"`
+	NetworkID      uint8
+	RefID          uint64
+	ThreadID       *string `gorm:"size:64"`
+	TallyMessageID *string `gorm:"size:64"`
+	Ayes           uint32
+	Nays           uint32
+	Abstains       uint32
+	Members        uint32 // registered members when the tally was taken
+	QuorumPercent  uint8
+	QuorumMet      bool
+	Outcome        *string `gorm:"size:16"` // nil while voting is open
+	DecidedAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Decided reports whether voting has closed
+func (d *DaoDecision) Decided() bool {
+	return d != nil && d.Outcome != nil
+}
+
+// Apply copies a tally onto the decision
+func (d *DaoDecision) Apply(t DaoTally) {
+	d.Ayes = uint32(t.Ayes)
+	d.Nays = uint32(t.Nays)
+	d.Abstains = uint32(t.Abstains)
+	d.Members = uint32(t.Members)
+	d.QuorumPercent = uint8(t.QuorumPercent)
+	d.QuorumMet = t.QuorumMet()
+}
+
+// DaoTally counts the current members' votes on a referendum
+type DaoTally struct {
+	Ayes          int
+	Nays          int
+	Abstains      int
+	Members       int
+	QuorumPercent int
+}
+
+// Votes returns the number of members who voted, abstentions included
+func (t DaoTally) Votes() int {
+	return t.Ayes + t.Nays + t.Abstains
+}
+
+// QuorumMet reports whether enough members voted for the tally to stand
+func (t DaoTally) QuorumMet() bool {
+	if t.Members == 0 {
+		return false
+	}
+	return t.Votes()*100 >= t.Members*t.QuorumPercent
+}
+
+// Outcome returns the decision the tally yields. Ties and abstain-only
+// tallies resolve to abstain.
+func (t DaoTally) Outcome() string {
+	switch {
+	case !t.QuorumMet():
+		return DaoOutcomeNoQuorum
+	case t.Ayes > t.Nays:
+		return DaoOutcomeAye
+	case t.Nays > t.Ayes:
+		return DaoOutcomeNay
+	default:
+		return DaoOutcomeAbstain
+	}
+}
+
+// Lines renders the tally for Discord, reports and prompts
+func (t DaoTally) Lines() []string {
+	quorum := "not met"
+	if t.QuorumMet() {
+		quorum = "met"
+	}
+	return []string{
+		fmt.Sprintf("Aye: %d • Nay: %d • Abstain: %d", t.Ayes, t.Nays, t.Abstains),
+		fmt.Sprintf("Turnout: %d of %d members (quorum %d%% %s)", t.Votes(), t.Members, t.QuorumPercent, quorum),
+	}
+}
+
+// TallyDaoVotes counts the votes a guild's DAO cast on a referendum by members
+// still linked to one of its registered addresses. Members are Discord
+// accounts, however many addresses each has linked.
+func TallyDaoVotes(db *gorm.DB, guildID string, refDBID uint64, quorumPercent int) (DaoTally, error) {
+	tally := DaoTally{QuorumPercent: quorumPercent}
+
+	var members int64
+	if err := db.Model(&DaoMember{}).Where("guild_id = ? AND discord <> ''", guildID).Distinct("discord").Count(&members).Error; err != nil {
+		return tally, err
+	}
+	tally.Members = int(members)
+
+	var rows []struct {
+		Choice int16
+		Count  int
+	}
+	if err := db.Model(&DaoVote{}).
+		Select("choice, COUNT(*) AS count").
+		Where("ref_id = ? AND guild_id = ? AND discord IN (?)", refDBID, guildID,
+			db.Model(&DaoMember{}).Select("discord").Where("guild_id = ?", guildID)).
+		Group("choice").
+		Scan(&rows).Error; err != nil {
+		return tally, err
+	}
+	for _, row := range rows {
+		switch row.Choice {
+		case DaoChoiceAye:
+			tally.Ayes = row.Count
+		case DaoChoiceNay:
+			tally.Nays = row.Count
+		default:
+			tally.Abstains += row.Count
+		}
+	}
+	return tally, nil
+}
+
+// DaoVoteCutoff returns when DAO voting on a referendum closes: the cutoff
+// before its estimated decision end. Returns nil while the decision period
+// has not started.
+func DaoVoteCutoff(ref *Ref, cutoff time.Duration) *time.Time {
+	if ref == nil || ref.DecisionEndAt == nil {
+		return nil
+	}
+	closesAt := ref.DecisionEndAt.Add(-cutoff)
+	return &closesAt
+}
+
+// WalletChallenge is a pending /link-wallet request: the message a Discord
+// user must sign with an address to link it in a guild
+type WalletChallenge struct {
+	ID        uint64 `gorm:"primaryKey;autoIncrement"`
+	GuildID   string `gorm:"size:64;index:idx_wallet_challenge,unique"`
+	DiscordID string `gorm:"size:64;index:idx_wallet_challenge,unique"`
+	Address   string `gorm:"size:128;index:idx_wallet_challenge,unique"`
+	Message   string `gorm:"type:text"`
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// WalletLinkMessage builds the message a Discord user signs to prove they
+// control an address. It names the guild so a signature links the address in
+// that guild only.
+func WalletLinkMessage(guildID, discordID, address, nonce string, expiresAt time.Time) string {
+	return fmt.Sprintf("GovComms wallet link: Discord user %s controls %s in guild %s (nonce %s, expires %s)",
+		discordID, address, guildID, nonce, expiresAt.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,25 @@
+package gov
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWalletLinkMessageNamesGuild(t *testing.T) {
+	expires := time.Date(2025, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	address := "15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5"
+
+	message := WalletLinkMessage("100000000000000001", "123456789012345678", address, "00ff", expires)
+	want := "GovComms wallet link: Discord user 123456789012345678 controls " + address +
+		" in guild 100000000000000001 (nonce 00ff, expires 2025-03-01T11:00:00Z)"
+	if message != want {
+		t.Errorf("WalletLinkMessage = %q, want %q", message, want)
+	}
+
+	// A signature over one guild's message does not cover another's
+	other := WalletLinkMessage("100000000000000002", "123456789012345678", address, "00ff", expires)
+	if other == message || !strings.Contains(other, "guild 100000000000000002") {
+		t.Errorf("message for another guild = %q", other)
+	}
+}
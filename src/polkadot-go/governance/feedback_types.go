@@ -1,20 +1,44 @@
 package gov
 
-import "time"
+import (
+	"time"
+
+	polkadot "github.com/stake-plus/govcomms/src/polkadot-go"
+)
+
+// Roles of a network RPC endpoint
+const (
+	RPCRoleGovernance = "governance" // chain hosting the referenda pallet
+	RPCRoleIdentity   = "identity"   // People chain hosting the Identity pallet
+)
 
 // NetworkRPC represents RPC endpoints for networks
 type NetworkRPC struct {
 	ID        uint32 `gorm:"primaryKey"`
 	NetworkID uint8
 	URL       string `gorm:"size:256;not null"`
+	Role      string `gorm:"size:16;not null;default:governance"`
 	Active    bool   `gorm:"default:true"`
+	// Health as last observed by the indexer
+	InUse         bool
+	Healthy       *bool
+	LatencyMs     *uint32
+	BestBlock     *uint64
+	BlockLag      *uint64
+	Failures      uint32
+	LastError     *string `gorm:"size:512"`
+	LastCheckedAt *time.Time
 }
 
-// DaoMember represents a DAO member
+// DaoMember represents a member of a guild's DAO. A Discord user may link
+// several addresses, one row each.
 type DaoMember struct {
-	Address string `gorm:"primaryKey;size:128"`
-	Discord string `gorm:"size:64"`
-	IsAdmin bool   `gorm:"default:false"`
+	GuildID  string     `gorm:"primaryKey;size:64"`
+	Address  string     `gorm:"primaryKey;size:128"`
+	Discord  string     `gorm:"size:64;index"` // Discord user ID the member votes from
+	IsAdmin  bool       `gorm:"default:false"`
+	Scheme   *string    `gorm:"size:16"` // signature scheme that proved ownership
+	LinkedAt *time.Time // when ownership was proven with /link-wallet
 }
 
 // Ref represents a referendum/proposal
@@ -32,6 +56,7 @@ type Ref struct {
 	SubmittedAt             *time.Time
 	DecisionStart           uint64
 	DecisionEnd             uint64
+	DecisionEndAt           *time.Time // estimated wall-clock time of DecisionEnd
 	ConfirmStart            uint64
 	ConfirmEnd              uint64
 	Approved                bool
@@ -43,6 +68,8 @@ type Ref struct {
 	Electorate              *string
 	PreimageHash            *string
 	PreimageLen             *uint32
+	DecodedCall             *string `gorm:"type:longtext"`
+	PassProjection          *string `gorm:"type:text"`
 	DecisionDepositWho      *string
 	DecisionDepositAmount   *string
 	SubmissionDepositWho     *string
@@ -53,17 +80,56 @@ type Ref struct {
 	UpdatedAt               time.Time
 }
 
+// Review states of feedback submitted while its guild reviews feedback.
+// Feedback posted without review has no status.
+const (
+	FeedbackPending   = "pending"
+	FeedbackApproving = "approving" // claimed by a reviewer while it is posted
+	FeedbackApproved  = "approved"
+	FeedbackRejected  = "rejected"
+)
+
 // RefMessage represents a message in a referendum thread
 type RefMessage struct {
 	ID                   uint64  `gorm:"primaryKey;autoIncrement"`
 	RefID                uint64  `gorm:"index"`
+	GuildID              string  `gorm:"size:64"` // guild whose thread the message belongs to
 	Author               string
-	Body                 string  `gorm:"type:text"`
+	AuthorID             *string `gorm:"size:64"` // Discord ID of the author of feedback
+	Body                 string  `gorm:"type:text"` // final text, as posted
+	Draft                *string `gorm:"type:text"` // text as submitted for review
+	Status               string  `gorm:"size:16"`
+	ReviewedBy           *string `gorm:"size:128"`
+	ReviewedAt           *time.Time
+	ReviewMessageID      *string `gorm:"size:64"` // message in the review channel
 	CreatedAt            time.Time
 	Internal             bool    `gorm:"default:false"`
 	PolkassemblyUserID    *uint32
 	PolkassemblyUsername string
 	PolkassemblyCommentID *string `gorm:"type:varchar(64)"`
+	PolkassemblyParentID *string `gorm:"size:64"` // comment replied to; nil for top-level feedback
+	RevisesID            *uint64 `gorm:"index"`   // posted feedback an edit under review replaces
+	DiscordMessageID     *string `gorm:"size:64;index"` // thread message showing the feedback or reply
+	EditedAt             *time.Time
+	RemovedAt            *time.Time // withdrawn with /feedback-withdraw or deleted on Polkassembly
+}
+
+// Where a revision of a RefMessage was made
+const (
+	MessageSourceDiscord      = "discord"
+	MessageSourcePolkassembly = "polkassembly"
+)
+
+// RefMessageVersion is one revision of a RefMessage body. The first version
+// holds the text as originally posted.
+type RefMessageVersion struct {
+	ID        uint64 `gorm:"primaryKey;autoIncrement"`
+	MessageID uint64 `gorm:"uniqueIndex:idx_message_version"`
+	Version   uint32 `gorm:"uniqueIndex:idx_message_version"`
+	Body      string `gorm:"type:text"`
+	EditedBy  string `gorm:"size:128"`
+	Source    string `gorm:"size:16"` // discord or polkassembly
+	CreatedAt time.Time
 }
 
 // RefProponent represents a proposal participant
@@ -74,12 +140,70 @@ type RefProponent struct {
 	Active  int8   `gorm:"default:1"`
 }
 
-// DaoVote represents a DAO vote on a referendum
+// DaoVote represents a DAO vote on a referendum, one per member and
+// referendum in each guild; Choice is one of the DaoChoice values. Members are keyed by
+// their Discord account, so one with several linked addresses votes once.
 type DaoVote struct {
-	ID          uint64 `gorm:"primaryKey"`
-	RefID       uint64 `gorm:"index;not null"`
-	DaoMemberID string `gorm:"size:128;not null"`
-	Choice      int16  `gorm:"not null"`
+	ID      uint64 `gorm:"primaryKey"`
+	RefID   uint64 `gorm:"index:idx_dao_vote_member,unique;not null"`
+	GuildID string `gorm:"size:64;index:idx_dao_vote_member,unique;not null"`
+	Discord string `gorm:"size:64;index:idx_dao_vote_member,unique;not null"` // Discord user ID of the member
+	Choice  int16  `gorm:"not null"`
 	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
+// IndexerCheckpoint is the last finalized block the indexer processed per network
+type IndexerCheckpoint struct {
+	NetworkID   uint8 `gorm:"primaryKey"`
+	BlockNumber uint64
+	BlockHash   string `gorm:"size:80"`
+	BlockTimeMs uint32 // the chain's expected block time
+	UpdatedAt   time.Time
+}
+
+// EstimateBlockTime converts a block number to an estimated wall-clock time,
+// counting blocks from the checkpoint at the chain's expected block time, or
+// at polkadot.DefaultBlockTime before the chain's has been recorded
+func (c *IndexerCheckpoint) EstimateBlockTime(block uint64) time.Time {
+	blockTime := time.Duration(c.BlockTimeMs) * time.Millisecond
+	if blockTime <= 0 {
+		blockTime = polkadot.DefaultBlockTime
+	}
+	if block >= c.BlockNumber {
+		return c.UpdatedAt.Add(time.Duration(block-c.BlockNumber) * blockTime)
+	}
+	return c.UpdatedAt.Add(-time.Duration(c.BlockNumber-block) * blockTime)
+}
+
+// RefEvent is an append-only lifecycle transition of a referendum
+type RefEvent struct {
+	ID          uint64 `gorm:"primaryKey;autoIncrement"`
+	RefDBID     uint64 `gorm:"index:idx_ref_event_unique,unique"`
+	NetworkID   uint8
+	RefID       uint64
+	Event       string  `gorm:"size:32;index:idx_ref_event_unique,unique"`
+	OldStatus   string  `gorm:"size:32"`
+	NewStatus   string  `gorm:"size:32"`
+	BlockNumber uint64  `gorm:"index:idx_ref_event_unique,unique"`
+	BlockHash   *string `gorm:"size:80"`
+	Ayes        *string
+	Nays        *string
+	Support     *string
+	OccurredAt  *time.Time
+	NotifiedAt  *time.Time // when the referendum thread was told, or the event skipped
+	CreatedAt   time.Time
+}
+
+// RefReminder records a decision-deadline reminder posted in a referendum
+// thread. DecisionEnd is part of the key so a moved deadline re-arms them.
+type RefReminder struct {
+	ID          uint64 `gorm:"primaryKey;autoIncrement"`
+	RefDBID     uint64 `gorm:"index:idx_ref_reminder,unique"`
+	NetworkID   uint8
+	RefID       uint64
+	OffsetHours uint16 `gorm:"index:idx_ref_reminder,unique"`
+	DecisionEnd uint64 `gorm:"index:idx_ref_reminder,unique"`
+	ThreadID    string `gorm:"size:64"`
+	SentAt      time.Time
+}
@@ -0,0 +1,169 @@
+package gov
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Guild is a Discord server served by this deployment, with its own roles
+// and the identity its feedback is posted to Polkassembly under
+type Guild struct {
+	GuildID                 string  `gorm:"primaryKey;size:64"`
+	Name                    string  `gorm:"size:128"`
+	QARoleID                string  `gorm:"column:qa_role_id;size:64"`
+	FeedbackRoleID          string  `gorm:"size:64"`
+	FeedbackReviewChannelID string  `gorm:"size:64"`   // when set, feedback waits there for approval
+	PolkassemblyIntro       *string `gorm:"type:text"` // falls back to the polkassembly_intro setting
+	PolkassemblyOutro       *string `gorm:"type:text"` // falls back to the polkassembly_outro setting
+	Active                  bool    `gorm:"default:true"`
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+}
+
+// GuildNetwork maps a network to a guild's referendum channel and the
+// Polkassembly account the guild posts feedback with
+type GuildNetwork struct {
+	ID               uint64 `gorm:"primaryKey;autoIncrement"`
+	GuildID          string `gorm:"size:64;uniqueIndex:idx_guild_network"`
+	NetworkID        uint8  `gorm:"uniqueIndex:idx_guild_network"`
+	DiscordChannelID string `gorm:"size:64;index"`
+	PolkassemblySeed string `gorm:"size:512"`
+}
+
+// GuildManager resolves the guilds a deployment serves and their channels.
+// Without active rows in the guilds table it serves the single legacy guild
+// configured through settings, mapped by the networks table.
+type GuildManager struct {
+	db        *gorm.DB
+	legacy    Guild
+	guilds    map[string]*Guild
+	order     []string
+	links     map[string][]GuildNetwork // by guild ID
+	byChannel map[string]GuildNetwork
+	mu        sync.RWMutex
+}
+
+// NewGuildManager creates a guild manager and loads the guild configuration
+func NewGuildManager(db *gorm.DB, legacy Guild) (*GuildManager, error) {
+	m := &GuildManager{db: db, legacy: legacy}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reload reads the guilds and their network channels from the database
+func (m *GuildManager) Reload() error {
+	var guilds []Guild
+	if err := m.db.Where("active = ?", true).Find(&guilds).Error; err != nil {
+		return err
+	}
+
+	var links []GuildNetwork
+	if len(guilds) > 0 {
+		ids := make([]string, 0, len(guilds))
+		for _, guild := range guilds {
+			ids = append(ids, guild.GuildID)
+		}
+		if err := m.db.Where("guild_id IN ?", ids).Order("id").Find(&links).Error; err != nil {
+			return err
+		}
+	} else if m.legacy.GuildID != "" {
+		var networks []Network
+		if err := m.db.Order("id").Find(&networks).Error; err != nil {
+			return err
+		}
+		guilds = []Guild{m.legacy}
+		for _, network := range networks {
+			if network.DiscordChannelID == "" && network.PolkassemblySeed == "" {
+				continue
+			}
+			links = append(links, GuildNetwork{
+				GuildID:          m.legacy.GuildID,
+				NetworkID:        network.ID,
+				DiscordChannelID: network.DiscordChannelID,
+				PolkassemblySeed: network.PolkassemblySeed,
+			})
+		}
+	}
+
+	byID := make(map[string]*Guild, len(guilds))
+	order := make([]string, 0, len(guilds))
+	for i := range guilds {
+		guild := &guilds[i]
+		byID[guild.GuildID] = guild
+		order = append(order, guild.GuildID)
+	}
+	sort.Strings(order)
+
+	byGuild := make(map[string][]GuildNetwork)
+	byChannel := make(map[string]GuildNetwork)
+	for _, link := range links {
+		byGuild[link.GuildID] = append(byGuild[link.GuildID], link)
+		if link.DiscordChannelID != "" {
+			byChannel[link.DiscordChannelID] = link
+		}
+	}
+
+	m.mu.Lock()
+	m.guilds = byID
+	m.order = order
+	m.links = byGuild
+	m.byChannel = byChannel
+	m.mu.Unlock()
+	return nil
+}
+
+// Guilds returns the active guilds ordered by ID
+func (m *GuildManager) Guilds() []*Guild {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]*Guild, 0, len(m.order))
+	for _, id := range m.order {
+		result = append(result, m.guilds[id])
+	}
+	return result
+}
+
+// Get returns an active guild by Discord ID, or nil
+func (m *GuildManager) Get(guildID string) *Guild {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.guilds[guildID]
+}
+
+// Networks returns a guild's network mappings
+func (m *GuildManager) Networks(guildID string) []GuildNetwork {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]GuildNetwork(nil), m.links[guildID]...)
+}
+
+// Accounts returns the network mappings that carry a Polkassembly seed
+func (m *GuildManager) Accounts() []GuildNetwork {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var accounts []GuildNetwork
+	for _, id := range m.order {
+		for _, link := range m.links[id] {
+			if strings.TrimSpace(link.PolkassemblySeed) != "" {
+				accounts = append(accounts, link)
+			}
+		}
+	}
+	return accounts
+}
+
+// FindByChannelID returns the guild mapping of a referendum channel
+func (m *GuildManager) FindByChannelID(channelID string) *GuildNetwork {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if link, ok := m.byChannel[channelID]; ok {
+		return &link
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package gov
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// identityFields lists the identity info fields shown next to the display
+// name, in display order
+var identityFields = []struct{ key, label string }{
+	{"legal", "Legal"},
+	{"web", "Web"},
+	{"email", "Email"},
+	{"twitter", "Twitter"},
+	{"github", "GitHub"},
+	{"matrix", "Matrix"},
+	{"riot", "Riot"},
+	{"discord", "Discord"},
+}
+
+// AccountIdentity caches the on-chain identity of an address GovComms
+// displays, resolved from the Identity pallet of the network's People chain
+type AccountIdentity struct {
+	ID            uint64  `gorm:"primaryKey;autoIncrement"`
+	NetworkID     uint8   `gorm:"index:idx_account_identity,unique"`
+	Address       string  `gorm:"size:128;index:idx_account_identity,unique"`
+	HasIdentity   bool    // false when the account has no identity or sub-identity
+	Display       *string `gorm:"size:255"`
+	Fields        *string `gorm:"type:text"` // JSON object of the remaining info fields
+	Judgements    *string `gorm:"type:text"` // JSON array of registrar judgements
+	Judgement     string  `gorm:"size:16"`   // strongest judgement, "none" without any
+	Verified      bool    // judged Reasonable or KnownGood
+	ParentAddress *string `gorm:"size:128"`
+	ParentDisplay *string `gorm:"size:255"`
+	SubName       *string `gorm:"size:255"`
+	ResolvedAt    time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Name returns the identity's display name, qualified with the sub-identity
+// name for sub-accounts (e.g. "Parent/sub")
+func (a *AccountIdentity) Name() string {
+	if a == nil || !a.HasIdentity {
+		return ""
+	}
+	name := ""
+	if a.Display != nil {
+		name = *a.Display
+	}
+	if a.ParentAddress != nil && a.SubName != nil && *a.SubName != "" {
+		if name == "" {
+			return *a.SubName
+		}
+		return name + "/" + *a.SubName
+	}
+	return name
+}
+
+// Label annotates an address with its identity and strongest judgement,
+// e.g. "Alice/ops (Reasonable) - 1abc..."
+func (a *AccountIdentity) Label(address string) string {
+	name := a.Name()
+	if name == "" {
+		return address
+	}
+	return fmt.Sprintf("%s (%s) - %s", name, a.Judgement, address)
+}
+
+// Details lists the identity's verified state, parent and info fields, one
+// entry per line
+func (a *AccountIdentity) Details() []string {
+	if a == nil || !a.HasIdentity {
+		return []string{"No on-chain identity"}
+	}
+
+	var lines []string
+	status := "Judgement: " + a.Judgement
+	if a.Verified {
+		status += " (verified)"
+	}
+	lines = append(lines, status)
+	if a.ParentAddress != nil {
+		parent := *a.ParentAddress
+		if a.ParentDisplay != nil && *a.ParentDisplay != "" {
+			parent = *a.ParentDisplay + " - " + parent
+		}
+		lines = append(lines, "Sub-identity of "+parent)
+	}
+
+	fields := a.FieldMap()
+	for _, field := range identityFields {
+		if value := fields[field.key]; value != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", field.label, value))
+		}
+	}
+	return lines
+}
+
+// FieldMap decodes the identity's info fields other than the display name
+func (a *AccountIdentity) FieldMap() map[string]string {
+	fields := make(map[string]string)
+	if a == nil || a.Fields == nil || *a.Fields == "" {
+		return fields
+	}
+	_ = json.Unmarshal([]byte(*a.Fields), &fields)
+	return fields
+}
+
+// IdentityDirectory maps addresses to their cached identities
+type IdentityDirectory map[string]*AccountIdentity
+
+// LoadIdentities returns the cached identities of the given addresses
+func LoadIdentities(db *gorm.DB, networkID uint8, addresses []string) (IdentityDirectory, error) {
+	directory := make(IdentityDirectory)
+	if len(addresses) == 0 {
+		return directory, nil
+	}
+
+	var rows []AccountIdentity
+	if err := db.Where("network_id = ? AND address IN ?", networkID, addresses).Find(&rows).Error; err != nil {
+		return directory, err
+	}
+	for i := range rows {
+		directory[rows[i].Address] = &rows[i]
+	}
+	return directory, nil
+}
+
+// Label annotates an address with its identity when one is cached
+func (d IdentityDirectory) Label(address string) string {
+	if identity, ok := d[address]; ok {
+		return identity.Label(address)
+	}
+	return address
+}
+
+// RefAccount is an address attached to a referendum and its role
+type RefAccount struct {
+	Role    string
+	Address string
+}
+
+// RefAccounts lists the submitter, deposit holders and beneficiaries of a
+// referendum, each address once
+func RefAccounts(ref *Ref, beneficiaries []string) []RefAccount {
+	var accounts []RefAccount
+	seen := make(map[string]bool)
+	add := func(role string, address *string) {
+		if address == nil || *address == "" || *address == "Unknown" || seen[*address] {
+			return
+		}
+		seen[*address] = true
+		accounts = append(accounts, RefAccount{Role: role, Address: *address})
+	}
+
+	if ref != nil {
+		add("Submitter", &ref.Submitter)
+		add("Submission deposit", ref.SubmissionDepositWho)
+		add("Decision deposit", ref.DecisionDepositWho)
+	}
+	for i := range beneficiaries {
+		add("Beneficiary", &beneficiaries[i])
+	}
+	return accounts
+}
+
+// Addresses returns the addresses of the accounts
+func Addresses(accounts []RefAccount) []string {
+	addresses := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		addresses = append(addresses, account.Address)
+	}
+	return addresses
+}
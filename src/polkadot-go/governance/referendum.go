@@ -14,6 +14,7 @@ import (
 // ThreadInfo contains referendum thread information
 type ThreadInfo struct {
 	ThreadID  string
+	GuildID   string
 	RefID     uint64
 	RefDBID   uint64
 	NetworkID uint8
@@ -39,6 +40,7 @@ func (m *ReferendumManager) FindThread(threadID string) (*ThreadInfo, error) {
 
 	return &ThreadInfo{
 		ThreadID:  refThread.ThreadID,
+		GuildID:   refThread.GuildID,
 		RefID:     refThread.RefID,
 		RefDBID:   refThread.RefDBID,
 		NetworkID: refThread.NetworkID,
@@ -55,12 +57,50 @@ func (m *ReferendumManager) GetThreadInfo(networkID uint8, refID uint32) (*Threa
 
 	return &ThreadInfo{
 		ThreadID:  refThread.ThreadID,
+		GuildID:   refThread.GuildID,
 		RefID:     refThread.RefID,
 		RefDBID:   refThread.RefDBID,
 		NetworkID: refThread.NetworkID,
 	}, nil
 }
 
+// GetGuildThreadInfo gets a guild's thread for a referendum
+func (m *ReferendumManager) GetGuildThreadInfo(guildID string, networkID uint8, refID uint32) (*ThreadInfo, error) {
+	var refThread RefThread
+	err := m.db.Where("guild_id = ? AND network_id = ? AND ref_id = ?", guildID, networkID, refID).First(&refThread).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &ThreadInfo{
+		ThreadID:  refThread.ThreadID,
+		GuildID:   refThread.GuildID,
+		RefID:     refThread.RefID,
+		RefDBID:   refThread.RefDBID,
+		NetworkID: refThread.NetworkID,
+	}, nil
+}
+
+// ListThreads returns every guild's thread for a referendum
+func (m *ReferendumManager) ListThreads(networkID uint8, refID uint32) ([]ThreadInfo, error) {
+	var refThreads []RefThread
+	if err := m.db.Where("network_id = ? AND ref_id = ?", networkID, refID).Order("id").Find(&refThreads).Error; err != nil {
+		return nil, err
+	}
+
+	threads := make([]ThreadInfo, 0, len(refThreads))
+	for _, refThread := range refThreads {
+		threads = append(threads, ThreadInfo{
+			ThreadID:  refThread.ThreadID,
+			GuildID:   refThread.GuildID,
+			RefID:     refThread.RefID,
+			RefDBID:   refThread.RefDBID,
+			NetworkID: refThread.NetworkID,
+		})
+	}
+	return threads, nil
+}
+
 // ParseRefIDFromTitle extracts referendum number from a thread title
 func ParseRefIDFromTitle(title string) (uint32, error) {
 	// Extract referendum number from title using regex to handle special characters
@@ -93,8 +133,8 @@ func ParseRefIDFromTitle(title string) (uint32, error) {
 	return uint32(refNum), nil
 }
 
-// UpsertThreadMapping links a Discord thread to a referendum record.
-func (m *ReferendumManager) UpsertThreadMapping(networkID uint8, refID uint32, threadID string) error {
+// UpsertThreadMapping links a guild's Discord thread to a referendum record.
+func (m *ReferendumManager) UpsertThreadMapping(guildID string, networkID uint8, refID uint32, threadID string) error {
 	if threadID == "" {
 		return fmt.Errorf("threadID cannot be empty")
 	}
@@ -116,6 +156,7 @@ func (m *ReferendumManager) UpsertThreadMapping(networkID uint8, refID uint32, t
 
 	thread := RefThread{
 		ThreadID:  threadID,
+		GuildID:   guildID,
 		RefDBID:   ref.ID,
 		NetworkID: networkID,
 		RefID:     ref.RefID,
@@ -124,6 +165,6 @@ func (m *ReferendumManager) UpsertThreadMapping(networkID uint8, refID uint32, t
 
 	return m.db.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "thread_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"ref_db_id", "network_id", "ref_id", "updated_at"}),
+		DoUpdates: clause.AssignmentColumns([]string{"guild_id", "ref_db_id", "network_id", "ref_id", "updated_at"}),
 	}).Create(&thread).Error
 }
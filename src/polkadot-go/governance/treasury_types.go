@@ -0,0 +1,94 @@
+package gov
+
+import (
+	"fmt"
+	"time"
+)
+
+// Payout status of a treasury spend
+const (
+	SpendStatusPending = "pending"
+	SpendStatusPaid    = "paid"
+	SpendStatusFailed  = "failed"
+	SpendStatusExpired = "expired"
+	SpendStatusCleared = "cleared" // removed from storage with no outcome seen
+)
+
+// Kinds of treasury spend a referendum can create
+const (
+	SpendKindSpend      = "spend"       // Treasury.spend, tracked in Treasury.Spends
+	SpendKindSpendLocal = "spend_local" // Treasury.spend_local, tracked in Treasury.Proposals
+)
+
+// RefSpend follows a treasury spend created by an approved referendum from
+// approval until it is paid, fails or expires
+type RefSpend struct {
+	ID            uint64 `gorm:"primaryKey;autoIncrement"`
+	RefDBID       uint64 `gorm:"index"`
+	NetworkID     uint8  `gorm:"index:idx_ref_spend_unique,unique"`
+	RefID         uint64
+	Kind          string  `gorm:"size:16;index:idx_ref_spend_unique,unique"`
+	SpendIndex    uint32  `gorm:"index:idx_ref_spend_unique,unique"`
+	Asset         *string `gorm:"size:32"`
+	Amount        string  `gorm:"size:64"`
+	AmountDisplay *string `gorm:"size:64"`
+	Beneficiary   *string `gorm:"size:128"`
+	ValidFrom     uint64
+	ExpireAt      uint64
+	ChainState    *string `gorm:"size:16"` // Pending, Attempted, Failed; nil once removed
+	Status        string  `gorm:"size:16;index"`
+	PaymentID     *uint64
+	PayoutBlock   *uint64
+	Note          *string `gorm:"size:255"`
+	AlertedAt     *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Describe renders the spend for Discord, reports and prompts
+func (s *RefSpend) Describe() string {
+	amount := s.Amount
+	if s.AmountDisplay != nil && *s.AmountDisplay != "" {
+		amount = *s.AmountDisplay
+	}
+	line := amount
+	if s.Beneficiary != nil && *s.Beneficiary != "" {
+		line += " to " + *s.Beneficiary
+	}
+	return line
+}
+
+// StatusLine renders the spend with its payout status
+func (s *RefSpend) StatusLine() string {
+	label := "Spend"
+	if s.Kind == SpendKindSpendLocal {
+		label = "Treasury proposal"
+	}
+	line := fmt.Sprintf("%s #%d: %s - %s", label, s.SpendIndex, s.Describe(), s.Status)
+
+	switch {
+	case s.Status == SpendStatusPaid && s.PayoutBlock != nil:
+		line += fmt.Sprintf(" at block #%d", *s.PayoutBlock)
+	case s.Status == SpendStatusPending && s.PayoutBlock != nil:
+		line += fmt.Sprintf(", payout attempted at block #%d", *s.PayoutBlock)
+	case s.Status == SpendStatusPending && s.ExpireAt > 0:
+		line += fmt.Sprintf(", claimable from block #%d until #%d", s.ValidFrom, s.ExpireAt)
+	case s.Status == SpendStatusExpired && s.ExpireAt > 0:
+		line += fmt.Sprintf(" unclaimed at block #%d", s.ExpireAt)
+	}
+	if s.Note != nil && *s.Note != "" {
+		line += " (" + *s.Note + ")"
+	}
+	return line
+}
+
+// SpendBeneficiaries returns the recipients of the spends
+func SpendBeneficiaries(spends []RefSpend) []string {
+	var beneficiaries []string
+	for _, spend := range spends {
+		if spend.Beneficiary != nil && *spend.Beneficiary != "" {
+			beneficiaries = append(beneficiaries, *spend.Beneficiary)
+		}
+	}
+	return beneficiaries
+}
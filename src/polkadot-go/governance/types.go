@@ -11,12 +11,16 @@ type Network struct {
 	DiscordChannelID string  `gorm:"size:64"`
 	PolkassemblySeed string  `gorm:"size:512"`
 	SS58Prefix       *uint16 `gorm:"column:ss58_prefix"`
+	// ReferendaPallet is the referenda pallet instance indexed for this
+	// network, e.g. FellowshipReferenda on the Collectives chain
+	ReferendaPallet string `gorm:"size:64;not null;default:Referenda"`
 }
 
 // RefThread maps Discord thread IDs to referendum information
 type RefThread struct {
 	ID        uint64 `gorm:"primaryKey;autoIncrement"`
 	ThreadID  string `gorm:"uniqueIndex"`
+	GuildID   string `gorm:"size:64;index"`
 	RefDBID   uint64 `gorm:"index"`
 	NetworkID uint8  `gorm:"index"`
 	RefID     uint64 `gorm:"index"`
@@ -0,0 +1,153 @@
+package gov
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// priceReloadInterval is how long loaded prices are used before the
+// ai_model_prices table is read again
+const priceReloadInterval = time.Minute
+
+// AITokenUsage is a ledger entry for the tokens one model request consumed
+type AITokenUsage struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement"`
+	Provider     string    `gorm:"size:32;index:idx_ai_token_usage_model,priority:1"`
+	Model        string    `gorm:"size:128;index:idx_ai_token_usage_model,priority:2"`
+	Feature      string    `gorm:"size:32;index:idx_ai_token_usage_feature"` // question, summary, research, report, consensus or agents
+	NetworkID    uint8     `gorm:"index:idx_ai_token_usage_ref,priority:1"`  // 0 when not about a referendum
+	RefID        uint32    `gorm:"index:idx_ai_token_usage_ref,priority:2"`
+	Command      string    `gorm:"size:32"`                                // slash command the request answered, if any
+	UserID       string    `gorm:"size:64;index:idx_ai_token_usage_user"`  // Discord user who asked, if any
+	GuildID      string    `gorm:"size:64;index:idx_ai_token_usage_guild"` // Discord guild asked in, if any
+	InputTokens  uint32    // prompt tokens, including cached ones
+	OutputTokens uint32    // completion tokens, including reasoning
+	CachedTokens uint32    // prompt tokens served from the provider's cache
+	CostUSD      *float64  `gorm:"column:cost_usd;type:decimal(12,6)"` // nil when no price matched
+	CreatedAt    time.Time `gorm:"index"`
+}
+
+// AIModelPrice is the list price of a model in US dollars per million tokens.
+// Model matches the model IDs the API reports by prefix, so "gpt-4o" prices
+// "gpt-4o-2024-08-06"; the longest match wins. An empty Provider applies to
+// every provider serving the model.
+type AIModelPrice struct {
+	ID                    uint64   `gorm:"primaryKey;autoIncrement"`
+	Provider              string   `gorm:"size:32"`
+	Model                 string   `gorm:"size:128"`
+	InputUSDPerMTok       float64  `gorm:"column:input_usd_per_mtok;type:decimal(10,4)"`
+	CachedInputUSDPerMTok *float64 `gorm:"column:cached_input_usd_per_mtok;type:decimal(10,4)"` // nil bills cached tokens as input
+	OutputUSDPerMTok      float64  `gorm:"column:output_usd_per_mtok;type:decimal(10,4)"`
+	Active                bool     `gorm:"default:true"`
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+// Cost prices a request's tokens
+func (p AIModelPrice) Cost(input, cached, output uint32) float64 {
+	cachedPrice := p.InputUSDPerMTok
+	if p.CachedInputUSDPerMTok != nil {
+		cachedPrice = *p.CachedInputUSDPerMTok
+	}
+	uncached := input
+	if cached < uncached {
+		uncached -= cached
+	} else {
+		uncached = 0
+	}
+	return (float64(uncached)*p.InputUSDPerMTok + float64(cached)*cachedPrice + float64(output)*p.OutputUSDPerMTok) / 1e6
+}
+
+// UsageLedger records token usage and prices it from the ai_model_prices
+// table, reloading the prices every minute
+type UsageLedger struct {
+	db       *gorm.DB
+	prices   []AIModelPrice
+	loadedAt time.Time
+	mu       sync.RWMutex
+}
+
+// NewUsageLedger creates a usage ledger. Prices load on first use so a
+// missing table doesn't stop the bots.
+func NewUsageLedger(db *gorm.DB) *UsageLedger {
+	return &UsageLedger{db: db}
+}
+
+// Reload reads the active prices from the database
+func (l *UsageLedger) Reload() error {
+	var rows []AIModelPrice
+	if err := l.db.Where("active = ?", true).Find(&rows).Error; err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.prices = rows
+	l.loadedAt = time.Now()
+	l.mu.Unlock()
+	return nil
+}
+
+// Price returns the price that applies to a provider's model
+func (l *UsageLedger) Price(provider, model string) (AIModelPrice, bool) {
+	l.mu.RLock()
+	stale := time.Since(l.loadedAt) > priceReloadInterval
+	l.mu.RUnlock()
+	if stale {
+		if err := l.Reload(); err != nil {
+			log.Printf("usage: price reload failed, keeping previous prices: %v", err)
+			l.mu.Lock()
+			l.loadedAt = time.Now()
+			l.mu.Unlock()
+		}
+	}
+
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	model = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(model), "models/"))
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var best AIModelPrice
+	found := false
+	for _, price := range l.prices {
+		priceProvider := strings.ToLower(strings.TrimSpace(price.Provider))
+		prefix := strings.ToLower(strings.TrimSpace(price.Model))
+		if prefix == "" || !strings.HasPrefix(model, prefix) {
+			continue
+		}
+		if priceProvider != "" && priceProvider != provider {
+			continue
+		}
+		// A longer model prefix is more specific; on a tie the provider's
+		// own price beats the one for every provider
+		if found {
+			bestPrefix := len(strings.TrimSpace(best.Model))
+			switch {
+			case len(prefix) < bestPrefix:
+				continue
+			case len(prefix) == bestPrefix && (priceProvider == "" || strings.TrimSpace(best.Provider) != ""):
+				continue
+			}
+		}
+		best = price
+		found = true
+	}
+	return best, found
+}
+
+// Record prices a usage entry and stores it. Failures are logged rather than
+// returned so that accounting never fails an AI call.
+func (l *UsageLedger) Record(entry AITokenUsage) {
+	if price, ok := l.Price(entry.Provider, entry.Model); ok {
+		cost := price.Cost(entry.InputTokens, entry.CachedTokens, entry.OutputTokens)
+		entry.CostUSD = &cost
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if err := l.db.Create(&entry).Error; err != nil {
+		log.Printf("usage: failed to record %s/%s usage for %s: %v", entry.Provider, entry.Model, entry.Feature, err)
+	}
+}
@@ -0,0 +1,42 @@
+package gov
+
+import "time"
+
+// ConvictionVote is an on-chain conviction vote on a referendum
+type ConvictionVote struct {
+	ID               uint64 `gorm:"primaryKey;autoIncrement"`
+	RefDBID          uint64 `gorm:"index"`
+	NetworkID        uint8  `gorm:"index:idx_cv_network_ref_voter,unique"`
+	RefID            uint64 `gorm:"index:idx_cv_network_ref_voter,unique"`
+	Voter            string `gorm:"size:128;index:idx_cv_network_ref_voter,unique"`
+	TrackID          uint16
+	VoteType         string `gorm:"size:16"` // Standard, Split, SplitAbstain
+	Aye              *bool
+	Conviction       uint8
+	Balance          *string `gorm:"size:64"`
+	AyeBalance       *string `gorm:"size:64"`
+	NayBalance       *string `gorm:"size:64"`
+	AbstainBalance   *string `gorm:"size:64"`
+	DelegatedVotes   *string `gorm:"size:64"`
+	DelegatedCapital *string `gorm:"size:64"`
+	ClassLock        *string `gorm:"size:64"`
+	SnapshotBlock    string  `gorm:"size:80"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// ConvictionDelegation records an account delegating voting power on a track.
+// Rows are never rewritten: a changed delegation closes the old row (EndedAt)
+// and opens a new one, so movement of delegated power is preserved.
+type ConvictionDelegation struct {
+	ID         uint64 `gorm:"primaryKey;autoIncrement"`
+	NetworkID  uint8  `gorm:"index:idx_cd_network_delegator"`
+	Delegator  string `gorm:"size:128;index:idx_cd_network_delegator"`
+	Target     string `gorm:"size:128;index"`
+	TrackID    uint16
+	Conviction uint8
+	Balance    string  `gorm:"size:64"`
+	ClassLock  *string `gorm:"size:64"`
+	StartedAt  time.Time
+	EndedAt    *time.Time
+}
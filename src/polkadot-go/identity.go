@@ -0,0 +1,297 @@
+package polkadot
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/mr-tron/base58"
+)
+
+// Registrar judgements as stored in Identity.IdentityOf
+const (
+	JudgementUnknown    = "Unknown"
+	JudgementFeePaid    = "FeePaid"
+	JudgementReasonable = "Reasonable"
+	JudgementKnownGood  = "KnownGood"
+	JudgementOutOfDate  = "OutOfDate"
+	JudgementLowQuality = "LowQuality"
+	JudgementErroneous  = "Erroneous"
+)
+
+// Judgement is a registrar's verdict on an identity
+type Judgement struct {
+	Registrar uint32 `json:"registrar"`
+	Judgement string `json:"judgement"`
+}
+
+// OnChainIdentity is an account's identity as read from the Identity pallet.
+// Fields holds the info fields that are set (display, legal, web, email,
+// twitter, github, matrix, discord, ...). For a sub-identity the fields
+// belong to the parent account and SubName is the name the parent gave it.
+type OnChainIdentity struct {
+	Address    string            `json:"address"`
+	Fields     map[string]string `json:"fields,omitempty"`
+	Judgements []Judgement       `json:"judgements,omitempty"`
+	Parent     string            `json:"parent,omitempty"`
+	SubName    string            `json:"subName,omitempty"`
+}
+
+// Display returns the identity's display name
+func (id *OnChainIdentity) Display() string {
+	return id.Fields["display"]
+}
+
+// Verified reports whether a registrar judged the identity Reasonable or KnownGood
+func (id *OnChainIdentity) Verified() bool {
+	for _, j := range id.Judgements {
+		if j.Judgement == JudgementReasonable || j.Judgement == JudgementKnownGood {
+			return true
+		}
+	}
+	return false
+}
+
+// JudgementStatus summarises the judgements, strongest first
+func (id *OnChainIdentity) JudgementStatus() string {
+	if len(id.Judgements) == 0 {
+		return "none"
+	}
+	rank := map[string]int{
+		JudgementKnownGood:  0,
+		JudgementReasonable: 1,
+		JudgementFeePaid:    2,
+		JudgementUnknown:    3,
+		JudgementOutOfDate:  4,
+		JudgementLowQuality: 5,
+		JudgementErroneous:  6,
+	}
+	best := id.Judgements[0].Judgement
+	for _, j := range id.Judgements[1:] {
+		if rank[j.Judgement] < rank[best] {
+			best = j.Judgement
+		}
+	}
+	return best
+}
+
+// GetIdentity resolves an account's identity on a chain with the Identity
+// pallet (the People chain). A sub-identity resolves to its parent's
+// registration. Returns nil when the account has no identity.
+func (c *Client) GetIdentity(address string) (*OnChainIdentity, error) {
+	if !c.HasPallet("Identity") {
+		return nil, fmt.Errorf("identity pallet not available")
+	}
+
+	accountID, err := SS58ToAccountID(address)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &OnChainIdentity{Address: address}
+	owner := accountID
+
+	superOf, err := c.readStorageMapEntry("Identity", "SuperOf", accountID[:])
+	if err != nil {
+		return nil, err
+	}
+	if parent, name, ok := subIdentityOf(superOf); ok {
+		identity.Parent = parent
+		identity.SubName = name
+		if owner, err = SS58ToAccountID(parent); err != nil {
+			return nil, err
+		}
+	}
+
+	registration, err := c.readStorageMapEntry("Identity", "IdentityOf", owner[:])
+	if err != nil {
+		return nil, err
+	}
+	if !identity.applyRegistration(registration) && identity.Parent == "" {
+		return nil, nil
+	}
+	return identity, nil
+}
+
+// subIdentityOf reads a decoded Identity.SuperOf value: the parent account
+// and the name it gave the sub-identity
+func subIdentityOf(value interface{}) (parent, name string, ok bool) {
+	pair, ok := value.([]interface{})
+	if !ok || len(pair) != 2 {
+		return "", "", false
+	}
+	account, ok := pair[0].(AccountValue)
+	if !ok {
+		return "", "", false
+	}
+	return string(account), identityData(pair[1]), true
+}
+
+// applyRegistration fills in the info fields and judgements of a decoded
+// Identity.IdentityOf value, reporting false when there is none
+func (id *OnChainIdentity) applyRegistration(registration interface{}) bool {
+	// Older runtimes store (Registration, Option<Username>)
+	if tuple, ok := registration.([]interface{}); ok && len(tuple) > 0 {
+		registration = tuple[0]
+	}
+	fields, ok := registration.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	if info, ok := fields["info"].(map[string]interface{}); ok {
+		id.Fields = make(map[string]string)
+		for name, value := range info {
+			if text := identityData(value); text != "" {
+				id.Fields[name] = text
+			}
+		}
+	}
+
+	judgements, _ := fields["judgements"].([]interface{})
+	for _, entry := range judgements {
+		pair, ok := entry.([]interface{})
+		if !ok || len(pair) != 2 {
+			continue
+		}
+		registrar, _ := pair[0].(uint64)
+		judgement := Judgement{Registrar: uint32(registrar)}
+		switch value := pair[1].(type) {
+		case string:
+			judgement.Judgement = value
+		case map[string]interface{}:
+			for name := range value {
+				judgement.Judgement = name
+			}
+		}
+		id.Judgements = append(id.Judgements, judgement)
+	}
+	sort.Slice(id.Judgements, func(i, j int) bool {
+		return id.Judgements[i].Registrar < id.Judgements[j].Registrar
+	})
+	return true
+}
+
+// identityData renders an Identity Data value. Raw data is shown as text;
+// hashed data cannot be recovered and is left empty.
+func identityData(v interface{}) string {
+	value, ok := v.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	for variant, inner := range value {
+		if !strings.HasPrefix(variant, "Raw") {
+			continue
+		}
+		encoded, _ := inner.(string)
+		raw, err := DecodeHex(encoded)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(raw))
+	}
+	return ""
+}
+
+// readStorageMapEntry decodes a single value of a storage map, hashing the
+// key with the hasher declared in the metadata. Returns nil when unset.
+func (c *Client) readStorageMapEntry(pallet, item string, key []byte) (interface{}, error) {
+	metadata := c.runtimeMetadata()
+	if metadata == nil || metadata.Version < 14 {
+		return nil, fmt.Errorf("storage maps require metadata v14")
+	}
+	meta := &metadata.AsMetadataV14
+
+	typeID, ok := c.storageMapValueType(pallet, item)
+	if !ok {
+		return nil, fmt.Errorf("%s.%s not found in metadata", pallet, item)
+	}
+	hasher, ok := c.storageMapHasher(pallet, item)
+	if !ok {
+		return nil, fmt.Errorf("%s.%s has an unsupported hasher", pallet, item)
+	}
+
+	storageKey := StorageKey(pallet, item) + HexEncode(hasher.Hash(key))
+	value, err := c.GetStorage(storageKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get %s.%s: %w", pallet, item, err)
+	}
+	data, err := DecodeHex(value)
+	if err != nil || len(data) == 0 {
+		return nil, nil
+	}
+
+	decoded, err := newCallDecoder(meta, c.accountIDToSS58, data).decodeValue(typeID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s.%s: %w", pallet, item, err)
+	}
+	return decoded, nil
+}
+
+// storageMapHasher returns the hasher of a single-key storage map
+func (c *Client) storageMapHasher(pallet, item string) (Hasher, bool) {
+	metadata := c.runtimeMetadata()
+	if metadata == nil || metadata.Version < 14 {
+		return nil, false
+	}
+	for _, p := range metadata.AsMetadataV14.Pallets {
+		if string(p.Name) != pallet || !p.HasStorage {
+			continue
+		}
+		for _, entry := range p.Storage.Items {
+			if string(entry.Name) != item || !entry.Type.IsMap || len(entry.Type.AsMap.Hashers) != 1 {
+				continue
+			}
+			switch h := entry.Type.AsMap.Hashers[0]; {
+			case h.IsBlake2_128Concat:
+				return Blake2_128Concat{}, true
+			case h.IsTwox64Concat:
+				return Twox64Concat{}, true
+			case h.IsIdentity:
+				return Identity{}, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// SS58ToAccountID decodes an SS58 address of any network prefix, rejecting
+// addresses whose checksum does not match. Addresses stored by earlier
+// versions of accountIDToSS58, which checksummed with Blake2b-256, are
+// accepted too.
+func SS58ToAccountID(address string) (types.AccountID, error) {
+	var accountID types.AccountID
+	raw, err := base58.Decode(strings.TrimSpace(address))
+	if err != nil {
+		return accountID, fmt.Errorf("invalid SS58 address %q: %w", address, err)
+	}
+
+	prefixLen := 1
+	if len(raw) > 0 && raw[0]&0x40 != 0 {
+		prefixLen = 2
+	}
+	if len(raw) != prefixLen+32+2 {
+		return accountID, fmt.Errorf("invalid SS58 address %q: unexpected length %d", address, len(raw))
+	}
+
+	body, checksum := raw[:prefixLen+32], raw[prefixLen+32:]
+	legacy := Blake2_256(append([]byte("SS58PRE"), body...))[:2]
+	if !bytes.Equal(checksum, ss58Checksum(body)) && !bytes.Equal(checksum, legacy) {
+		return accountID, fmt.Errorf("invalid SS58 address %q: checksum mismatch", address)
+	}
+
+	copy(accountID[:], body[prefixLen:])
+	return accountID, nil
+}
+
+// NormalizeSS58 re-encodes an SS58 address of any network with the given
+// prefix, so the same account always compares equal
+func NormalizeSS58(address string, prefix uint16) (string, error) {
+	accountID, err := SS58ToAccountID(address)
+	if err != nil {
+		return "", err
+	}
+	return accountIDToSS58WithPrefix(accountID, prefix), nil
+}
@@ -0,0 +1,183 @@
+package polkadot
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// //Alice on the networks whose prefixes GovComms encodes
+var aliceAddresses = map[uint16]string{
+	0:    "15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5",
+	2:    "HNZata7iMYWmk5RvZRTiAsSDhV8366zq2YGb3tLH5Upf74F",
+	42:   "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
+	1284: "VdvKmYJfD4VXA9fzz1SbmCo2eYHSzUFbaDCZSuaNKJAe8YNg6",
+}
+
+func TestSS58RoundTrip(t *testing.T) {
+	account := mustDecodeHex(t, aliceAccount)
+
+	for prefix, address := range aliceAddresses {
+		got, err := SS58ToAccountID(address)
+		if err != nil {
+			t.Fatalf("SS58ToAccountID(%s): %v", address, err)
+		}
+		if hex.EncodeToString(got[:]) != aliceAccount {
+			t.Errorf("SS58ToAccountID(%s) = %x, want //Alice", address, got[:])
+		}
+
+		var accountID [32]byte
+		copy(accountID[:], account)
+		if encoded := accountIDToSS58WithPrefix(accountID, prefix); encoded != address {
+			t.Errorf("accountIDToSS58WithPrefix(//Alice, %d) = %s, want %s", prefix, encoded, address)
+		}
+	}
+
+	// Surrounding whitespace from pasted addresses is ignored
+	if _, err := SS58ToAccountID(" " + aliceAddresses[0] + "\n"); err != nil {
+		t.Errorf("padded address: %v", err)
+	}
+	// Earlier versions of accountIDToSS58 checksummed with Blake2b-256
+	if _, err := SS58ToAccountID("15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6X1k"); err != nil {
+		t.Errorf("legacy checksum: %v", err)
+	}
+}
+
+func TestSS58ToAccountIDRejectsBadAddresses(t *testing.T) {
+	for address, want := range map[string]string{
+		"15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp6": "checksum mismatch",
+		"15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp0": "invalid SS58 address",
+		"15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6":    "unexpected length",
+		"": "invalid SS58 address",
+	} {
+		if _, err := SS58ToAccountID(address); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("SS58ToAccountID(%q) error = %v, want %q", address, err, want)
+		}
+	}
+}
+
+func TestNormalizeSS58(t *testing.T) {
+	for prefix, want := range aliceAddresses {
+		for _, address := range aliceAddresses {
+			if got, err := NormalizeSS58(address, prefix); err != nil || got != want {
+				t.Errorf("NormalizeSS58(%s, %d) = %s, %v; want %s", address, prefix, got, err, want)
+			}
+		}
+	}
+
+	if _, err := NormalizeSS58("not-an-address", 0); err == nil {
+		t.Error("NormalizeSS58 accepted an invalid address")
+	}
+}
+
+// decodeIdentityStorage decodes a captured Identity storage value with the
+// gsrpc test runtime's metadata
+func decodeIdentityStorage(t *testing.T, client *Client, item, value string) interface{} {
+	t.Helper()
+	typeID, ok := client.storageMapValueType("Identity", item)
+	if !ok {
+		t.Fatalf("Identity.%s not in test metadata", item)
+	}
+	decoded, err := newCallDecoder(&client.metadata.AsMetadataV14, client.accountIDToSS58, mustDecodeHex(t, value)).decodeValue(typeID, 0)
+	if err != nil {
+		t.Fatalf("decode Identity.%s: %v", item, err)
+	}
+	return decoded
+}
+
+func TestIdentityRegistration(t *testing.T) {
+	client := testMetadataClient(t)
+
+	// Judged FeePaid by registrar 3 and Reasonable by registrar 0, with a
+	// hashed email and an additional field, neither of which can be shown
+	verified := decodeIdentityStorage(t, client, "IdentityOf",
+		"0803000000016400000000000000000000000000000000000000020010a5d4e8000000000000000000000004"+
+			"08646973636f726408616c696365233106416c696365001268747470733a2f2f616c6963652e64657600"+
+			"22000000000000000000000000000000000000000000000000000000000000000000000740616c696365")
+	id := &OnChainIdentity{}
+	if !id.applyRegistration(verified) {
+		t.Fatal("applyRegistration found no registration")
+	}
+	wantFields := map[string]string{"display": "Alice", "web": "https://alice.dev", "twitter": "@alice"}
+	if !reflect.DeepEqual(id.Fields, wantFields) {
+		t.Errorf("Fields = %v, want %v", id.Fields, wantFields)
+	}
+	wantJudgements := []Judgement{{0, JudgementReasonable}, {3, JudgementFeePaid}}
+	if !reflect.DeepEqual(id.Judgements, wantJudgements) {
+		t.Errorf("Judgements = %v, want %v", id.Judgements, wantJudgements)
+	}
+	if !id.Verified() || id.JudgementStatus() != JudgementReasonable {
+		t.Errorf("Verified = %v, status %s; want verified and Reasonable", id.Verified(), id.JudgementStatus())
+	}
+
+	// Only a pending FeePaid judgement, stored as (Registration, Option<Username>)
+	pending := decodeIdentityStorage(t, client, "IdentityOf",
+		"040100000001050000000000000000000000000000000010a5d4e800000000000000000000000004426f6200000000000000")
+	id = &OnChainIdentity{}
+	if !id.applyRegistration([]interface{}{pending, nil}) {
+		t.Fatal("applyRegistration found no registration in the tuple")
+	}
+	if id.Display() != "Bob" || id.Verified() || id.JudgementStatus() != JudgementFeePaid {
+		t.Errorf("got %q verified %v status %s, want Bob unverified FeePaid", id.Display(), id.Verified(), id.JudgementStatus())
+	}
+
+	if (&OnChainIdentity{}).applyRegistration(nil) {
+		t.Error("applyRegistration accepted an unset value")
+	}
+}
+
+func TestSubIdentityOf(t *testing.T) {
+	client := testMetadataClient(t)
+
+	// //Alice named this account "bot"
+	superOf := decodeIdentityStorage(t, client, "SuperOf", aliceAccount+"04626f74")
+	parent, name, ok := subIdentityOf(superOf)
+	if !ok || parent != aliceAddresses[0] || name != "bot" {
+		t.Errorf("subIdentityOf = %s, %q, %v; want //Alice, \"bot\"", parent, name, ok)
+	}
+
+	if _, _, ok := subIdentityOf(nil); ok {
+		t.Error("subIdentityOf accepted an unset value")
+	}
+}
+
+func TestJudgementStatusRanking(t *testing.T) {
+	id := &OnChainIdentity{Judgements: []Judgement{
+		{0, JudgementErroneous},
+		{1, JudgementOutOfDate},
+		{2, JudgementKnownGood},
+		{3, JudgementReasonable},
+	}}
+	if got := id.JudgementStatus(); got != JudgementKnownGood {
+		t.Errorf("JudgementStatus = %s, want KnownGood", got)
+	}
+
+	id = &OnChainIdentity{Judgements: []Judgement{{0, JudgementLowQuality}, {1, JudgementUnknown}}}
+	if got := id.JudgementStatus(); got != JudgementUnknown || id.Verified() {
+		t.Errorf("JudgementStatus = %s verified %v, want Unknown and unverified", got, id.Verified())
+	}
+
+	if got := (&OnChainIdentity{}).JudgementStatus(); got != "none" {
+		t.Errorf("JudgementStatus without judgements = %s, want none", got)
+	}
+}
+
+func TestIdentityData(t *testing.T) {
+	for _, tc := range []struct {
+		value interface{}
+		want  string
+	}{
+		{map[string]interface{}{"Raw5": "0x416c696365"}, "Alice"},
+		{map[string]interface{}{"Raw7": "0x20416c69636520"}, "Alice"},
+		{map[string]interface{}{"Raw0": "0x"}, ""},
+		{map[string]interface{}{"None": nil}, ""},
+		{map[string]interface{}{"BlakeTwo256": "0x" + aliceAccount}, ""},
+		{map[string]interface{}{"Raw5": "not hex"}, ""},
+		{"Alice", ""},
+	} {
+		if got := identityData(tc.value); got != tc.want {
+			t.Errorf("identityData(%v) = %q, want %q", tc.value, got, tc.want)
+		}
+	}
+}
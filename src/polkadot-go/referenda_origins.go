@@ -1,9 +1,11 @@
 package polkadot
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 
-	"github.com/centrifuge/go-substrate-rpc-client/v4/scale"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
 )
 
 // getOriginName maps the origin variant to a name based on track info
@@ -61,58 +63,102 @@ func (c *Client) GetOriginsInfo() (map[uint8]string, error) {
 	return origins, nil
 }
 
-// decodeOrigin decodes the origin from referendum data
-func decodeOrigin(decoder *scale.Decoder) (string, error) {
-	// First byte is the origin type
-	originType, err := decoder.ReadOneByte()
-	if err != nil {
-		return "", fmt.Errorf("read origin type: %w", err)
+// originDecodeFunc decodes a referendum's origin, leaving the reader after it
+type originDecodeFunc func(reader *bytes.Reader) (string, error)
+
+// originDecoder returns a decoder for the pallet's origin type, the runtime's
+// OriginCaller enum, resolved from the ReferendumStatus in v14 metadata
+func (c *Client) originDecoder() originDecodeFunc {
+	metadata := c.runtimeMetadata()
+	if metadata == nil || metadata.Version < 14 {
+		return func(*bytes.Reader) (string, error) {
+			return "", fmt.Errorf("origin type needs v14 metadata")
+		}
+	}
+	meta := &metadata.AsMetadataV14
+	lookup := metadataLookup(meta)
+	typeID, ok := referendumOriginType(meta, lookup, c.ReferendaPallet())
+	if !ok {
+		return func(*bytes.Reader) (string, error) {
+			return "", fmt.Errorf("origin type of %s not found in metadata", c.ReferendaPallet())
+		}
 	}
 
-	if originType == 0 { // system
-		// System origin doesn't have additional data
-		return "system", nil
-	} else if originType == 1 { // Origins pallet
-		// Read the origin variant
-		originVariant, err := decoder.ReadOneByte()
+	return func(reader *bytes.Reader) (string, error) {
+		start, err := reader.Seek(0, io.SeekCurrent)
 		if err != nil {
-			return "", fmt.Errorf("read origin variant: %w", err)
+			return "", err
+		}
+		rest := make([]byte, reader.Len())
+		if _, err := io.ReadFull(reader, rest); err != nil {
+			return "", err
+		}
+
+		d := &callDecoder{meta: meta, lookup: lookup, encodeAccount: c.accountIDToSS58, data: rest}
+		value, err := d.decodeValue(typeID, 0)
+		if err != nil {
+			return "", err
+		}
+		if _, err := reader.Seek(start+int64(d.pos), io.SeekStart); err != nil {
+			return "", err
 		}
-		// We'll need the client context to properly map this
-		// For now, use the static mapping
-		return getOriginNameStatic(originVariant), nil
-	} else if originType == 2 { // Void
-		return "Void", nil
-	} else {
-		// Try to read as Origins variant directly
-		// Some older referenda might encode the origin differently
-		return getOriginNameStatic(originType), nil
+		return originName(value), nil
 	}
 }
 
-// getOriginNameStatic provides static fallback origin names
-func getOriginNameStatic(variant uint8) string {
-	origins := map[uint8]string{
-		0:  "Root",
-		1:  "WishForChange",
-		10: "StakingAdmin",
-		11: "Treasurer",
-		12: "LeaseAdmin",
-		13: "FellowshipAdmin",
-		14: "GeneralAdmin",
-		15: "AuctionAdmin",
-		20: "ReferendumCanceller",
-		21: "ReferendumKiller",
-		30: "SmallTipper",
-		31: "BigTipper",
-		32: "SmallSpender",
-		33: "MediumSpender",
-		34: "BigSpender",
-		35: "WhitelistedCaller",
+// referendumOriginType finds the type of the origin field of the Ongoing
+// variant of the pallet's ReferendumInfoFor value
+func referendumOriginType(meta *types.MetadataV14, lookup map[int64]*types.Si1Type, pallet string) (int64, bool) {
+	for _, p := range meta.Pallets {
+		if string(p.Name) != pallet || !p.HasStorage {
+			continue
+		}
+		for _, item := range p.Storage.Items {
+			if string(item.Name) != "ReferendumInfoFor" || !item.Type.IsMap {
+				continue
+			}
+			info, ok := lookup[item.Type.AsMap.Value.Int64()]
+			if !ok || !info.Def.IsVariant {
+				return 0, false
+			}
+			for _, variant := range info.Def.Variant.Variants {
+				if string(variant.Name) != "Ongoing" || len(variant.Fields) != 1 {
+					continue
+				}
+				status, ok := lookup[variant.Fields[0].Type.Int64()]
+				if !ok || !status.Def.IsComposite {
+					return 0, false
+				}
+				for _, field := range status.Def.Composite.Fields {
+					if field.HasName && string(field.Name) == "origin" {
+						return field.Type.Int64(), true
+					}
+				}
+			}
+			return 0, false
+		}
 	}
+	return 0, false
+}
 
-	if name, ok := origins[variant]; ok {
-		return name
+// originName names a decoded origin by its innermost variant, e.g. Root for
+// system(Root) or Treasurer for Origins(Treasurer)
+func originName(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case map[string]interface{}:
+		if len(val) == 1 {
+			for name, inner := range val {
+				switch inner.(type) {
+				case nil:
+					return name
+				case string, map[string]interface{}:
+					return originName(inner)
+				}
+				return fmt.Sprintf("%s(%s)", name, formatArgValue(inner))
+			}
+		}
 	}
-	return fmt.Sprintf("Origin(%d)", variant)
+	return formatArgValue(v)
 }
@@ -0,0 +1,115 @@
+package polkadot
+
+import (
+	"fmt"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+)
+
+// DefaultReferendaPallet is the OpenGov referenda pallet on relay chains.
+// The Collectives chain runs FellowshipReferenda and AmbassadorReferenda.
+const DefaultReferendaPallet = "Referenda"
+
+// SetReferendaPallet selects the referenda pallet instance the client reads
+// referenda, tracks and events from
+func (c *Client) SetReferendaPallet(pallet string) error {
+	if pallet == "" {
+		pallet = DefaultReferendaPallet
+	}
+	metadata := c.runtimeMetadata()
+	if metadata != nil && metadata.Version == 14 && !hasPallet(&metadata.AsMetadataV14, pallet) {
+		return fmt.Errorf("pallet %s not found in runtime metadata", pallet)
+	}
+
+	c.referendaPallet = pallet
+	rankedTally := c.detectRankedTally(metadata)
+	c.connMu.Lock()
+	c.rankedTally = rankedTally
+	c.connMu.Unlock()
+
+	// Tracks and constants come from the selected pallet
+	c.constantsMu.Lock()
+	c.constantsCache = make(map[string]interface{})
+	c.constantsMu.Unlock()
+
+	return nil
+}
+
+// ReferendaPallet returns the name of the referenda pallet in use
+func (c *Client) ReferendaPallet() string {
+	if c.referendaPallet == "" {
+		return DefaultReferendaPallet
+	}
+	return c.referendaPallet
+}
+
+// RankedTally reports whether the referenda pallet is tallied by a ranked
+// collective (one vote per member weighted by rank) instead of by
+// conviction-weighted balances
+func (c *Client) RankedTally() bool {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+	return c.rankedTally
+}
+
+// tallyDecoder returns the decoder matching the pallet's Tally type
+func (c *Client) tallyDecoder() tallyDecodeFunc {
+	if c.RankedTally() {
+		return decodeRankedTally
+	}
+	return decodeTally
+}
+
+// detectRankedTally inspects the Tally type parameter of the pallet's
+// ReferendumInfoFor value in metadata. Metadata without type info falls back
+// to the pallet name.
+func (c *Client) detectRankedTally(metadata *types.Metadata) bool {
+	pallet := c.ReferendaPallet()
+	if metadata == nil || metadata.Version != 14 {
+		return pallet != DefaultReferendaPallet
+	}
+	meta := &metadata.AsMetadataV14
+
+	lookup := metadataLookup(meta)
+
+	for _, p := range meta.Pallets {
+		if string(p.Name) != pallet || !p.HasStorage {
+			continue
+		}
+		for _, item := range p.Storage.Items {
+			if string(item.Name) != "ReferendumInfoFor" || !item.Type.IsMap {
+				continue
+			}
+			info, ok := lookup[item.Type.AsMap.Value.Int64()]
+			if !ok {
+				break
+			}
+			for _, param := range info.Params {
+				if string(param.Name) != "Tally" || !param.HasType {
+					continue
+				}
+				tally, ok := lookup[param.Type.Int64()]
+				if !ok {
+					break
+				}
+				for _, segment := range tally.Path {
+					if string(segment) == "pallet_ranked_collective" {
+						return true
+					}
+				}
+				return false
+			}
+		}
+	}
+
+	return pallet != DefaultReferendaPallet
+}
+
+func hasPallet(meta *types.MetadataV14, name string) bool {
+	for _, p := range meta.Pallets {
+		if string(p.Name) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -31,7 +31,7 @@ func (c *Client) GetReferendaConstants() (*ReferendaConstants, error) {
 	}
 
 	// Get tracks using chain constants
-	tracksData, err := c.getConstantValue("Referenda", "Tracks")
+	tracksData, err := c.getConstantValue(c.ReferendaPallet(), "Tracks")
 	if err != nil {
 		return nil, fmt.Errorf("get tracks constant: %w", err)
 	}
@@ -42,7 +42,7 @@ func (c *Client) GetReferendaConstants() (*ReferendaConstants, error) {
 	}
 
 	// Get undeciding timeout
-	timeoutData, err := c.getConstantValue("Referenda", "UndecidingTimeout")
+	timeoutData, err := c.getConstantValue(c.ReferendaPallet(), "UndecidingTimeout")
 	if err != nil {
 		return nil, fmt.Errorf("get undeciding timeout: %w", err)
 	}
@@ -52,7 +52,7 @@ func (c *Client) GetReferendaConstants() (*ReferendaConstants, error) {
 	}
 
 	// Get submission deposit
-	depositData, err := c.getConstantValue("Referenda", "SubmissionDeposit")
+	depositData, err := c.getConstantValue(c.ReferendaPallet(), "SubmissionDeposit")
 	if err != nil {
 		return nil, fmt.Errorf("get submission deposit: %w", err)
 	}
@@ -65,7 +65,7 @@ func (c *Client) GetReferendaConstants() (*ReferendaConstants, error) {
 	}
 
 	// Get max queued
-	maxQueuedData, err := c.getConstantValue("Referenda", "MaxQueued")
+	maxQueuedData, err := c.getConstantValue(c.ReferendaPallet(), "MaxQueued")
 	if err != nil {
 		return nil, fmt.Errorf("get max queued: %w", err)
 	}
@@ -124,12 +124,13 @@ func (c *Client) getConstantValue(pallet, constant string) ([]byte, error) {
 		return DecodeHex(data)
 	}
 
-	if c.metadata == nil {
+	metadata := c.runtimeMetadata()
+	if metadata == nil {
 		return nil, fmt.Errorf("metadata not loaded")
 	}
 
-	if c.metadata.Version == 14 {
-		meta := c.metadata.AsMetadataV14
+	if metadata.Version == 14 {
+		meta := metadata.AsMetadataV14
 		for _, p := range meta.Pallets {
 			if string(p.Name) != pallet {
 				continue
@@ -230,13 +231,15 @@ func (c *Client) decodeTracks(data []byte, tracks map[uint16]*TrackInfo) error {
 		offset += 4
 
 		// MinApproval (Curve enum)
-		approvalData, bytesRead := decodeCurve(data[offset:])
-		track.MinApproval = fmt.Sprintf("%v", approvalData)
+		approvalCurve, bytesRead := parseCurve(data[offset:])
+		track.ApprovalCurve = approvalCurve
+		track.MinApproval = fmt.Sprintf("%v", approvalCurve.describe())
 		offset += bytesRead
 
 		// MinSupport (Curve enum)
-		supportData, bytesRead := decodeCurve(data[offset:])
-		track.MinSupport = fmt.Sprintf("%v", supportData)
+		supportCurve, bytesRead := parseCurve(data[offset:])
+		track.SupportCurve = supportCurve
+		track.MinSupport = fmt.Sprintf("%v", supportCurve.describe())
 		offset += bytesRead
 
 		tracks[trackID] = track
@@ -248,8 +251,8 @@ func (c *Client) decodeTracks(data []byte, tracks map[uint16]*TrackInfo) error {
 // loadKnownTracks loads known track data as fallback
 func (c *Client) loadKnownTracks(tracks map[uint16]*TrackInfo) error {
 	// Query the actual tracks from storage
-	// Referenda.Tracks storage key prefix
-	prefix := "0x" + HexEncode(Twox128([]byte("Referenda"))) + HexEncode(Twox128([]byte("Tracks")))
+	// <pallet>.Tracks storage key prefix
+	prefix := "0x" + HexEncode(Twox128([]byte(c.ReferendaPallet()))) + HexEncode(Twox128([]byte("Tracks")))
 
 	keys, err := c.GetKeys(prefix, nil)
 	if err != nil {
@@ -280,7 +283,7 @@ func (c *Client) loadKnownTracks(tracks map[uint16]*TrackInfo) error {
 	}
 
 	// If no tracks found, use hardcoded ones based on the data you provided
-	if len(tracks) == 0 {
+	if len(tracks) == 0 && c.ReferendaPallet() == DefaultReferendaPallet {
 		tracks[0] = &TrackInfo{Name: "root", MaxDeciding: 1, DecisionDeposit: "1000000000000000", PreparePeriod: 1200, DecisionPeriod: 403200, ConfirmPeriod: 14400, MinEnactmentPeriod: 14400}
 		tracks[1] = &TrackInfo{Name: "whitelisted_caller", MaxDeciding: 100, DecisionDeposit: "100000000000000", PreparePeriod: 300, DecisionPeriod: 403200, ConfirmPeriod: 100, MinEnactmentPeriod: 100}
 		// Add other tracks as needed...
@@ -373,81 +376,6 @@ func (c *Client) decodeTrackData(data string) (*TrackInfo, error) {
 	return track, nil
 }
 
-// decodeCurve decodes a Curve enum (LinearDecreasing or Reciprocal)
-func decodeCurve(data []byte) (interface{}, int) {
-	if len(data) == 0 {
-		return nil, 0
-	}
-
-	variant := data[0]
-	offset := 1
-
-	switch variant {
-	case 0: // LinearDecreasing
-		result := map[string]interface{}{
-			"LinearDecreasing": map[string]interface{}{},
-		}
-		// length (Perbill - u32)
-		if len(data) >= offset+4 {
-			length := uint32(data[offset]) | uint32(data[offset+1])<<8 |
-				uint32(data[offset+2])<<16 | uint32(data[offset+3])<<24
-			result["LinearDecreasing"].(map[string]interface{})["length"] = formatPerbill(length)
-			offset += 4
-		}
-
-		// floor (Perbill - u32)
-		if len(data) >= offset+4 {
-			floor := uint32(data[offset]) | uint32(data[offset+1])<<8 |
-				uint32(data[offset+2])<<16 | uint32(data[offset+3])<<24
-			result["LinearDecreasing"].(map[string]interface{})["floor"] = formatPerbill(floor)
-			offset += 4
-		}
-
-		// ceil (Perbill - u32)
-		if len(data) >= offset+4 {
-			ceil := uint32(data[offset]) | uint32(data[offset+1])<<8 |
-				uint32(data[offset+2])<<16 | uint32(data[offset+3])<<24
-			result["LinearDecreasing"].(map[string]interface{})["ceil"] = formatPerbill(ceil)
-			offset += 4
-		}
-
-		return result, offset
-
-	case 1: // Reciprocal
-		result := map[string]interface{}{
-			"Reciprocal": map[string]interface{}{},
-		}
-		// factor (i32)
-		if len(data) >= offset+4 {
-			factor := int32(data[offset]) | int32(data[offset+1])<<8 |
-				int32(data[offset+2])<<16 | int32(data[offset+3])<<24
-			result["Reciprocal"].(map[string]interface{})["factor"] = factor
-			offset += 4
-		}
-
-		// xOffset (i32)
-		if len(data) >= offset+4 {
-			xOffset := int32(data[offset]) | int32(data[offset+1])<<8 |
-				int32(data[offset+2])<<16 | int32(data[offset+3])<<24
-			result["Reciprocal"].(map[string]interface{})["xOffset"] = xOffset
-			offset += 4
-		}
-
-		// yOffset (i32)
-		if len(data) >= offset+4 {
-			yOffset := int32(data[offset]) | int32(data[offset+1])<<8 |
-				int32(data[offset+2])<<16 | int32(data[offset+3])<<24
-			result["Reciprocal"].(map[string]interface{})["yOffset"] = yOffset
-			offset += 4
-		}
-
-		return result, offset
-
-	default:
-		return map[string]interface{}{"Unknown": variant}, 1
-	}
-}
-
 // formatPerbill formats a Perbill value as percentage
 func formatPerbill(value uint32) string {
 	// Perbill is parts per billion (1,000,000,000)
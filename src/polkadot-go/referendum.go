@@ -16,12 +16,12 @@ import (
 // GetReferendumInfo fetches and decodes referendum info
 func (c *Client) GetReferendumInfo(refID uint32) (*ReferendumInfo, error) {
 	// Create storage key for ReferendumInfoFor
-	key := createReferendumStorageKey(refID)
+	key := createReferendumStorageKey(c.ReferendaPallet(), refID)
 
 	// Query storage
 	var raw types.StorageDataRaw
 	storageKey := types.NewStorageKey(key)
-	ok, err := c.api.RPC.State.GetStorageLatest(storageKey, &raw)
+	ok, err := c.rpc().RPC.State.GetStorageLatest(storageKey, &raw)
 	if err != nil {
 		return nil, fmt.Errorf("get storage: %w", err)
 	}
@@ -31,7 +31,7 @@ func (c *Client) GetReferendumInfo(refID uint32) (*ReferendumInfo, error) {
 	}
 
 	// Try to decode the referendum data
-	info, err := decodeReferendumInfo(raw, refID, c.accountIDToSS58)
+	info, err := decodeReferendumInfo(raw, refID, c.accountIDToSS58, c.originDecoder(), c.tallyDecoder())
 	if err == nil {
 		return info, nil
 	}
@@ -69,7 +69,7 @@ func (c *Client) fetchHistoricalReferendum(refID uint32, variant uint8, blockNum
 		return nil, fmt.Errorf("decode block hash: %w", err)
 	}
 
-	ok, err := c.api.RPC.State.GetStorage(storageKey, &histRaw, hash)
+	ok, err := c.rpc().RPC.State.GetStorage(storageKey, &histRaw, hash)
 	if err != nil {
 		return nil, fmt.Errorf("get storage at block %d: %w", targetBlock, err)
 	}
@@ -79,7 +79,7 @@ func (c *Client) fetchHistoricalReferendum(refID uint32, variant uint8, blockNum
 	}
 
 	// Decode the historical data
-	histInfo, err := decodeReferendumInfo(histRaw, refID, c.accountIDToSS58)
+	histInfo, err := decodeReferendumInfo(histRaw, refID, c.accountIDToSS58, c.originDecoder(), c.tallyDecoder())
 	if err != nil {
 		// Try legacy format for very old referenda
 		histInfo, err = decodeLegacyReferendumInfo(histRaw, refID, c.accountIDToSS58)
@@ -111,9 +111,9 @@ func (c *Client) fetchHistoricalReferendum(refID uint32, variant uint8, blockNum
 }
 
 // createReferendumStorageKey creates the storage key for a referendum
-func createReferendumStorageKey(refID uint32) []byte {
-	// Referenda.ReferendumInfoFor storage key
-	palletHash := Twox128([]byte("Referenda"))
+func createReferendumStorageKey(pallet string, refID uint32) []byte {
+	// <pallet>.ReferendumInfoFor storage key
+	palletHash := Twox128([]byte(pallet))
 	storageHash := Twox128([]byte("ReferendumInfoFor"))
 
 	// Encode the referendum ID
@@ -132,39 +132,7 @@ func createReferendumStorageKey(refID uint32) []byte {
 
 // accountIDToSS58 converts an AccountID to SS58 format using chain's prefix
 func (c *Client) accountIDToSS58(accountID types.AccountID) string {
-	prefix := c.GetCachedSS58Prefix()
-
-	// Create the payload: prefix + accountID + checksum
-	payload := make([]byte, 0, 35)
-
-	// For prefix > 63, we need to encode it differently
-	if prefix < 64 {
-		payload = append(payload, byte(prefix))
-	} else {
-		// For larger prefixes, use the extended format
-		payload = append(payload, 0x40|((byte(prefix>>8))&0x3f))
-		payload = append(payload, byte(prefix&0xff))
-	}
-	payload = append(payload, accountID[:]...)
-
-	// Calculate SS58 checksum
-	checksumInput := []byte("SS58PRE")
-	// Add the correct prefix bytes for checksum
-	if prefix < 64 {
-		checksumInput = append(checksumInput, byte(prefix))
-	} else {
-		checksumInput = append(checksumInput, 0x40|((byte(prefix>>8))&0x3f))
-		checksumInput = append(checksumInput, byte(prefix&0xff))
-	}
-	checksumInput = append(checksumInput, accountID[:]...)
-
-	checksum := Blake2_256(checksumInput)
-
-	// Append first 2 bytes of checksum
-	payload = append(payload, checksum[0:2]...)
-
-	// Base58 encode
-	return base58.Encode(payload)
+	return accountIDToSS58WithPrefix(accountID, c.GetCachedSS58Prefix())
 }
 
 // Helper function for backwards compatibility
@@ -176,36 +144,29 @@ func accountIDToSS58(accountID types.AccountID) string {
 // accountIDToSS58WithPrefix converts with specific prefix
 func accountIDToSS58WithPrefix(accountID types.AccountID, prefix uint16) string {
 	// Create the payload: prefix + accountID + checksum
-	payload := make([]byte, 0, 35)
-
-	// For prefix > 63, we need to encode it differently
-	if prefix < 64 {
-		payload = append(payload, byte(prefix))
-	} else {
-		// For larger prefixes, use the extended format
-		payload = append(payload, 0x40|((byte(prefix>>8))&0x3f))
-		payload = append(payload, byte(prefix&0xff))
-	}
+	payload := ss58PrefixBytes(prefix)
 	payload = append(payload, accountID[:]...)
+	payload = append(payload, ss58Checksum(payload)...)
+
+	// Base58 encode
+	return base58.Encode(payload)
+}
 
-	// Calculate SS58 checksum
-	checksumInput := []byte("SS58PRE")
-	// Add the correct prefix bytes for checksum
+// ss58PrefixBytes encodes a network prefix: one byte below 64, otherwise the
+// two-byte form carrying 14 bits
+func ss58PrefixBytes(prefix uint16) []byte {
 	if prefix < 64 {
-		checksumInput = append(checksumInput, byte(prefix))
-	} else {
-		checksumInput = append(checksumInput, 0x40|((byte(prefix>>8))&0x3f))
-		checksumInput = append(checksumInput, byte(prefix&0xff))
+		return []byte{byte(prefix)}
 	}
-	checksumInput = append(checksumInput, accountID[:]...)
-
-	checksum := Blake2_256(checksumInput)
-
-	// Append first 2 bytes of checksum
-	payload = append(payload, checksum[0:2]...)
+	return []byte{
+		0x40 | byte((prefix&0xfc)>>2),
+		byte(prefix>>8) | byte((prefix&0x03)<<6),
+	}
+}
 
-	// Base58 encode
-	return base58.Encode(payload)
+// ss58Checksum returns the two checksum bytes of an SS58 prefix and account
+func ss58Checksum(body []byte) []byte {
+	return Blake2_512(append([]byte("SS58PRE"), body...))[:2]
 }
 
 // decodeLegacyReferendumInfo decodes very old referendum formats
@@ -396,8 +357,9 @@ func decodeFinishedReferendum(decoder *scale.Decoder, status string, encodeAccou
 }
 
 // decodeReferendumInfo decodes referendum data based on the structure from the documentation
-func decodeReferendumInfo(data []byte, refID uint32, encodeAccount func(types.AccountID) string) (*ReferendumInfo, error) {
-	decoder := scale.NewDecoder(bytes.NewReader(data))
+func decodeReferendumInfo(data []byte, refID uint32, encodeAccount func(types.AccountID) string, decodeOrigin originDecodeFunc, decodeTally tallyDecodeFunc) (*ReferendumInfo, error) {
+	reader := bytes.NewReader(data)
+	decoder := scale.NewDecoder(reader)
 
 	// Read variant
 	variant, err := decoder.ReadOneByte()
@@ -407,7 +369,7 @@ func decodeReferendumInfo(data []byte, refID uint32, encodeAccount func(types.Ac
 
 	switch variant {
 	case 0: // Ongoing
-		return decodeOngoingReferendum(decoder, refID, encodeAccount)
+		return decodeOngoingReferendum(reader, refID, encodeAccount, decodeOrigin, decodeTally)
 	case 1: // Approved
 		return decodeFinishedReferendum(decoder, "Approved", encodeAccount)
 	case 2: // Rejected
@@ -439,7 +401,8 @@ func decodeReferendumInfo(data []byte, refID uint32, encodeAccount func(types.Ac
 }
 
 // decodeOngoingReferendum decodes the complex Ongoing referendum state
-func decodeOngoingReferendum(decoder *scale.Decoder, refID uint32, encodeAccount func(types.AccountID) string) (*ReferendumInfo, error) {
+func decodeOngoingReferendum(reader *bytes.Reader, refID uint32, encodeAccount func(types.AccountID) string, decodeOrigin originDecodeFunc, decodeTally tallyDecodeFunc) (*ReferendumInfo, error) {
+	decoder := scale.NewDecoder(reader)
 	info := &ReferendumInfo{Status: "Ongoing"}
 
 	if encodeAccount == nil {
@@ -455,8 +418,8 @@ func decodeOngoingReferendum(decoder *scale.Decoder, refID uint32, encodeAccount
 	}
 	info.Track = track
 
-	// Origin
-	origin, err := decodeOrigin(decoder)
+	// Origin - PalletsOriginOf<T>
+	origin, err := decodeOrigin(reader)
 	if err != nil {
 		return nil, fmt.Errorf("decode origin: %w", err)
 	}
@@ -584,6 +547,7 @@ func decodeProposal(decoder *scale.Decoder, info *ReferendumInfo, refID uint32)
 		hash := Blake2_256(callData)
 		info.Proposal = codec.HexEncodeToString(hash)
 		info.ProposalLen = uint32(len(callData))
+		info.ProposalInline = callData
 
 	case 3, 4, 5, 6, 7: // Various legacy formats with just hash
 		var hash types.Hash
@@ -761,6 +725,9 @@ func decodeDeciding(decoder *scale.Decoder, decision **DecisionStatus) error {
 	return nil
 }
 
+// tallyDecodeFunc decodes the pallet's T::Tally
+type tallyDecodeFunc func(decoder *scale.Decoder, tally *Tally) error
+
 // decodeTally decodes the tally information
 func decodeTally(decoder *scale.Decoder, tally *Tally) error {
 	// Try to decode ayes
@@ -819,17 +786,48 @@ func decodeTally(decoder *scale.Decoder, tally *Tally) error {
 	return nil
 }
 
+// decodeRankedTally decodes a pallet_ranked_collective tally: bare_ayes
+// (members voting aye), then rank-weighted ayes and nays, each a u32.
+// Support is the bare aye count, measured against the member count.
+func decodeRankedTally(decoder *scale.Decoder, tally *Tally) error {
+	var bareAyes, ayes, nays uint32
+	if err := decoder.Decode(&bareAyes); err != nil {
+		return fmt.Errorf("decode bare ayes: %w", err)
+	}
+	if err := decoder.Decode(&ayes); err != nil {
+		return fmt.Errorf("decode ayes: %w", err)
+	}
+	if err := decoder.Decode(&nays); err != nil {
+		return fmt.Errorf("decode nays: %w", err)
+	}
+
+	tally.Ranked = true
+	tally.BareAyes = fmt.Sprintf("%d", bareAyes)
+	tally.Ayes = fmt.Sprintf("%d", ayes)
+	tally.Nays = fmt.Sprintf("%d", nays)
+	tally.Support = tally.BareAyes
+
+	if total := uint64(ayes) + uint64(nays); total > 0 {
+		approval := uint64(ayes) * 10000 / total
+		tally.Approval = fmt.Sprintf("%d.%02d%%", approval/100, approval%100)
+	} else {
+		tally.Approval = "0%"
+	}
+
+	return nil
+}
+
 // GetReferendumCount gets the total number of referenda
 func (c *Client) GetReferendumCount() (uint32, error) {
-	// Create storage key for Referenda.ReferendumCount
-	palletHash := Twox128([]byte("Referenda"))
+	// Create storage key for <pallet>.ReferendumCount
+	palletHash := Twox128([]byte(c.ReferendaPallet()))
 	storageHash := Twox128([]byte("ReferendumCount"))
 	key := append(palletHash, storageHash...)
 
 	storageKey := types.NewStorageKey(key)
 
 	var count types.U32
-	ok, err := c.api.RPC.State.GetStorageLatest(storageKey, &count)
+	ok, err := c.rpc().RPC.State.GetStorageLatest(storageKey, &count)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,90 @@
+package polkadot
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	secp256k1 "github.com/ethereum/go-ethereum/crypto"
+	"github.com/vedhavyas/go-subkey/v2/sr25519"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Signature schemes of Substrate accounts
+const (
+	SchemeSr25519 = "sr25519"
+	SchemeEd25519 = "ed25519"
+	SchemeEcdsa   = "ecdsa"
+)
+
+// VerifySignature checks that signature was made over message by the key
+// behind an SS58 address and returns the scheme that verified it. Wallet
+// extensions sign raw payloads wrapped in <Bytes>...</Bytes>, so both the
+// bare and the wrapped message are accepted, as are signatures encoded as a
+// MultiSignature with a leading scheme byte.
+func VerifySignature(address string, message, signature []byte) (string, error) {
+	accountID, err := SS58ToAccountID(address)
+	if err != nil {
+		return "", err
+	}
+
+	candidates := [][]byte{
+		message,
+		append(append([]byte("<Bytes>"), message...), []byte("</Bytes>")...),
+	}
+
+	if scheme, ok := verifyRawSignature(accountID, candidates, signature); ok {
+		return scheme, nil
+	}
+	if n := len(signature); (n == 65 && signature[0] <= 1) || (n == 66 && signature[0] == 2) {
+		if scheme, ok := verifyRawSignature(accountID, candidates, signature[1:]); ok {
+			return scheme, nil
+		}
+	}
+
+	if n := len(signature); n < 64 || n > 66 {
+		return "", fmt.Errorf("unexpected signature length %d", n)
+	}
+	return "", fmt.Errorf("signature does not match %s", address)
+}
+
+// verifyRawSignature verifies a bare 64-byte sr25519/ed25519 or 65-byte ecdsa
+// signature over any of the candidate messages
+func verifyRawSignature(accountID types.AccountID, candidates [][]byte, signature []byte) (string, bool) {
+	switch len(signature) {
+	case 64:
+		// sr25519 and ed25519 accounts are their public key
+		if pub, err := (sr25519.Scheme{}).FromPublicKey(accountID[:]); err == nil {
+			for _, msg := range candidates {
+				if pub.Verify(msg, signature) {
+					return SchemeSr25519, true
+				}
+			}
+		}
+		for _, msg := range candidates {
+			if ed25519.Verify(ed25519.PublicKey(accountID[:]), msg, signature) {
+				return SchemeEd25519, true
+			}
+		}
+	case 65:
+		// ecdsa accounts are the blake2_256 hash of the compressed public
+		// key, which is recovered from the signature
+		sig := append([]byte(nil), signature...)
+		if sig[64] >= 27 {
+			sig[64] -= 27
+		}
+		for _, msg := range candidates {
+			digest := blake2b.Sum256(msg)
+			pub, err := secp256k1.SigToPub(digest[:], sig)
+			if err != nil {
+				continue
+			}
+			signer := blake2b.Sum256(secp256k1.CompressPubkey(pub))
+			if bytes.Equal(signer[:], accountID[:]) {
+				return SchemeEcdsa, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,59 @@
+package polkadot
+
+import (
+	"strings"
+	"testing"
+)
+
+// Signatures over linkMessage by the //Alice dev keys. sr25519 and ecdsa
+// wallets sign the <Bytes>-wrapped message, the ed25519 key the bare one.
+const (
+	linkMessage = "Link Discord user 123456789012345678 to this address"
+
+	aliceSr25519    = "15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5"
+	aliceSr25519Sig = "6e8daa53ed23dbbd8c5b452f832040afa2ebc95e35a4bbe51cc868c474061d3c6165b45ea812d0fb16906e67051330b16e6bdb5778d60800f0192e695d0cf088"
+	aliceEd25519    = "146SvjUZXoMaemdeiecyxgALeYMm8ZWh1yrGo8RtpoPfe7WL"
+	aliceEd25519Sig = "bc16d1282f235862b394a8036fae2e5789338276122cf8616a13edf288078a910ec6d76108a2611782fd6cb0eb6ee78825afc89367fcbcdb7a61fa4f159d6907"
+	ecdsaAccount    = "14JTx8FU2dpdiv6TRCAUdZb2Dcypwe2pHy2YXg3vzxYcqvtp"
+	ecdsaSig        = "d65000a29adc0432eb8851908af08f83ce69854143c8a540ea432544f1fd2b9a6821694ded626c97cc3b8b6eada3f100980b3accf5a5ee3a4a0606ec72b7362900"
+)
+
+func TestVerifySignature(t *testing.T) {
+	for name, tc := range map[string]struct {
+		address, signature, scheme string
+	}{
+		"sr25519 over wrapped message":    {aliceSr25519, aliceSr25519Sig, SchemeSr25519},
+		"sr25519 as MultiSignature":       {aliceSr25519, "01" + aliceSr25519Sig, SchemeSr25519},
+		"ed25519 over bare message":       {aliceEd25519, aliceEd25519Sig, SchemeEd25519},
+		"ed25519 as MultiSignature":       {aliceEd25519, "00" + aliceEd25519Sig, SchemeEd25519},
+		"ecdsa":                           {ecdsaAccount, ecdsaSig, SchemeEcdsa},
+		"ecdsa with Ethereum recovery ID": {ecdsaAccount, ecdsaSig[:128] + "1b", SchemeEcdsa},
+		"ecdsa as MultiSignature":         {ecdsaAccount, "02" + ecdsaSig, SchemeEcdsa},
+	} {
+		scheme, err := VerifySignature(tc.address, []byte(linkMessage), mustDecodeHex(t, tc.signature))
+		if err != nil || scheme != tc.scheme {
+			t.Errorf("%s: VerifySignature = %s, %v; want %s", name, scheme, err, tc.scheme)
+		}
+	}
+}
+
+func TestVerifySignatureRejectsMismatches(t *testing.T) {
+	if _, err := VerifySignature(aliceSr25519, []byte(linkMessage+"."), mustDecodeHex(t, aliceSr25519Sig)); err == nil {
+		t.Error("accepted a signature over another message")
+	}
+	if _, err := VerifySignature(aliceEd25519, []byte(linkMessage), mustDecodeHex(t, aliceSr25519Sig)); err == nil {
+		t.Error("accepted another address's sr25519 signature")
+	}
+	if _, err := VerifySignature(aliceSr25519, []byte(linkMessage), mustDecodeHex(t, ecdsaSig)); err == nil {
+		t.Error("accepted an ecdsa signature of another account")
+	}
+
+	_, err := VerifySignature(aliceSr25519, []byte(linkMessage), mustDecodeHex(t, aliceSr25519Sig[:120]))
+	if err == nil || !strings.Contains(err.Error(), "unexpected signature length 60") {
+		t.Errorf("short signature: error = %v", err)
+	}
+	_, err = VerifySignature("not-an-address", []byte(linkMessage), mustDecodeHex(t, aliceSr25519Sig))
+	if err == nil || !strings.Contains(err.Error(), "invalid SS58 address") {
+		t.Errorf("invalid address: error = %v", err)
+	}
+}
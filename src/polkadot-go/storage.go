@@ -83,6 +83,12 @@ func Blake2_256(data []byte) []byte {
 	return h.Sum(nil)
 }
 
+// Blake2_512 implements Blake2b 512-bit hash
+func Blake2_512(data []byte) []byte {
+	sum := blake2b.Sum512(data)
+	return sum[:]
+}
+
 // HexEncode encodes bytes to hex string without 0x prefix
 func HexEncode(data []byte) string {
 	return hex.EncodeToString(data)
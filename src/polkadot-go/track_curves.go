@@ -0,0 +1,394 @@
+package polkadot
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+// Curve variants of pallet_referenda::Curve
+const (
+	CurveLinearDecreasing  = "LinearDecreasing"
+	CurveSteppedDecreasing = "SteppedDecreasing"
+	CurveReciprocal        = "Reciprocal"
+)
+
+const (
+	perbillUnit = 1_000_000_000
+	fixedUnit   = 1_000_000_000 // FixedI64 accuracy
+)
+
+// Curve is a track's approval or support threshold over the decision period.
+// Perbill and FixedI64 parameters are stored as fractions (1.0 = 100%).
+type Curve struct {
+	Kind string
+	// LinearDecreasing
+	Length float64
+	Floor  float64
+	Ceil   float64
+	// SteppedDecreasing
+	Begin  float64
+	End    float64
+	Step   float64
+	Period float64
+	// Reciprocal
+	Factor  float64
+	XOffset float64
+	YOffset float64
+}
+
+// parseCurve decodes a SCALE Curve enum, returning the bytes consumed
+func parseCurve(data []byte) (*Curve, int) {
+	if len(data) == 0 {
+		return nil, 0
+	}
+
+	perbill := func(offset int) float64 {
+		return float64(binary.LittleEndian.Uint32(data[offset:])) / perbillUnit
+	}
+	fixed := func(offset int) float64 {
+		return float64(int64(binary.LittleEndian.Uint64(data[offset:]))) / fixedUnit
+	}
+
+	switch data[0] {
+	case 0: // LinearDecreasing { length, floor, ceil: Perbill }
+		if len(data) < 1+12 {
+			return nil, len(data)
+		}
+		return &Curve{Kind: CurveLinearDecreasing, Length: perbill(1), Floor: perbill(5), Ceil: perbill(9)}, 13
+	case 1: // SteppedDecreasing { begin, end, step, period: Perbill }
+		if len(data) < 1+16 {
+			return nil, len(data)
+		}
+		return &Curve{Kind: CurveSteppedDecreasing, Begin: perbill(1), End: perbill(5), Step: perbill(9), Period: perbill(13)}, 17
+	case 2: // Reciprocal { factor, x_offset, y_offset: FixedI64 }
+		if len(data) < 1+24 {
+			return nil, len(data)
+		}
+		return &Curve{Kind: CurveReciprocal, Factor: fixed(1), XOffset: fixed(9), YOffset: fixed(17)}, 25
+	default:
+		return nil, 1
+	}
+}
+
+// describe renders the curve parameters for TrackInfo.MinApproval/MinSupport
+func (c *Curve) describe() interface{} {
+	if c == nil {
+		return nil
+	}
+	pct := func(v float64) string { return fmt.Sprintf("%.2f%%", v*100) }
+
+	switch c.Kind {
+	case CurveLinearDecreasing:
+		return map[string]interface{}{c.Kind: map[string]interface{}{
+			"length": pct(c.Length), "floor": pct(c.Floor), "ceil": pct(c.Ceil),
+		}}
+	case CurveSteppedDecreasing:
+		return map[string]interface{}{c.Kind: map[string]interface{}{
+			"begin": pct(c.Begin), "end": pct(c.End), "step": pct(c.Step), "period": pct(c.Period),
+		}}
+	default:
+		return map[string]interface{}{c.Kind: map[string]interface{}{
+			"factor": c.Factor, "xOffset": c.XOffset, "yOffset": c.YOffset,
+		}}
+	}
+}
+
+// Threshold returns the required fraction at x, the elapsed fraction of the
+// decision period, mirroring Curve::threshold in pallet_referenda
+func (c *Curve) Threshold(x float64) float64 {
+	x = clampUnit(x)
+
+	switch c.Kind {
+	case CurveLinearDecreasing:
+		if c.Length <= 0 {
+			return c.Floor
+		}
+		return c.Ceil - math.Min(x, c.Length)/c.Length*(c.Ceil-c.Floor)
+	case CurveSteppedDecreasing:
+		if c.Period <= 0 {
+			return c.Begin
+		}
+		steps := math.Floor(x / c.Period)
+		return math.Max(c.Begin-steps*c.Step, c.End)
+	case CurveReciprocal:
+		denominator := x + c.XOffset
+		if denominator <= 0 {
+			return 1
+		}
+		return clampUnit(c.Factor/denominator + c.YOffset)
+	default:
+		return 1
+	}
+}
+
+func clampUnit(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// RequiredAt returns the approval and support a referendum on this track needs
+// elapsed blocks into its decision period
+func (t *TrackInfo) RequiredAt(elapsed uint32) (approval, support float64, err error) {
+	if t.ApprovalCurve == nil || t.SupportCurve == nil {
+		return 0, 0, fmt.Errorf("track %s has no decoded curves", t.Name)
+	}
+	if t.DecisionPeriod == 0 {
+		return 0, 0, fmt.Errorf("track %s has no decision period", t.Name)
+	}
+	if elapsed > t.DecisionPeriod {
+		elapsed = t.DecisionPeriod
+	}
+	x := float64(elapsed) / float64(t.DecisionPeriod)
+	return t.ApprovalCurve.Threshold(x), t.SupportCurve.Threshold(x), nil
+}
+
+// PassProjection evaluates a referendum's tally against its track curves and
+// projects when it would pass if the tally stayed frozen
+type PassProjection struct {
+	Track            string  `json:"track"`
+	Phase            string  `json:"phase"` // Preparing, Deciding, Confirming
+	EvaluatedAt      uint64  `json:"evaluatedAt"`
+	DecisionSince    uint64  `json:"decisionSince,omitempty"`
+	DecisionEnds     uint64  `json:"decisionEnds,omitempty"`
+	Approval         float64 `json:"approval"`
+	Support          float64 `json:"support"`
+	RequiredApproval float64 `json:"requiredApproval"`
+	RequiredSupport  float64 `json:"requiredSupport"`
+	Passing          bool    `json:"passing"`
+	WillPass         bool    `json:"willPass"`
+	PassingFrom      uint64  `json:"passingFrom,omitempty"` // first block both thresholds are met
+	ApprovedBy       uint64  `json:"approvedBy,omitempty"`  // block confirmation would complete
+	BlockTimeMs      uint32  `json:"blockTimeMs,omitempty"` // the chain's expected block time
+	Note             string  `json:"note,omitempty"`
+}
+
+// ProjectPassing evaluates an ongoing referendum at block now. electorate is
+// the active issuance that support is measured against, and blockTime the
+// chain's expected block time used to turn blocks into durations.
+func ProjectPassing(track *TrackInfo, info *ReferendumInfo, electorate *big.Int, now uint32, blockTime time.Duration) (*PassProjection, error) {
+	if info == nil || info.Status != "Ongoing" {
+		return nil, fmt.Errorf("referendum is not ongoing")
+	}
+	if track == nil {
+		return nil, fmt.Errorf("track info required")
+	}
+
+	p := &PassProjection{
+		Track:       track.Name,
+		EvaluatedAt: uint64(now),
+		BlockTimeMs: uint32(blockTime.Milliseconds()),
+	}
+
+	ayes, _ := new(big.Int).SetString(orZero(info.Tally.Ayes), 10)
+	nays, _ := new(big.Int).SetString(orZero(info.Tally.Nays), 10)
+	support, _ := new(big.Int).SetString(orZero(info.Tally.Support), 10)
+	if ayes == nil || nays == nil || support == nil {
+		return nil, fmt.Errorf("invalid tally")
+	}
+	p.Approval = ratio(ayes, new(big.Int).Add(ayes, nays))
+	p.Support = ratio(support, electorate)
+
+	var since uint32
+	switch {
+	case info.Decision == nil:
+		p.Phase = "Preparing"
+		// Deciding starts once the prepare period is over and a decision
+		// deposit is placed; assume the earliest possible start
+		since = info.Submitted + track.PreparePeriod
+		if since < now {
+			since = now
+		}
+		if info.DecisionDeposit == nil {
+			p.Note = "awaiting decision deposit"
+		} else if info.InQueue {
+			p.Note = "queued for a deciding slot"
+		}
+	case info.Decision.Confirming != nil:
+		p.Phase = "Confirming"
+		since = info.Decision.Since
+	default:
+		p.Phase = "Deciding"
+		since = info.Decision.Since
+	}
+	p.DecisionSince = uint64(since)
+	p.DecisionEnds = uint64(since) + uint64(track.DecisionPeriod)
+
+	var elapsed uint32
+	if now > since {
+		elapsed = now - since
+	}
+	requiredApproval, requiredSupport, err := track.RequiredAt(elapsed)
+	if err != nil {
+		return nil, err
+	}
+	p.RequiredApproval = requiredApproval
+	p.RequiredSupport = requiredSupport
+
+	meets := func(at uint32) bool {
+		a, s, _ := track.RequiredAt(at)
+		return p.Approval >= a && p.Support >= s
+	}
+	p.Passing = meets(elapsed)
+
+	// Thresholds only fall over time, so the first passing block can be found
+	// by bisection between now and the end of the decision period
+	var passAt uint32
+	switch {
+	case p.Passing:
+		passAt = elapsed
+		p.WillPass = true
+	case elapsed < track.DecisionPeriod && meets(track.DecisionPeriod):
+		lo, hi := elapsed, track.DecisionPeriod
+		for lo+1 < hi {
+			mid := lo + (hi-lo)/2
+			if meets(mid) {
+				hi = mid
+			} else {
+				lo = mid
+			}
+		}
+		passAt = hi
+		p.WillPass = true
+	}
+
+	if p.WillPass {
+		p.PassingFrom = uint64(since) + uint64(passAt)
+		if p.Phase == "Confirming" && p.Passing {
+			p.ApprovedBy = uint64(*info.Decision.Confirming)
+		} else {
+			p.ApprovedBy = p.PassingFrom + uint64(track.ConfirmPeriod)
+		}
+	}
+
+	return p, nil
+}
+
+// GetElectorate returns the active issuance (total minus inactive), which
+// support is measured against
+func (c *Client) GetElectorate() (*big.Int, error) {
+	total, err := c.getU128Storage("Balances", "TotalIssuance")
+	if err != nil {
+		return nil, fmt.Errorf("get total issuance: %w", err)
+	}
+	inactive, err := c.getU128Storage("Balances", "InactiveIssuance")
+	if err != nil {
+		inactive = new(big.Int)
+	}
+	if inactive.Cmp(total) < 0 {
+		total.Sub(total, inactive)
+	}
+	return total, nil
+}
+
+func (c *Client) getU128Storage(pallet, item string) (*big.Int, error) {
+	value, err := c.GetStorage(StorageKey(pallet, item), nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := DecodeHex(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 16 {
+		return nil, fmt.Errorf("%s.%s not set", pallet, item)
+	}
+	return leToBigInt(data[:16]), nil
+}
+
+// ProjectReferendum evaluates an ongoing referendum against its track curves
+func (c *Client) ProjectReferendum(info *ReferendumInfo, now uint32) (*PassProjection, error) {
+	if info.Tally.Ranked {
+		// Support is measured against the member count of the class's
+		// minimum rank, which is runtime configuration not in metadata
+		return nil, fmt.Errorf("projection not supported for ranked tallies")
+	}
+	track, err := c.GetTrackInfo(info.Track)
+	if err != nil {
+		return nil, err
+	}
+	electorate, err := c.GetElectorate()
+	if err != nil {
+		return nil, err
+	}
+	return ProjectPassing(track, info, electorate, now, c.ExpectedBlockTime())
+}
+
+// ParsePassProjection decodes a persisted projection
+func ParsePassProjection(data []byte) (*PassProjection, error) {
+	var p PassProjection
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse pass projection: %w", err)
+	}
+	return &p, nil
+}
+
+// Lines renders the projection for Discord, reports and prompts
+func (p *PassProjection) Lines() []string {
+	if p == nil {
+		return nil
+	}
+
+	lines := []string{
+		fmt.Sprintf("Track: %s (%s)", p.Track, p.Phase),
+		fmt.Sprintf("Approval: %.2f%% (needs %.2f%%)", p.Approval*100, p.RequiredApproval*100),
+		fmt.Sprintf("Support: %.4f%% (needs %.4f%%)", p.Support*100, p.RequiredSupport*100),
+	}
+
+	switch {
+	case p.Passing && p.Phase == "Confirming":
+		lines = append(lines, fmt.Sprintf("Passing and confirming; approved at block #%d (%s) if the tally holds",
+			p.ApprovedBy, p.blocksFromNow(p.ApprovedBy)))
+	case p.Passing:
+		lines = append(lines, fmt.Sprintf("Passing now; approved at block #%d (%s) if the tally holds",
+			p.ApprovedBy, p.blocksFromNow(p.ApprovedBy)))
+	case p.WillPass:
+		lines = append(lines, fmt.Sprintf("Not passing yet; with a frozen tally thresholds are met at block #%d (%s), approval at #%d",
+			p.PassingFrom, p.blocksFromNow(p.PassingFrom), p.ApprovedBy))
+	default:
+		lines = append(lines, fmt.Sprintf("Will not pass with the current tally before the decision period ends at block #%d (%s)",
+			p.DecisionEnds, p.blocksFromNow(p.DecisionEnds)))
+	}
+
+	if p.Note != "" {
+		lines = append(lines, "Note: "+p.Note)
+	}
+	lines = append(lines, fmt.Sprintf("Evaluated at block #%d", p.EvaluatedAt))
+	return lines
+}
+
+// blocksFromNow renders a block as an approximate duration from evaluation
+func (p *PassProjection) blocksFromNow(block uint64) string {
+	if block <= p.EvaluatedAt {
+		return "now"
+	}
+	blockTime := DefaultBlockTime
+	if p.BlockTimeMs > 0 {
+		blockTime = time.Duration(p.BlockTimeMs) * time.Millisecond
+	}
+	d := time.Duration(block-p.EvaluatedAt) * blockTime
+	if d >= 48*time.Hour {
+		return fmt.Sprintf("~%d days", int(d.Hours()/24))
+	}
+	if d >= 2*time.Hour {
+		return fmt.Sprintf("~%d hours", int(d.Hours()))
+	}
+	return fmt.Sprintf("~%d minutes", int(d.Minutes()))
+}
+
+func ratio(num, den *big.Int) float64 {
+	if den == nil || den.Sign() == 0 {
+		return 0
+	}
+	r, _ := new(big.Rat).SetFrac(num, den).Float64()
+	return r
+}
+
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
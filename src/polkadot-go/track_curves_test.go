@@ -0,0 +1,217 @@
+package polkadot
+
+import (
+	"encoding/hex"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// polkadotRootTrack returns the Polkadot Root track, with its curves decoded
+// from their SCALE encoding in the runtime's Referenda.Tracks constant
+func polkadotRootTrack(t *testing.T) *TrackInfo {
+	t.Helper()
+	// Reciprocal { factor: 0.222222224, x_offset: 0.333333335, y_offset: 0.333333332 }
+	approval := decodeTestCurve(t, "0290d73e0d000000005743de13000000005443de1300000000")
+	// LinearDecreasing { length: 100%, floor: 0%, ceil: 50% }
+	support := decodeTestCurve(t, "0000ca9a3b000000000065cd1d")
+	return &TrackInfo{
+		Name:           "root",
+		PreparePeriod:  1200,
+		DecisionPeriod: 403200,
+		ConfirmPeriod:  14400,
+		ApprovalCurve:  approval,
+		SupportCurve:   support,
+	}
+}
+
+func decodeTestCurve(t *testing.T, encoded string) *Curve {
+	t.Helper()
+	data, err := hex.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("decode hex: %v", err)
+	}
+	curve, n := parseCurve(data)
+	if curve == nil || n != len(data) {
+		t.Fatalf("parseCurve(%s) = %v, %d bytes", encoded, curve, n)
+	}
+	return curve
+}
+
+func TestCurveThreshold(t *testing.T) {
+	root := polkadotRootTrack(t)
+	// 100% falling by 10% every tenth of the decision period down to 50%
+	stepped := &Curve{Kind: CurveSteppedDecreasing, Begin: 1, End: 0.5, Step: 0.1, Period: 0.1}
+	// LinearDecreasing reaching its floor halfway through the period
+	halfLinear := &Curve{Kind: CurveLinearDecreasing, Length: 0.5, Floor: 0.1, Ceil: 0.9}
+
+	tests := []struct {
+		name  string
+		curve *Curve
+		x     float64
+		want  float64
+	}{
+		{name: "reciprocal start", curve: root.ApprovalCurve, x: 0, want: 1},
+		{name: "reciprocal midpoint", curve: root.ApprovalCurve, x: 0.5, want: 0.6},
+		{name: "reciprocal end", curve: root.ApprovalCurve, x: 1, want: 0.5},
+		{name: "reciprocal past end", curve: root.ApprovalCurve, x: 2, want: 0.5},
+		{name: "linear start", curve: root.SupportCurve, x: 0, want: 0.5},
+		{name: "linear midpoint", curve: root.SupportCurve, x: 0.5, want: 0.25},
+		{name: "linear end", curve: root.SupportCurve, x: 1, want: 0},
+		{name: "linear before length", curve: halfLinear, x: 0.25, want: 0.5},
+		{name: "linear after length", curve: halfLinear, x: 0.75, want: 0.1},
+		{name: "stepped start", curve: stepped, x: 0, want: 1},
+		{name: "stepped within first step", curve: stepped, x: 0.09, want: 1},
+		{name: "stepped third step", curve: stepped, x: 0.25, want: 0.8},
+		{name: "stepped floor", curve: stepped, x: 0.9, want: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.curve.Threshold(tt.x); math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("Threshold(%v) = %v, want %v", tt.x, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectPassing(t *testing.T) {
+	track := polkadotRootTrack(t)
+	electorate := big.NewInt(1000)
+	confirming := uint32(150000)
+
+	tests := []struct {
+		name string
+		info *ReferendumInfo
+		now  uint32
+		// expectations; PassingFrom is checked to within a block since the
+		// bisection lands on a floating-point threshold crossing
+		phase       string
+		passing     bool
+		willPass    bool
+		passingFrom uint64
+		approvedBy  uint64
+		note        string
+		err         string
+	}{
+		{
+			name: "passing while deciding",
+			info: &ReferendumInfo{
+				Status:   "Ongoing",
+				Decision: &DecisionStatus{Since: 100000},
+				Tally:    Tally{Ayes: "900", Nays: "0", Support: "600"},
+			},
+			now:         110000,
+			phase:       "Deciding",
+			passing:     true,
+			willPass:    true,
+			passingFrom: 110000,
+			approvedBy:  110000 + 14400,
+		},
+		{
+			// 75% approval is met a fifth of the way in, 10% support only
+			// four fifths of the way in
+			name: "passes later with a frozen tally",
+			info: &ReferendumInfo{
+				Status:   "Ongoing",
+				Decision: &DecisionStatus{Since: 100000},
+				Tally:    Tally{Ayes: "300", Nays: "100", Support: "100"},
+			},
+			now:         100000,
+			phase:       "Deciding",
+			willPass:    true,
+			passingFrom: 100000 + 322560,
+			approvedBy:  100000 + 322560 + 14400,
+		},
+		{
+			name: "confirming keeps its confirmation block",
+			info: &ReferendumInfo{
+				Status:   "Ongoing",
+				Decision: &DecisionStatus{Since: 100000, Confirming: &confirming},
+				Tally:    Tally{Ayes: "900", Nays: "0", Support: "600"},
+			},
+			now:         140000,
+			phase:       "Confirming",
+			passing:     true,
+			willPass:    true,
+			passingFrom: 140000,
+			approvedBy:  150000,
+		},
+		{
+			name: "approval below the curve floor",
+			info: &ReferendumInfo{
+				Status:   "Ongoing",
+				Decision: &DecisionStatus{Since: 100000},
+				Tally:    Tally{Ayes: "100", Nays: "300", Support: "900"},
+			},
+			now:   100000,
+			phase: "Deciding",
+		},
+		{
+			name: "preparing without a decision deposit",
+			info: &ReferendumInfo{
+				Status:    "Ongoing",
+				Submitted: 100000,
+				Tally:     Tally{Ayes: "0", Nays: "0", Support: "0"},
+			},
+			now:   100000,
+			phase: "Preparing",
+			note:  "awaiting decision deposit",
+		},
+		{
+			name: "not ongoing",
+			info: &ReferendumInfo{Status: "Approved"},
+			err:  "referendum is not ongoing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ProjectPassing(track, tt.info, electorate, tt.now, 6*time.Second)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("ProjectPassing() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProjectPassing() error = %v", err)
+			}
+			if p.Phase != tt.phase || p.Passing != tt.passing || p.WillPass != tt.willPass || p.Note != tt.note {
+				t.Errorf("got phase %q passing %v willPass %v note %q, want %q %v %v %q",
+					p.Phase, p.Passing, p.WillPass, p.Note, tt.phase, tt.passing, tt.willPass, tt.note)
+			}
+			if diff := int64(p.PassingFrom) - int64(tt.passingFrom); diff < -1 || diff > 1 {
+				t.Errorf("PassingFrom = %d, want %d", p.PassingFrom, tt.passingFrom)
+			}
+			if diff := int64(p.ApprovedBy) - int64(tt.approvedBy); diff < -1 || diff > 1 {
+				t.Errorf("ApprovedBy = %d, want %d", p.ApprovedBy, tt.approvedBy)
+			}
+		})
+	}
+}
+
+func TestPassProjectionBlocksFromNow(t *testing.T) {
+	tests := []struct {
+		name        string
+		blockTimeMs uint32
+		block       uint64
+		want        string
+	}{
+		{name: "six second blocks", blockTimeMs: 6000, block: 1600, want: "~60 minutes"},
+		{name: "twelve second blocks", blockTimeMs: 12000, block: 1600, want: "~2 hours"},
+		{name: "unknown block time", block: 1600, want: "~60 minutes"},
+		{name: "days", blockTimeMs: 12000, block: 100000, want: "~13 days"},
+		{name: "past", blockTimeMs: 6000, block: 900, want: "now"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &PassProjection{EvaluatedAt: 1000, BlockTimeMs: tt.blockTimeMs}
+			if got := p.blocksFromNow(tt.block); got != tt.want {
+				t.Errorf("blocksFromNow(%d) = %q, want %q", tt.block, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package polkadot
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+)
+
+// Treasury spend payment states as stored in Treasury.Spends
+const (
+	SpendStatePending   = "Pending"
+	SpendStateAttempted = "Attempted"
+	SpendStateFailed    = "Failed"
+)
+
+// TreasurySpend is an entry of Treasury.Spends, created by Treasury.spend.
+// AssetKind and Beneficiary keep the decoded location form so they can be
+// compared with the arguments of the call that requested the spend.
+type TreasurySpend struct {
+	Index       uint32
+	AssetKind   interface{}
+	Amount      string
+	Beneficiary interface{}
+	ValidFrom   uint32
+	ExpireAt    uint32
+	State       string  // Pending, Attempted or Failed
+	PaymentID   *uint64 // set once a payout was attempted
+}
+
+// TreasuryProposal is a Treasury.Proposals entry, created by spend_local and
+// paid from the treasury account at the next spend period
+type TreasuryProposal struct {
+	Index       uint32
+	Beneficiary string
+	Value       string
+	Approved    bool // listed in Treasury.Approvals
+}
+
+// SpendRequest is a treasury spend requested by a proposal call
+type SpendRequest struct {
+	Call        string // spend or spend_local
+	AssetKind   interface{}
+	Amount      string
+	Beneficiary interface{}
+}
+
+// GetTreasurySpends reads every entry of Treasury.Spends
+func (c *Client) GetTreasurySpends() ([]TreasurySpend, error) {
+	var spends []TreasurySpend
+	err := c.readStorageMap("Treasury", "Spends", func(index uint32, value interface{}) {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		spend := TreasurySpend{
+			Index:       index,
+			AssetKind:   fields["asset_kind"],
+			Beneficiary: fields["beneficiary"],
+		}
+		spend.Amount, _ = balanceString(fields["amount"])
+		if v, ok := fields["valid_from"].(uint64); ok {
+			spend.ValidFrom = uint32(v)
+		}
+		if v, ok := fields["expire_at"].(uint64); ok {
+			spend.ExpireAt = uint32(v)
+		}
+
+		switch status := fields["status"].(type) {
+		case string:
+			spend.State = status
+		case map[string]interface{}:
+			if attempted, ok := status[SpendStateAttempted].(map[string]interface{}); ok {
+				spend.State = SpendStateAttempted
+				if id, ok := attempted["id"].(uint64); ok {
+					spend.PaymentID = &id
+				}
+			}
+		}
+		spends = append(spends, spend)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return spends, nil
+}
+
+// GetTreasuryProposals reads Treasury.Proposals and marks those awaiting
+// payout in Treasury.Approvals
+func (c *Client) GetTreasuryProposals() ([]TreasuryProposal, error) {
+	approved := make(map[uint32]bool)
+	value, err := c.GetStorage(StorageKey("Treasury", "Approvals"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("get approvals: %w", err)
+	}
+	if data, err := DecodeHex(value); err == nil && len(data) > 0 {
+		n, offset := decodeCompactInteger(data)
+		for i := uint64(0); i < n && offset+4 <= len(data); i++ {
+			approved[binary.LittleEndian.Uint32(data[offset:])] = true
+			offset += 4
+		}
+	}
+
+	var proposals []TreasuryProposal
+	err = c.readStorageMap("Treasury", "Proposals", func(index uint32, value interface{}) {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		proposal := TreasuryProposal{Index: index, Approved: approved[index]}
+		proposal.Value, _ = balanceString(fields["value"])
+		if who, ok := fields["beneficiary"].(AccountValue); ok {
+			proposal.Beneficiary = string(who)
+		}
+		proposals = append(proposals, proposal)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return proposals, nil
+}
+
+// readStorageMap decodes every value of a map keyed by a Twox64Concat u32
+func (c *Client) readStorageMap(pallet, item string, fn func(index uint32, value interface{})) error {
+	metadata := c.runtimeMetadata()
+	if metadata == nil || metadata.Version < 14 {
+		return fmt.Errorf("storage maps require metadata v14")
+	}
+	meta := &metadata.AsMetadataV14
+
+	typeID, ok := c.storageMapValueType(pallet, item)
+	if !ok {
+		return fmt.Errorf("%s.%s not found in metadata", pallet, item)
+	}
+
+	blockHash, err := c.GetBlockHash(nil)
+	if err != nil {
+		return fmt.Errorf("get block hash: %w", err)
+	}
+
+	prefix := StorageKey(pallet, item)
+	var keys []string
+	startKey := prefix
+	for {
+		page, err := c.GetKeysPaged(prefix, keysPageSize, startKey, &blockHash)
+		if err != nil {
+			return fmt.Errorf("list %s.%s keys: %w", pallet, item, err)
+		}
+		keys = append(keys, page...)
+		if len(page) < keysPageSize {
+			break
+		}
+		startKey = page[len(page)-1]
+	}
+
+	values, err := c.QueryStorageAt(keys, &blockHash)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		raw, err := DecodeHex(key)
+		if err != nil || len(raw) < 4 {
+			continue
+		}
+		data, err := DecodeHex(values[key])
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		value, err := newCallDecoder(meta, c.accountIDToSS58, data).decodeValue(typeID, 0)
+		if err != nil {
+			return fmt.Errorf("decode %s.%s: %w", pallet, item, err)
+		}
+		fn(binary.LittleEndian.Uint32(raw[len(raw)-4:]), value)
+	}
+	return nil
+}
+
+func (c *Client) storageMapValueType(pallet, item string) (int64, bool) {
+	metadata := c.runtimeMetadata()
+	if metadata == nil || metadata.Version < 14 {
+		return 0, false
+	}
+	for _, p := range metadata.AsMetadataV14.Pallets {
+		if string(p.Name) != pallet || !p.HasStorage {
+			continue
+		}
+		for _, entry := range p.Storage.Items {
+			if string(entry.Name) == item && entry.Type.IsMap {
+				return entry.Type.AsMap.Value.Int64(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// HasPallet reports whether the runtime includes the named pallet
+func (c *Client) HasPallet(name string) bool {
+	metadata := c.runtimeMetadata()
+	return metadata != nil && metadata.Version == 14 && hasPallet(&metadata.AsMetadataV14, name)
+}
+
+// SpendRequests returns the Treasury spends requested anywhere in the call tree
+func (pc *ProposalCall) SpendRequests() []SpendRequest {
+	if pc == nil || pc.Call == nil {
+		return nil
+	}
+	var requests []SpendRequest
+	collectSpendRequests(pc.Call, &requests)
+	return requests
+}
+
+func collectSpendRequests(call *DecodedCall, out *[]SpendRequest) {
+	if call.Pallet == "Treasury" && (call.Call == "spend" || call.Call == "spend_local") {
+		request := SpendRequest{Call: call.Call}
+		if arg, ok := call.Arg("amount"); ok {
+			request.Amount, _ = balanceString(arg.Value)
+		}
+		if arg, ok := call.Arg("beneficiary"); ok {
+			request.Beneficiary = arg.Value
+		}
+		if arg, ok := call.Arg("asset_kind"); ok {
+			request.AssetKind = arg.Value
+		}
+		if request.Amount != "" {
+			*out = append(*out, request)
+		}
+	}
+	for _, nested := range call.NestedCalls() {
+		collectSpendRequests(nested, out)
+	}
+}
+
+// MatchesSpend reports whether a Treasury.spend request created the spend
+func (r SpendRequest) MatchesSpend(spend TreasurySpend) bool {
+	return r.Call == "spend" &&
+		r.Amount == spend.Amount &&
+		sameValue(r.Beneficiary, spend.Beneficiary) &&
+		sameValue(r.AssetKind, spend.AssetKind)
+}
+
+// MatchesProposal reports whether a Treasury.spend_local request created the proposal
+func (r SpendRequest) MatchesProposal(proposal TreasuryProposal) bool {
+	return r.Call == "spend_local" &&
+		r.Amount == proposal.Value &&
+		lookupAccount(r.Beneficiary) == proposal.Beneficiary
+}
+
+// sameValue compares decoded values by their JSON form, so a persisted call
+// tree matches freshly decoded storage
+func sameValue(a, b interface{}) bool {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(left) == string(right)
+}
+
+// lookupAccount resolves a decoded AccountIdLookup (MultiAddress) argument
+func lookupAccount(v interface{}) string {
+	switch val := v.(type) {
+	case AccountValue:
+		return string(val)
+	case string:
+		return val
+	case map[string]interface{}:
+		return lookupAccount(val["Id"])
+	}
+	return ""
+}
+
+// DescribeSpendAmount resolves a spend's asset and formats the amount in its
+// units; decimals and symbol describe the native token
+func DescribeSpendAmount(assetKind interface{}, amount string, decimals uint8, symbol string) (asset, display string) {
+	info, known := resolveAsset(assetKind, assetInfo{symbol: symbol, decimals: decimals})
+	if !known {
+		return info.symbol, amount + " " + info.symbol
+	}
+	return info.symbol, FormatTokenAmount(amount, info.decimals, info.symbol)
+}
+
+// SpendBeneficiary returns the SS58 address a decoded beneficiary pays to,
+// whether a plain account, a MultiAddress or an XCM location
+func (c *Client) SpendBeneficiary(v interface{}) string {
+	var address string
+	walkValue(v, func(inner interface{}) {
+		if address != "" {
+			return
+		}
+		switch val := inner.(type) {
+		case AccountValue:
+			address = string(val)
+		case map[string]interface{}:
+			if id, ok := val["Id"].(string); ok {
+				address = id
+				return
+			}
+			junction, ok := val["AccountId32"].(map[string]interface{})
+			if !ok {
+				return
+			}
+			id, _ := junction["id"].(string)
+			raw, err := DecodeHex(id)
+			if err != nil || len(raw) != 32 {
+				return
+			}
+			var accountID types.AccountID
+			copy(accountID[:], raw)
+			address = c.accountIDToSS58(accountID)
+		}
+	})
+	return address
+}
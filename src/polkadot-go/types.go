@@ -47,6 +47,7 @@ type ReferendumInfo struct {
 	Origin          string
 	Proposal        string // Preimage hash
 	ProposalLen     uint32 // Preimage length
+	ProposalInline  []byte // Call data for inline proposals
 	Enactment       string
 	Submitted       uint32
 	Submission      Submission
@@ -80,12 +81,16 @@ type DecisionStatus struct {
 	Confirming *uint32
 }
 
-// Tally represents vote counts
+// Tally represents vote counts. Conviction tallies are in planck; ranked
+// collective tallies are rank-weighted votes, with Support holding the
+// number of members voting aye.
 type Tally struct {
 	Ayes     string
 	Nays     string
 	Support  string
 	Approval string // Calculated percentage
+	BareAyes string // Ranked tallies only
+	Ranked   bool
 }
 
 // BoundedCall represents a proposal
@@ -106,4 +111,6 @@ type TrackInfo struct {
 	MinEnactmentPeriod uint32
 	MinApproval        string
 	MinSupport         string
+	ApprovalCurve      *Curve
+	SupportCurve       *Curve
 }